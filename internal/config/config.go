@@ -40,8 +40,19 @@ type Config struct {
 	RedisWriteTimeout time.Duration
 
 	// Cache
-	CacheTTL       time.Duration
-	CacheNamespace string
+	CacheTTL               time.Duration
+	CacheNamespace         string
+	RepositoryCacheEnabled bool // Cache-aside decorators over PostgreSQL repositories
+	LocalCacheEnabled      bool // In-process LRU tier in front of Redis
+	LocalCacheSize         int
+	LocalCacheTTL          time.Duration
+
+	// Outbox
+	OutboxEnabled       bool // Write domain events to exchange.outbox with each mutation
+	OutboxBatchSize     int
+	OutboxRelayInterval time.Duration
+	OutboxMaxAttempts   int // Publish attempts before an event is dead-lettered
+	EventStreamMaxLen   int // Approximate maximum entries per Redis event stream
 
 	// Service Discovery
 	ServiceDiscoveryNamespace string
@@ -90,6 +101,15 @@ func LoadConfig() (*Config, error) {
 		RedisWriteTimeout:         getEnvDuration("REDIS_WRITE_TIMEOUT", 3*time.Second),
 		CacheTTL:                  getEnvDuration("CACHE_TTL", 300*time.Second),
 		CacheNamespace:            getEnv("CACHE_NAMESPACE", "exchange"),
+		RepositoryCacheEnabled:    getEnvBool("REPOSITORY_CACHE_ENABLED", false),
+		LocalCacheEnabled:         getEnvBool("LOCAL_CACHE_ENABLED", false),
+		LocalCacheSize:            getEnvInt("LOCAL_CACHE_SIZE", 10000),
+		LocalCacheTTL:             getEnvDuration("LOCAL_CACHE_TTL", 5*time.Second),
+		OutboxEnabled:             getEnvBool("OUTBOX_ENABLED", false),
+		OutboxBatchSize:           getEnvInt("OUTBOX_BATCH_SIZE", 100),
+		OutboxRelayInterval:       getEnvDuration("OUTBOX_RELAY_INTERVAL", time.Second),
+		OutboxMaxAttempts:         getEnvInt("OUTBOX_MAX_ATTEMPTS", 10),
+		EventStreamMaxLen:         getEnvInt("EVENT_STREAM_MAX_LEN", 100000),
 		ServiceDiscoveryNamespace: getEnv("SERVICE_DISCOVERY_NAMESPACE", "exchange"),
 		HeartbeatInterval:         getEnvDuration("HEARTBEAT_INTERVAL", 30*time.Second),
 		ServiceTTL:                getEnvDuration("SERVICE_TTL", 90*time.Second),
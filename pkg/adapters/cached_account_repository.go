@@ -0,0 +1,80 @@
+package adapters
+
+import (
+	"context"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/typedcache"
+	"github.com/sirupsen/logrus"
+)
+
+// CachedAccountRepository caches account reads in Redis and invalidates them on every write.
+// Methods that are not overridden pass through to the wrapped repository.
+type CachedAccountRepository struct {
+	interfaces.AccountRepository
+	cache  *typedcache.TypedCache[*models.Account]
+	ttl    time.Duration
+	logger *logrus.Logger
+}
+
+func NewCachedAccountRepository(repo interfaces.AccountRepository, cache interfaces.CacheRepository, ttl time.Duration, logger *logrus.Logger) interfaces.AccountRepository {
+	return &CachedAccountRepository{
+		AccountRepository: repo,
+		cache:             typedcache.New[*models.Account](cache, typedcache.Options{}, logger),
+		ttl:               ttl,
+		logger:            logger,
+	}
+}
+
+func accountCacheKey(accountID string) string {
+	return "repo:account:" + accountID
+}
+
+func (r *CachedAccountRepository) GetByID(ctx context.Context, accountID string) (*models.Account, error) {
+	return r.cache.GetOrLoad(ctx, accountCacheKey(accountID), r.ttl, func(ctx context.Context) (*models.Account, error) {
+		return r.AccountRepository.GetByID(ctx, accountID)
+	})
+}
+
+func (r *CachedAccountRepository) Update(ctx context.Context, account *models.Account) error {
+	if err := r.AccountRepository.Update(ctx, account); err != nil {
+		return err
+	}
+	r.invalidate(ctx, account.AccountID)
+	return nil
+}
+
+func (r *CachedAccountRepository) UpdateStatus(ctx context.Context, accountID string, status models.AccountStatus) error {
+	if err := r.AccountRepository.UpdateStatus(ctx, accountID, status); err != nil {
+		return err
+	}
+	r.invalidate(ctx, accountID)
+	return nil
+}
+
+func (r *CachedAccountRepository) Delete(ctx context.Context, accountID string) error {
+	if err := r.AccountRepository.Delete(ctx, accountID); err != nil {
+		return err
+	}
+	r.invalidate(ctx, accountID)
+	return nil
+}
+
+func (r *CachedAccountRepository) Restore(ctx context.Context, accountID string) error {
+	if err := r.AccountRepository.Restore(ctx, accountID); err != nil {
+		return err
+	}
+	r.invalidate(ctx, accountID)
+	return nil
+}
+
+// invalidate deletes the cached account once the write's transaction, if any, has committed
+func (r *CachedAccountRepository) invalidate(ctx context.Context, accountID string) {
+	afterCommit(ctx, func() {
+		if err := r.cache.Delete(ctx, accountCacheKey(accountID)); err != nil {
+			r.logger.WithError(err).WithField("account_id", accountID).Warn("Failed to invalidate cached account")
+		}
+	})
+}
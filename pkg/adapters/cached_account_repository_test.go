@@ -0,0 +1,79 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// softDeleteAccountRepository stands in for the Postgres repository's close and restore behaviour
+type softDeleteAccountRepository struct {
+	interfaces.AccountRepository
+	account *models.Account
+	reads   int
+}
+
+func (s *softDeleteAccountRepository) GetByID(ctx context.Context, accountID string) (*models.Account, error) {
+	s.reads++
+	if s.account.DeletedAt != nil {
+		return nil, fmt.Errorf("account not found: %s", accountID)
+	}
+	account := *s.account
+	return &account, nil
+}
+
+func (s *softDeleteAccountRepository) Delete(ctx context.Context, accountID string) error {
+	now := time.Now()
+	s.account.Status = models.AccountStatusClosed
+	s.account.DeletedAt = &now
+	return nil
+}
+
+func (s *softDeleteAccountRepository) Restore(ctx context.Context, accountID string) error {
+	s.account.DeletedAt = nil
+	return nil
+}
+
+// TestCachedAccountRepositorySoftDelete tests that closing and restoring invalidate cached accounts
+func TestCachedAccountRepositorySoftDelete(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx := context.Background()
+
+	underlying := &softDeleteAccountRepository{account: &models.Account{
+		AccountID: "acc-1", UserID: "user-1", Status: models.AccountStatusActive,
+	}}
+	cache := &memoryCacheRepository{values: map[string]string{}}
+	repo := NewCachedAccountRepository(underlying, cache, time.Minute, logger)
+
+	if _, err := repo.GetByID(ctx, "acc-1"); err != nil {
+		t.Fatalf("GetByID() unexpected error: %v", err)
+	}
+
+	if err := repo.Delete(ctx, "acc-1"); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, "acc-1"); err == nil {
+		t.Error("GetByID() served a closed account from the cache")
+	}
+
+	if err := repo.Restore(ctx, "acc-1"); err != nil {
+		t.Fatalf("Restore() unexpected error: %v", err)
+	}
+	account, err := repo.GetByID(ctx, "acc-1")
+	if err != nil {
+		t.Fatalf("GetByID() after Restore unexpected error: %v", err)
+	}
+	if account.Status != models.AccountStatusClosed || account.DeletedAt != nil {
+		t.Errorf("restored account = %+v, expected CLOSED and not deleted", account)
+	}
+	if underlying.reads != 3 {
+		t.Errorf("underlying reads = %d, expected 3", underlying.reads)
+	}
+}
@@ -0,0 +1,121 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/typedcache"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// CachedBalanceRepository caches balance reads by account and symbol, and the per-account
+// balance list. Every write deletes both before it runs and again once it has committed, so a
+// read racing the write cannot leave the old balance cached. Methods that are not overridden
+// pass through.
+type CachedBalanceRepository struct {
+	interfaces.BalanceRepository
+	balances *typedcache.TypedCache[*models.Balance]
+	accounts *typedcache.TypedCache[[]*models.Balance]
+	ttl      time.Duration
+	logger   *logrus.Logger
+}
+
+func NewCachedBalanceRepository(repo interfaces.BalanceRepository, cache interfaces.CacheRepository, ttl time.Duration, logger *logrus.Logger) interfaces.BalanceRepository {
+	return &CachedBalanceRepository{
+		BalanceRepository: repo,
+		balances:          typedcache.New[*models.Balance](cache, typedcache.Options{}, logger),
+		accounts:          typedcache.New[[]*models.Balance](cache, typedcache.Options{}, logger),
+		ttl:               ttl,
+		logger:            logger,
+	}
+}
+
+func balanceCacheKey(accountID, symbol string) string {
+	return "repo:balance:" + accountID + ":" + symbol
+}
+
+func accountBalancesCacheKey(accountID string) string {
+	return "repo:balances:" + accountID
+}
+
+func (r *CachedBalanceRepository) GetByAccountAndSymbol(ctx context.Context, accountID, symbol string) (*models.Balance, error) {
+	return r.balances.GetOrLoad(ctx, balanceCacheKey(accountID, symbol), r.ttl, func(ctx context.Context) (*models.Balance, error) {
+		return r.BalanceRepository.GetByAccountAndSymbol(ctx, accountID, symbol)
+	})
+}
+
+func (r *CachedBalanceRepository) GetByAccount(ctx context.Context, accountID string) ([]*models.Balance, error) {
+	return r.accounts.GetOrLoad(ctx, accountBalancesCacheKey(accountID), r.ttl, func(ctx context.Context) ([]*models.Balance, error) {
+		return r.BalanceRepository.GetByAccount(ctx, accountID)
+	})
+}
+
+func (r *CachedBalanceRepository) Upsert(ctx context.Context, balance *models.Balance) error {
+	r.evict(ctx, balance.AccountID, balance.Symbol)
+	if err := r.BalanceRepository.Upsert(ctx, balance); err != nil {
+		return err
+	}
+	r.invalidate(ctx, balance.AccountID, balance.Symbol)
+	return nil
+}
+
+func (r *CachedBalanceRepository) UpdateAvailableBalance(ctx context.Context, balanceID string, availableBalance, lockedBalance decimal.Decimal) error {
+	// Resolve the account and symbol behind the balance ID before writing; a write whose
+	// cache keys are unknown could not be invalidated
+	balance, err := r.BalanceRepository.GetByID(ctx, balanceID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve balance for cache invalidation: %w", err)
+	}
+
+	r.evict(ctx, balance.AccountID, balance.Symbol)
+	if err := r.BalanceRepository.UpdateAvailableBalance(ctx, balanceID, availableBalance, lockedBalance); err != nil {
+		return err
+	}
+	r.invalidate(ctx, balance.AccountID, balance.Symbol)
+	return nil
+}
+
+func (r *CachedBalanceRepository) AtomicUpdate(ctx context.Context, accountID, symbol string, availableDelta, lockedDelta decimal.Decimal) error {
+	r.evict(ctx, accountID, symbol)
+	if err := r.BalanceRepository.AtomicUpdate(ctx, accountID, symbol, availableDelta, lockedDelta); err != nil {
+		return err
+	}
+	r.invalidate(ctx, accountID, symbol)
+	return nil
+}
+
+func (r *CachedBalanceRepository) Transfer(ctx context.Context, transfer *models.Transfer) error {
+	r.evict(ctx, transfer.FromAccountID, transfer.Symbol)
+	r.evict(ctx, transfer.ToAccountID, transfer.Symbol)
+	if err := r.BalanceRepository.Transfer(ctx, transfer); err != nil {
+		return err
+	}
+	r.invalidate(ctx, transfer.FromAccountID, transfer.Symbol)
+	r.invalidate(ctx, transfer.ToAccountID, transfer.Symbol)
+	return nil
+}
+
+// invalidate evicts the cached balance and account list once the write's transaction, if
+// any, has committed
+func (r *CachedBalanceRepository) invalidate(ctx context.Context, accountID, symbol string) {
+	afterCommit(ctx, func() {
+		r.evict(ctx, accountID, symbol)
+	})
+}
+
+// evict deletes the cached balance and account list
+func (r *CachedBalanceRepository) evict(ctx context.Context, accountID, symbol string) {
+	if err := r.balances.Delete(ctx, balanceCacheKey(accountID, symbol)); err != nil {
+		r.logger.WithError(err).WithFields(logrus.Fields{
+			"account_id": accountID,
+			"symbol":     symbol,
+		}).Warn("Failed to invalidate cached balance")
+	}
+	if err := r.accounts.Delete(ctx, accountBalancesCacheKey(accountID)); err != nil {
+		r.logger.WithError(err).WithField("account_id", accountID).Warn("Failed to invalidate cached account balances")
+	}
+}
@@ -0,0 +1,231 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// memoryCacheRepository is an in-process CacheRepository for decorator tests
+type memoryCacheRepository struct {
+	interfaces.CacheRepository
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func (m *memoryCacheRepository) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = string(value.([]byte))
+	return nil
+}
+
+func (m *memoryCacheRepository) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.values[key]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", interfaces.ErrCacheMiss, key)
+	}
+	return value, nil
+}
+
+func (m *memoryCacheRepository) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.values, key)
+	return nil
+}
+
+func (m *memoryCacheRepository) MGet(ctx context.Context, keys []string) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if value, ok := m.values[key]; ok {
+			result[key] = value
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryCacheRepository) MSet(ctx context.Context, values map[string]interface{}, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, value := range values {
+		m.values[key] = value.(string)
+	}
+	return nil
+}
+
+// countingBalanceRepository serves a single balance and counts reads
+type countingBalanceRepository struct {
+	interfaces.BalanceRepository
+	balance *models.Balance
+	reads   int
+	writes  int
+	getErr  error
+}
+
+func (c *countingBalanceRepository) GetByID(ctx context.Context, balanceID string) (*models.Balance, error) {
+	if c.getErr != nil {
+		return nil, c.getErr
+	}
+	copied := *c.balance
+	return &copied, nil
+}
+
+func (c *countingBalanceRepository) UpdateAvailableBalance(ctx context.Context, balanceID string, availableBalance, lockedBalance decimal.Decimal) error {
+	c.writes++
+	c.balance.AvailableBalance = availableBalance
+	c.balance.LockedBalance = lockedBalance
+	return nil
+}
+
+func (c *countingBalanceRepository) GetByAccountAndSymbol(ctx context.Context, accountID, symbol string) (*models.Balance, error) {
+	c.reads++
+	copied := *c.balance
+	return &copied, nil
+}
+
+func (c *countingBalanceRepository) AtomicUpdate(ctx context.Context, accountID, symbol string, availableDelta, lockedDelta decimal.Decimal) error {
+	c.balance.AvailableBalance = c.balance.AvailableBalance.Add(availableDelta)
+	return nil
+}
+
+func (c *countingBalanceRepository) Transfer(ctx context.Context, transfer *models.Transfer) error {
+	if transfer.FromAccountID == c.balance.AccountID {
+		c.balance.AvailableBalance = c.balance.AvailableBalance.Sub(transfer.Amount)
+	}
+	return nil
+}
+
+// TestCachedBalanceRepository tests cache-aside reads and invalidation on write
+func TestCachedBalanceRepository(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx := context.Background()
+
+	underlying := &countingBalanceRepository{balance: &models.Balance{
+		BalanceID: "bal-1", AccountID: "acc-1", Symbol: "USD", AvailableBalance: decimal.NewFromInt(100),
+	}}
+	cache := &memoryCacheRepository{values: map[string]string{}}
+	repo := NewCachedBalanceRepository(underlying, cache, time.Minute, logger)
+
+	for i := 0; i < 3; i++ {
+		balance, err := repo.GetByAccountAndSymbol(ctx, "acc-1", "USD")
+		if err != nil {
+			t.Fatalf("GetByAccountAndSymbol() unexpected error: %v", err)
+		}
+		if !balance.AvailableBalance.Equal(decimal.NewFromInt(100)) {
+			t.Errorf("available = %s, expected 100", balance.AvailableBalance)
+		}
+	}
+	if underlying.reads != 1 {
+		t.Errorf("underlying reads = %d, expected 1", underlying.reads)
+	}
+
+	if err := repo.AtomicUpdate(ctx, "acc-1", "USD", decimal.NewFromInt(-40), decimal.Zero); err != nil {
+		t.Fatalf("AtomicUpdate() unexpected error: %v", err)
+	}
+
+	balance, err := repo.GetByAccountAndSymbol(ctx, "acc-1", "USD")
+	if err != nil {
+		t.Fatalf("GetByAccountAndSymbol() unexpected error: %v", err)
+	}
+	if !balance.AvailableBalance.Equal(decimal.NewFromInt(60)) {
+		t.Errorf("available after update = %s, expected 60", balance.AvailableBalance)
+	}
+	if underlying.reads != 2 {
+		t.Errorf("underlying reads = %d, expected 2 after invalidation", underlying.reads)
+	}
+}
+
+// TestCachedBalanceRepositoryTransfer tests that transfers invalidate the source balance
+func TestCachedBalanceRepositoryTransfer(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx := context.Background()
+
+	underlying := &countingBalanceRepository{balance: &models.Balance{
+		BalanceID: "bal-1", AccountID: "master", Symbol: "USD", AvailableBalance: decimal.NewFromInt(100),
+	}}
+	cache := &memoryCacheRepository{values: map[string]string{}}
+	repo := NewCachedBalanceRepository(underlying, cache, time.Minute, logger)
+
+	if _, err := repo.GetByAccountAndSymbol(ctx, "master", "USD"); err != nil {
+		t.Fatalf("GetByAccountAndSymbol() unexpected error: %v", err)
+	}
+	err := repo.Transfer(ctx, &models.Transfer{
+		TransferID: "tr-1", FromAccountID: "master", ToAccountID: "sub-1", Symbol: "USD", Amount: decimal.NewFromInt(25),
+	})
+	if err != nil {
+		t.Fatalf("Transfer() unexpected error: %v", err)
+	}
+
+	balance, err := repo.GetByAccountAndSymbol(ctx, "master", "USD")
+	if err != nil {
+		t.Fatalf("GetByAccountAndSymbol() unexpected error: %v", err)
+	}
+	if !balance.AvailableBalance.Equal(decimal.NewFromInt(75)) {
+		t.Errorf("available after transfer = %s, expected 75", balance.AvailableBalance)
+	}
+}
+
+// TestCachedBalanceRepositoryUpdateAvailable tests that UpdateAvailableBalance resolves its
+// cache keys before writing and refuses to write when it cannot
+func TestCachedBalanceRepositoryUpdateAvailable(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx := context.Background()
+	resolveErr := errors.New("connection reset")
+
+	tests := []struct {
+		name           string
+		getErr         error
+		expectedWrites int
+		expected       int64
+	}{
+		{name: "keys resolved", expectedWrites: 1, expected: 70},
+		{name: "keys unresolved", getErr: resolveErr, expected: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			underlying := &countingBalanceRepository{balance: &models.Balance{
+				BalanceID: "bal-1", AccountID: "acc-1", Symbol: "USD", AvailableBalance: decimal.NewFromInt(100),
+			}}
+			cache := &memoryCacheRepository{values: map[string]string{}}
+			repo := NewCachedBalanceRepository(underlying, cache, time.Minute, logger)
+
+			if _, err := repo.GetByAccountAndSymbol(ctx, "acc-1", "USD"); err != nil {
+				t.Fatalf("GetByAccountAndSymbol() unexpected error: %v", err)
+			}
+			underlying.getErr = tt.getErr
+			err := repo.UpdateAvailableBalance(ctx, "bal-1", decimal.NewFromInt(70), decimal.Zero)
+			if !errors.Is(err, tt.getErr) {
+				t.Errorf("UpdateAvailableBalance() error = %v, expected %v", err, tt.getErr)
+			}
+			if underlying.writes != tt.expectedWrites {
+				t.Errorf("underlying writes = %d, expected %d", underlying.writes, tt.expectedWrites)
+			}
+
+			balance, err := repo.GetByAccountAndSymbol(ctx, "acc-1", "USD")
+			if err != nil {
+				t.Fatalf("GetByAccountAndSymbol() unexpected error: %v", err)
+			}
+			if !balance.AvailableBalance.Equal(decimal.NewFromInt(tt.expected)) {
+				t.Errorf("cached available = %s, expected %d", balance.AvailableBalance, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package adapters
+
+import (
+	"context"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/typedcache"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// CachedOrderRepository caches order reads in Redis and invalidates them on every write.
+// Methods that are not overridden pass through to the wrapped repository.
+type CachedOrderRepository struct {
+	interfaces.OrderRepository
+	cache  *typedcache.TypedCache[*models.Order]
+	ttl    time.Duration
+	logger *logrus.Logger
+}
+
+func NewCachedOrderRepository(repo interfaces.OrderRepository, cache interfaces.CacheRepository, ttl time.Duration, logger *logrus.Logger) interfaces.OrderRepository {
+	return &CachedOrderRepository{
+		OrderRepository: repo,
+		cache:           typedcache.New[*models.Order](cache, typedcache.Options{}, logger),
+		ttl:             ttl,
+		logger:          logger,
+	}
+}
+
+func orderCacheKey(orderID string) string {
+	return "repo:order:" + orderID
+}
+
+func (r *CachedOrderRepository) GetByID(ctx context.Context, orderID string) (*models.Order, error) {
+	return r.cache.GetOrLoad(ctx, orderCacheKey(orderID), r.ttl, func(ctx context.Context) (*models.Order, error) {
+		return r.OrderRepository.GetByID(ctx, orderID)
+	})
+}
+
+func (r *CachedOrderRepository) UpdateStatus(ctx context.Context, orderID string, status models.OrderStatus) error {
+	if err := r.OrderRepository.UpdateStatus(ctx, orderID, status); err != nil {
+		return err
+	}
+	r.invalidate(ctx, orderID)
+	return nil
+}
+
+func (r *CachedOrderRepository) UpdateFilled(ctx context.Context, orderID string, filledQuantity, averagePrice decimal.Decimal) error {
+	if err := r.OrderRepository.UpdateFilled(ctx, orderID, filledQuantity, averagePrice); err != nil {
+		return err
+	}
+	r.invalidate(ctx, orderID)
+	return nil
+}
+
+func (r *CachedOrderRepository) Cancel(ctx context.Context, orderID string) error {
+	if err := r.OrderRepository.Cancel(ctx, orderID); err != nil {
+		return err
+	}
+	r.invalidate(ctx, orderID)
+	return nil
+}
+
+// invalidate deletes the cached order once the write's transaction, if any, has committed
+func (r *CachedOrderRepository) invalidate(ctx context.Context, orderID string) {
+	afterCommit(ctx, func() {
+		if err := r.cache.Delete(ctx, orderCacheKey(orderID)); err != nil {
+			r.logger.WithError(err).WithField("order_id", orderID).Warn("Failed to invalidate cached order")
+		}
+	})
+}
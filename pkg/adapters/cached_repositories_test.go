@@ -0,0 +1,117 @@
+package adapters
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+)
+
+// declaredMethods returns the names of the methods file declares on *receiver
+func declaredMethods(t *testing.T, file, receiver string) map[string]bool {
+	t.Helper()
+	parsed, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", file, err)
+	}
+
+	methods := map[string]bool{}
+	for _, decl := range parsed.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 {
+			continue
+		}
+		star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		if ident, ok := star.X.(*ast.Ident); ok && ident.Name == receiver {
+			methods[fn.Name.Name] = true
+		}
+	}
+	return methods
+}
+
+// TestCachedRepositoriesCoverWrites tests that every repository method is either overridden by
+// its caching decorator or listed here as safe to pass through. The decorators embed their
+// repository interface, so a write method added to it would otherwise reach the database
+// without invalidating the cache; adding one fails this test until it is classified.
+func TestCachedRepositoriesCoverWrites(t *testing.T) {
+	tests := []struct {
+		decorator   string
+		file        string
+		repository  reflect.Type
+		passThrough []string
+	}{
+		{
+			decorator:  "CachedAccountRepository",
+			file:       "cached_account_repository.go",
+			repository: reflect.TypeOf((*interfaces.AccountRepository)(nil)).Elem(),
+			// Create writes an account no cached read can have seen, as misses are not cached
+			passThrough: []string{"Create", "GetByIDs", "GetByUserID", "Query", "GetSubAccounts"},
+		},
+		{
+			decorator:  "CachedOrderRepository",
+			file:       "cached_order_repository.go",
+			repository: reflect.TypeOf((*interfaces.OrderRepository)(nil)).Elem(),
+			// Create and CreateBatch write orders no cached read can have seen
+			passThrough: []string{"Create", "CreateBatch", "GetByIDs", "Query", "GetPendingByAccount",
+				"GetByAccountAndSymbol", "GetHistory", "GetAsOf"},
+		},
+		{
+			decorator:  "CachedBalanceRepository",
+			file:       "cached_balance_repository.go",
+			repository: reflect.TypeOf((*interfaces.BalanceRepository)(nil)).Elem(),
+			passThrough: []string{"GetByID", "GetByIDs", "GetMany", "Query", "GetTransfer", "GetLedger",
+				"GetHierarchyBalances"},
+		},
+		{
+			decorator:  "CachedTradeRepository",
+			file:       "cached_trade_repository.go",
+			repository: reflect.TypeOf((*interfaces.TradeRepository)(nil)).Elem(),
+			// Trades are immutable, and Create and CreateBatch write trades no cached read can have seen
+			passThrough: []string{"Create", "CreateBatch", "GetByIDs", "GetByOrderID", "Query", "GetBySymbol",
+				"GetByAccount"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.decorator, func(t *testing.T) {
+			declared := declaredMethods(t, tt.file, tt.decorator)
+			passThrough := map[string]bool{}
+			for _, name := range tt.passThrough {
+				passThrough[name] = true
+			}
+
+			var unclassified, overridden []string
+			for i := 0; i < tt.repository.NumMethod(); i++ {
+				name := tt.repository.Method(i).Name
+				switch {
+				case declared[name] && passThrough[name]:
+					overridden = append(overridden, name)
+				case !declared[name] && !passThrough[name]:
+					unclassified = append(unclassified, name)
+				}
+				delete(passThrough, name)
+			}
+			if len(unclassified) > 0 {
+				t.Errorf("%s neither overrides nor lists as pass-through %v", tt.decorator, unclassified)
+			}
+			if len(overridden) > 0 {
+				t.Errorf("%s overrides pass-through methods %v", tt.decorator, overridden)
+			}
+			var stale []string
+			for name := range passThrough {
+				stale = append(stale, name)
+			}
+			sort.Strings(stale)
+			if len(stale) > 0 {
+				t.Errorf("pass-through methods %v are not on %s", stale, tt.repository.Name())
+			}
+		})
+	}
+}
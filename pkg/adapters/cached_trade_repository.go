@@ -0,0 +1,37 @@
+package adapters
+
+import (
+	"context"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/typedcache"
+	"github.com/sirupsen/logrus"
+)
+
+// CachedTradeRepository caches trade reads in Redis. Trades are immutable once written,
+// so no invalidation is required. Methods that are not overridden pass through.
+type CachedTradeRepository struct {
+	interfaces.TradeRepository
+	cache *typedcache.TypedCache[*models.Trade]
+	ttl   time.Duration
+}
+
+func NewCachedTradeRepository(repo interfaces.TradeRepository, cache interfaces.CacheRepository, ttl time.Duration, logger *logrus.Logger) interfaces.TradeRepository {
+	return &CachedTradeRepository{
+		TradeRepository: repo,
+		cache:           typedcache.New[*models.Trade](cache, typedcache.Options{}, logger),
+		ttl:             ttl,
+	}
+}
+
+func tradeCacheKey(tradeID string) string {
+	return "repo:trade:" + tradeID
+}
+
+func (r *CachedTradeRepository) GetByID(ctx context.Context, tradeID string) (*models.Trade, error) {
+	return r.cache.GetOrLoad(ctx, tradeCacheKey(tradeID), r.ttl, func(ctx context.Context) (*models.Trade, error) {
+		return r.TradeRepository.GetByID(ctx, tradeID)
+	})
+}
@@ -0,0 +1,57 @@
+package adapters
+
+import (
+	"context"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// CandleRefresher periodically refreshes materialized candles for a set of hot symbols
+type CandleRefresher struct {
+	repo      interfaces.CandleRepository
+	symbols   []string
+	intervals []time.Duration
+	period    time.Duration
+	logger    *logrus.Logger
+}
+
+func NewCandleRefresher(repo interfaces.CandleRepository, symbols []string, intervals []time.Duration, period time.Duration, logger *logrus.Logger) *CandleRefresher {
+	return &CandleRefresher{
+		repo:      repo,
+		symbols:   symbols,
+		intervals: intervals,
+		period:    period,
+		logger:    logger,
+	}
+}
+
+// Run refreshes all configured symbol/interval pairs every period until the context is cancelled
+func (c *CandleRefresher) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.period)
+	defer ticker.Stop()
+
+	c.refreshAll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.refreshAll(ctx)
+		}
+	}
+}
+
+func (c *CandleRefresher) refreshAll(ctx context.Context) {
+	for _, symbol := range c.symbols {
+		for _, interval := range c.intervals {
+			if err := c.repo.Refresh(ctx, symbol, interval); err != nil {
+				c.logger.WithError(err).WithFields(logrus.Fields{
+					"symbol":   symbol,
+					"interval": interval.String(),
+				}).Warn("Failed to refresh candles")
+			}
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/internal/config"
 	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/internal/database"
 	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/ratelimit"
 	"github.com/sirupsen/logrus"
 )
 
@@ -18,8 +19,31 @@ type DataAdapter interface {
 	OrderRepository() interfaces.OrderRepository
 	TradeRepository() interfaces.TradeRepository
 	BalanceRepository() interfaces.BalanceRepository
+	CandleRepository() interfaces.CandleRepository
+	OutboxRepository() interfaces.OutboxRepository
+	AuditRepository() interfaces.AuditRepository
 	ServiceDiscoveryRepository() interfaces.ServiceDiscoveryRepository
 	CacheRepository() interfaces.CacheRepository
+	LockRepository() interfaces.LockRepository
+	OrderBookRepository() interfaces.OrderBookRepository
+
+	// ChangeListener delivers the orders, trades and balances LISTEN/NOTIFY change feed
+	ChangeListener() *PostgresChangeListener
+
+	// Transactor groups writes across the PostgreSQL repositories into one transaction
+	Transactor() interfaces.Transactor
+
+	// Domain events published to and consumed from Redis Streams
+	EventPublisher() interfaces.EventPublisher
+
+	// OutboxRelay publishes outbox events to the EventPublisher; RunOutboxRelay runs it on one
+	// replica at a time under a LeaderElector until ctx is done
+	OutboxRelay() (*OutboxRelay, error)
+	RunOutboxRelay(ctx context.Context) error
+	EventStreamConsumer(category, group, consumer string, options StreamConsumerOptions) (*RedisStreamConsumer, error)
+
+	// RateLimiter creates a Redis-backed limiter scoped to the instance namespace
+	RateLimiter(name string, options ratelimit.Options) (*ratelimit.Limiter, error)
 
 	// Lifecycle
 	Connect(ctx context.Context) error
@@ -40,10 +64,23 @@ type ExchangeDataAdapter struct {
 	orderRepo            interfaces.OrderRepository
 	tradeRepo            interfaces.TradeRepository
 	balanceRepo          interfaces.BalanceRepository
+	candleRepo           interfaces.CandleRepository
+	outboxRepo           interfaces.OutboxRepository
+	auditRepo            interfaces.AuditRepository
+	changeListener       *PostgresChangeListener
+	transactor           interfaces.Transactor
 	serviceDiscoveryRepo interfaces.ServiceDiscoveryRepository
 	cacheRepo            interfaces.CacheRepository
+	tieredCache          *TieredCacheRepository
+	lockRepo             interfaces.LockRepository
+	orderBookRepo        interfaces.OrderBookRepository
+	eventPublisher       interfaces.EventPublisher
+	outboxRelay          *OutboxRelay
 }
 
+// outboxRelayElection is the lock name replicas campaign for to run the outbox relay
+const outboxRelayElection = "outbox-relay"
+
 // deriveSchemaName derives PostgreSQL schema name from service and instance names
 // Singleton: exchange-simulator → "exchange"
 // Multi-instance: exchange-OKX → "exchange_okx"
@@ -117,10 +154,15 @@ func NewExchangeDataAdapter(cfg *config.Config, logger *logrus.Logger) (DataAdap
 		adapter.postgresDB = postgresDB
 
 		// Initialize PostgreSQL repositories
-		adapter.accountRepo = NewPostgresAccountRepository(postgresDB.DB, logger)
-		adapter.orderRepo = NewPostgresOrderRepository(postgresDB.DB, logger)
-		adapter.tradeRepo = NewPostgresTradeRepository(postgresDB.DB, logger)
-		adapter.balanceRepo = NewPostgresBalanceRepository(postgresDB.DB, logger)
+		adapter.accountRepo = NewPostgresAccountRepository(postgresDB.DB, cfg.OutboxEnabled, logger)
+		adapter.orderRepo = NewPostgresOrderRepository(postgresDB.DB, cfg.OutboxEnabled, logger)
+		adapter.tradeRepo = NewPostgresTradeRepository(postgresDB.DB, cfg.OutboxEnabled, logger)
+		adapter.balanceRepo = NewPostgresBalanceRepository(postgresDB.DB, cfg.OutboxEnabled, logger)
+		adapter.candleRepo = NewPostgresCandleRepository(postgresDB.DB, logger)
+		adapter.outboxRepo = NewPostgresOutboxRepository(postgresDB.DB, logger)
+		adapter.auditRepo = NewPostgresAuditRepository(postgresDB.DB, logger)
+		adapter.changeListener = NewPostgresChangeListener(cfg.PostgresURL, postgresDB.DB, logger)
+		adapter.transactor = NewPostgresTransactor(postgresDB.DB, logger)
 	} else {
 		logger.Warn("PostgreSQL URL not configured, repositories will not be available")
 	}
@@ -134,12 +176,39 @@ func NewExchangeDataAdapter(cfg *config.Config, logger *logrus.Logger) (DataAdap
 		adapter.redisClient = redisClient
 
 		// Initialize Redis repositories
-		adapter.serviceDiscoveryRepo = NewRedisServiceDiscovery(redisClient.Client, cfg.ServiceDiscoveryNamespace, logger)
+		adapter.serviceDiscoveryRepo = NewRedisServiceDiscovery(redisClient.Client, cfg.ServiceDiscoveryNamespace,
+			cfg.ServiceTTL, cfg.HeartbeatInterval, logger)
 		adapter.cacheRepo = NewRedisCacheRepository(redisClient.Client, cfg.CacheNamespace, logger)
+		if cfg.LocalCacheEnabled {
+			adapter.tieredCache = NewTieredCacheRepository(adapter.cacheRepo, redisClient.Client,
+				cfg.CacheNamespace, cfg.LocalCacheSize, cfg.LocalCacheTTL, logger)
+			adapter.cacheRepo = adapter.tieredCache
+		}
+		adapter.lockRepo = NewRedisLockRepository(redisClient.Client, cfg.RedisNamespace, logger)
+		adapter.orderBookRepo = NewRedisOrderBookRepository(redisClient.Client, adapter.orderRepo, cfg.RedisNamespace, logger)
+		adapter.eventPublisher = NewRedisStreamPublisher(redisClient.Client, cfg.RedisNamespace, int64(cfg.EventStreamMaxLen), logger)
 	} else {
 		logger.Warn("Redis URL not configured, cache and service discovery will not be available")
 	}
 
+	// Relay outbox events written by the PostgreSQL repositories to Redis Streams
+	if cfg.OutboxEnabled && adapter.outboxRepo != nil && adapter.eventPublisher != nil {
+		adapter.outboxRelay = NewOutboxRelay(adapter.outboxRepo, adapter.eventPublisher, cfg.OutboxBatchSize,
+			cfg.OutboxRelayInterval, cfg.OutboxMaxAttempts, logger)
+	}
+
+	// Wrap PostgreSQL repositories with Redis cache-aside decorators
+	if cfg.RepositoryCacheEnabled {
+		if adapter.postgresDB != nil && adapter.redisClient != nil {
+			adapter.accountRepo = NewCachedAccountRepository(adapter.accountRepo, adapter.cacheRepo, cfg.CacheTTL, logger)
+			adapter.orderRepo = NewCachedOrderRepository(adapter.orderRepo, adapter.cacheRepo, cfg.CacheTTL, logger)
+			adapter.tradeRepo = NewCachedTradeRepository(adapter.tradeRepo, adapter.cacheRepo, cfg.CacheTTL, logger)
+			adapter.balanceRepo = NewCachedBalanceRepository(adapter.balanceRepo, adapter.cacheRepo, cfg.CacheTTL, logger)
+		} else {
+			logger.Warn("Repository cache enabled but PostgreSQL or Redis not configured, caching disabled")
+		}
+	}
+
 	return adapter, nil
 }
 
@@ -174,6 +243,11 @@ func (a *ExchangeDataAdapter) Connect(ctx context.Context) error {
 func (a *ExchangeDataAdapter) Disconnect(ctx context.Context) error {
 	var errors []error
 
+	// Stop local cache invalidation listener before closing Redis
+	if a.tieredCache != nil {
+		a.tieredCache.Close()
+	}
+
 	// Disconnect from PostgreSQL
 	if a.postgresDB != nil {
 		if err := a.postgresDB.Disconnect(ctx); err != nil {
@@ -231,6 +305,26 @@ func (a *ExchangeDataAdapter) BalanceRepository() interfaces.BalanceRepository {
 	return a.balanceRepo
 }
 
+func (a *ExchangeDataAdapter) CandleRepository() interfaces.CandleRepository {
+	return a.candleRepo
+}
+
+func (a *ExchangeDataAdapter) OutboxRepository() interfaces.OutboxRepository {
+	return a.outboxRepo
+}
+
+func (a *ExchangeDataAdapter) AuditRepository() interfaces.AuditRepository {
+	return a.auditRepo
+}
+
+func (a *ExchangeDataAdapter) ChangeListener() *PostgresChangeListener {
+	return a.changeListener
+}
+
+func (a *ExchangeDataAdapter) Transactor() interfaces.Transactor {
+	return a.transactor
+}
+
 func (a *ExchangeDataAdapter) ServiceDiscoveryRepository() interfaces.ServiceDiscoveryRepository {
 	return a.serviceDiscoveryRepo
 }
@@ -238,3 +332,44 @@ func (a *ExchangeDataAdapter) ServiceDiscoveryRepository() interfaces.ServiceDis
 func (a *ExchangeDataAdapter) CacheRepository() interfaces.CacheRepository {
 	return a.cacheRepo
 }
+
+func (a *ExchangeDataAdapter) LockRepository() interfaces.LockRepository {
+	return a.lockRepo
+}
+
+func (a *ExchangeDataAdapter) OrderBookRepository() interfaces.OrderBookRepository {
+	return a.orderBookRepo
+}
+
+func (a *ExchangeDataAdapter) EventPublisher() interfaces.EventPublisher {
+	return a.eventPublisher
+}
+
+func (a *ExchangeDataAdapter) OutboxRelay() (*OutboxRelay, error) {
+	if a.outboxRelay == nil {
+		return nil, fmt.Errorf("outbox relay requires OUTBOX_ENABLED, PostgreSQL and Redis")
+	}
+	return a.outboxRelay, nil
+}
+
+func (a *ExchangeDataAdapter) RunOutboxRelay(ctx context.Context) error {
+	relay, err := a.OutboxRelay()
+	if err != nil {
+		return err
+	}
+	return NewLeaderElector(a.lockRepo, outboxRelayElection, 0, a.logger).Run(ctx, relay.Run)
+}
+
+func (a *ExchangeDataAdapter) EventStreamConsumer(category, group, consumer string, options StreamConsumerOptions) (*RedisStreamConsumer, error) {
+	if a.redisClient == nil {
+		return nil, fmt.Errorf("event stream consumer %s requires Redis", group)
+	}
+	return NewRedisStreamConsumer(a.redisClient.Client, a.config.RedisNamespace, category, group, consumer, options, a.logger), nil
+}
+
+func (a *ExchangeDataAdapter) RateLimiter(name string, options ratelimit.Options) (*ratelimit.Limiter, error) {
+	if a.redisClient == nil {
+		return nil, fmt.Errorf("rate limiter %s requires Redis", name)
+	}
+	return ratelimit.NewLimiter(a.redisClient.Client, a.config.RedisNamespace, name, options, a.logger)
+}
@@ -226,3 +226,48 @@ func TestNewExchangeDataAdapter(t *testing.T) {
 		})
 	}
 }
+
+// TestOutboxRelayWiring tests that the adapter builds an outbox relay only when the outbox is
+// enabled and both PostgreSQL and Redis are configured
+func TestOutboxRelayWiring(t *testing.T) {
+	tests := []struct {
+		name          string
+		outbox        bool
+		postgresURL   string
+		redisURL      string
+		expectedRelay bool
+	}{
+		{"enabled with both stores", true, "postgres://localhost/exchange", "redis://localhost:6379/0", true},
+		{"disabled", false, "postgres://localhost/exchange", "redis://localhost:6379/0", false},
+		{"without Redis", true, "postgres://localhost/exchange", "", false},
+		{"without PostgreSQL", true, "", "redis://localhost:6379/0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := logrus.New()
+			logger.SetOutput(io.Discard)
+
+			adapter, err := NewExchangeDataAdapter(&config.Config{
+				ServiceName:         "exchange-simulator",
+				ServiceInstanceName: "exchange-simulator",
+				PostgresURL:         tt.postgresURL,
+				RedisURL:            tt.redisURL,
+				OutboxEnabled:       tt.outbox,
+				OutboxBatchSize:     50,
+				OutboxMaxAttempts:   3,
+			}, logger)
+			if err != nil {
+				t.Fatalf("NewExchangeDataAdapter failed: %v", err)
+			}
+
+			relay, err := adapter.OutboxRelay()
+			if (relay != nil) != tt.expectedRelay || (err == nil) != tt.expectedRelay {
+				t.Fatalf("OutboxRelay() = %v, %v; expected relay %v", relay, err, tt.expectedRelay)
+			}
+			if relay != nil && (relay.batchSize != 50 || relay.maxAttempts != 3) {
+				t.Errorf("relay batch size %d, max attempts %d; expected 50 and 3", relay.batchSize, relay.maxAttempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// resignTimeout bounds releasing leadership after the job returns
+const resignTimeout = 5 * time.Second
+
+const (
+	defaultLeaderTTL = 30 * time.Second
+
+	// leaseMarginDivisor sets the safety margin, a tenth of the TTL, by which the leader gives
+	// up its lease ahead of ExpiresAt to allow for clock drift and the time to stop work
+	leaseMarginDivisor = 10
+
+	// minLeaderTTL keeps the renew interval at a millisecond or more, the lock expiry resolution
+	minLeaderTTL = 3 * time.Millisecond
+)
+
+// LeaderElector campaigns for a named lock so that only one replica runs a job at a time
+type LeaderElector struct {
+	locks         interfaces.LockRepository
+	name          string
+	ttl           time.Duration
+	renewInterval time.Duration
+	margin        time.Duration
+	logger        *logrus.Logger
+}
+
+// NewLeaderElector creates an elector whose leadership lease lasts ttl and is renewed
+// and retried every third of the TTL. Leadership is given up a tenth of the TTL before the
+// lease expires unless renewed. A non-positive ttl defaults to 30s and a shorter one than
+// 3ms is raised to 3ms.
+func NewLeaderElector(locks interfaces.LockRepository, name string, ttl time.Duration, logger *logrus.Logger) *LeaderElector {
+	if ttl <= 0 {
+		ttl = defaultLeaderTTL
+	}
+	if ttl < minLeaderTTL {
+		ttl = minLeaderTTL
+	}
+	return &LeaderElector{
+		locks:         locks,
+		name:          name,
+		ttl:           ttl,
+		renewInterval: ttl / 3,
+		margin:        ttl / leaseMarginDivisor,
+		logger:        logger,
+	}
+}
+
+// Leadership is held until Lost is closed or Resign is called
+type Leadership struct {
+	lock   *interfaces.Lock
+	locks  interfaces.LockRepository
+	lost   chan struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// FencingToken identifies this term of leadership; later terms have higher tokens
+func (l *Leadership) FencingToken() int64 {
+	return l.lock.FencingToken
+}
+
+// Lost is closed when leadership could not be renewed, no later than the lease's safety margin
+// before it expires
+func (l *Leadership) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Resign stops renewing and releases the lock so another replica can take over immediately
+func (l *Leadership) Resign(ctx context.Context) error {
+	var err error
+	l.once.Do(func() {
+		l.cancel()
+		<-l.done
+		err = l.locks.Release(ctx, l.lock)
+	})
+	if errors.Is(err, interfaces.ErrLockNotHeld) {
+		return nil
+	}
+	return err
+}
+
+// Campaign blocks until leadership is acquired or ctx is cancelled
+func (e *LeaderElector) Campaign(ctx context.Context) (*Leadership, error) {
+	for {
+		lock, err := e.locks.Acquire(ctx, e.name, e.ttl)
+		if err == nil {
+			e.logger.WithFields(logrus.Fields{
+				"election":      e.name,
+				"fencing_token": lock.FencingToken,
+			}).Info("Leadership acquired")
+			return e.hold(ctx, lock), nil
+		}
+		if !errors.Is(err, interfaces.ErrLockNotAcquired) {
+			e.logger.WithError(err).WithField("election", e.name).Warn("Leader campaign attempt failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(e.renewInterval):
+		}
+	}
+}
+
+// hold renews the lock in the background until it is lost or resigned. Each renewal must
+// finish before the safety margin ahead of the lease expiry, and leadership is given up at
+// that point if none has succeeded, even while a renewal is still outstanding.
+func (e *LeaderElector) hold(ctx context.Context, lock *interfaces.Lock) *Leadership {
+	renewCtx, cancel := context.WithCancel(ctx)
+	leadership := &Leadership{
+		lock:   lock,
+		locks:  e.locks,
+		lost:   make(chan struct{}),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(leadership.done)
+
+		ticker := time.NewTicker(e.renewInterval)
+		defer ticker.Stop()
+		expiry := time.NewTimer(time.Until(lock.ExpiresAt) - e.margin)
+		defer expiry.Stop()
+
+		for {
+			select {
+			case <-renewCtx.Done():
+				return
+			case <-expiry.C:
+				e.logger.WithField("election", e.name).Warn("Leadership lost: lease expiring without renewal")
+				close(leadership.lost)
+				return
+			case <-ticker.C:
+				deadline := lock.ExpiresAt.Add(-e.margin)
+				attemptCtx, cancelAttempt := context.WithDeadline(renewCtx, deadline)
+				err := e.locks.Renew(attemptCtx, lock, e.ttl)
+				cancelAttempt()
+				if renewCtx.Err() != nil {
+					return
+				}
+				if err == nil {
+					expiry.Reset(time.Until(lock.ExpiresAt) - e.margin)
+					continue
+				}
+				// Transient errors are tolerated until the lease reaches its safety margin
+				if errors.Is(err, interfaces.ErrLockNotHeld) || !time.Now().Before(deadline) {
+					e.logger.WithError(err).WithField("election", e.name).Warn("Leadership lost")
+					close(leadership.lost)
+					return
+				}
+				e.logger.WithError(err).WithField("election", e.name).Warn("Failed to renew leadership")
+			}
+		}
+	}()
+
+	return leadership
+}
+
+// Run repeatedly campaigns and calls fn while leader. The context passed to fn is cancelled
+// when leadership is lost; Run resigns after fn returns and campaigns again until ctx is done.
+func (e *LeaderElector) Run(ctx context.Context, fn func(ctx context.Context)) error {
+	for {
+		leadership, err := e.Campaign(ctx)
+		if err != nil {
+			return err
+		}
+
+		leaderCtx, cancel := context.WithCancel(ctx)
+		go func() {
+			select {
+			case <-leadership.Lost():
+				cancel()
+			case <-leaderCtx.Done():
+			}
+		}()
+
+		fn(leaderCtx)
+		cancel()
+
+		resignCtx, cancelResign := context.WithTimeout(context.Background(), resignTimeout)
+		if err := leadership.Resign(resignCtx); err != nil {
+			e.logger.WithError(err).WithField("election", e.name).Warn("Failed to resign leadership")
+		}
+		cancelResign()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
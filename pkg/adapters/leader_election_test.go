@@ -0,0 +1,175 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// memoryLockRepository is an in-process LockRepository for election tests. With hang set,
+// Renew blocks until its context ends, as against an unresponsive Redis.
+type memoryLockRepository struct {
+	mu        sync.Mutex
+	owners    map[string]string
+	fence     map[string]int64
+	next      int
+	hang      bool
+	unbounded bool
+}
+
+func newMemoryLockRepository() *memoryLockRepository {
+	return &memoryLockRepository{owners: map[string]string{}, fence: map[string]int64{}}
+}
+
+func (m *memoryLockRepository) Acquire(ctx context.Context, name string, ttl time.Duration) (*interfaces.Lock, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, held := m.owners[name]; held {
+		return nil, interfaces.ErrLockNotAcquired
+	}
+	m.next++
+	owner := fmt.Sprintf("owner-%d", m.next)
+	m.owners[name] = owner
+	m.fence[name]++
+	return &interfaces.Lock{Name: name, Owner: owner, FencingToken: m.fence[name], ExpiresAt: time.Now().Add(ttl)}, nil
+}
+
+func (m *memoryLockRepository) Renew(ctx context.Context, lock *interfaces.Lock, ttl time.Duration) error {
+	m.mu.Lock()
+	if m.hang {
+		if _, bounded := ctx.Deadline(); !bounded {
+			m.unbounded = true
+		}
+		m.mu.Unlock()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	defer m.mu.Unlock()
+	if m.owners[lock.Name] != lock.Owner {
+		return interfaces.ErrLockNotHeld
+	}
+	lock.ExpiresAt = time.Now().Add(ttl)
+	return nil
+}
+
+func (m *memoryLockRepository) Release(ctx context.Context, lock *interfaces.Lock) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.owners[lock.Name] != lock.Owner {
+		return interfaces.ErrLockNotHeld
+	}
+	delete(m.owners, lock.Name)
+	return nil
+}
+
+// expire simulates the lock TTL running out
+func (m *memoryLockRepository) expire(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.owners, name)
+}
+
+// TestLeaderElection tests campaign exclusivity, fencing tokens and loss notification
+func TestLeaderElection(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	locks := newMemoryLockRepository()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	first := NewLeaderElector(locks, "order-expiry", 30*time.Millisecond, logger)
+	second := NewLeaderElector(locks, "order-expiry", 30*time.Millisecond, logger)
+
+	leader, err := first.Campaign(ctx)
+	if err != nil {
+		t.Fatalf("first Campaign() unexpected error: %v", err)
+	}
+
+	// The second replica cannot win while the first holds and renews the lease
+	shortCtx, cancelShort := context.WithTimeout(ctx, 100*time.Millisecond)
+	if _, err := second.Campaign(shortCtx); err == nil {
+		t.Fatal("second Campaign() acquired leadership while first was leader")
+	}
+	cancelShort()
+
+	// Losing the lock closes Lost and lets the second replica take over with a higher token
+	locks.expire("order-expiry")
+	select {
+	case <-leader.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("Lost() not closed after lock expired")
+	}
+
+	successor, err := second.Campaign(ctx)
+	if err != nil {
+		t.Fatalf("second Campaign() unexpected error: %v", err)
+	}
+	if successor.FencingToken() <= leader.FencingToken() {
+		t.Errorf("successor token %d not greater than previous %d", successor.FencingToken(), leader.FencingToken())
+	}
+
+	if err := successor.Resign(ctx); err != nil {
+		t.Fatalf("Resign() unexpected error: %v", err)
+	}
+	if _, err := first.Campaign(ctx); err != nil {
+		t.Fatalf("Campaign() after resign unexpected error: %v", err)
+	}
+}
+
+// TestLeaderElectionHungRenew tests that a renewal that never returns is bounded by the lease
+// and that leadership is given up before the lease can expire
+func TestLeaderElectionHungRenew(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	locks := newMemoryLockRepository()
+	locks.hang = true
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	leader, err := NewLeaderElector(locks, "order-expiry", 300*time.Millisecond, logger).Campaign(ctx)
+	if err != nil {
+		t.Fatalf("Campaign() unexpected error: %v", err)
+	}
+	expiresAt := leader.lock.ExpiresAt
+
+	select {
+	case <-leader.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("Lost() not closed while renewal hung")
+	}
+	if lostAt := time.Now(); !lostAt.Before(expiresAt) {
+		t.Errorf("Lost() closed %v after the lease expired", lostAt.Sub(expiresAt))
+	}
+
+	locks.mu.Lock()
+	defer locks.mu.Unlock()
+	if locks.unbounded {
+		t.Error("Renew() called without a deadline")
+	}
+}
+
+// TestLeaderElectorTTL tests that TTLs too short to tick are defaulted or raised
+func TestLeaderElectorTTL(t *testing.T) {
+	tests := []struct {
+		ttl      time.Duration
+		expected time.Duration
+	}{
+		{ttl: 0, expected: defaultLeaderTTL},
+		{ttl: -time.Second, expected: defaultLeaderTTL},
+		{ttl: time.Nanosecond, expected: minLeaderTTL},
+		{ttl: 30 * time.Millisecond, expected: 30 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		e := NewLeaderElector(newMemoryLockRepository(), "order-expiry", tt.ttl, logrus.New())
+		if e.ttl != tt.expected || e.renewInterval <= 0 {
+			t.Errorf("NewLeaderElector(%v) ttl = %v renew = %v, expected ttl %v", tt.ttl, e.ttl, e.renewInterval, tt.expected)
+		}
+	}
+}
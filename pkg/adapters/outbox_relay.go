@@ -0,0 +1,163 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// outboxGapWait is how long the relay holds back events after a gap in event IDs. Event IDs are
+// drawn when an event is inserted but become visible when its transaction commits, so a gap is
+// usually a transaction still in flight; one still open this long after the relay first saw it
+// is taken to have rolled back.
+const outboxGapWait = 5 * time.Second
+
+// defaultOutboxMaxAttempts is how many times an event is tried before it is dead-lettered
+const defaultOutboxMaxAttempts = 10
+
+// OutboxRelay publishes outbox events at least once. Events are published in event ID order;
+// when an event fails, later events of the same aggregate are held back until it succeeds, so
+// consumers see each aggregate's events in order. Repositories write an aggregate's events
+// while holding its row lock, and the relay waits on recent gaps in event IDs so that an
+// earlier event committing late is not overtaken. Run a single relay per database, for example
+// under a LeaderElector, since concurrent relays could interleave an aggregate's events.
+//
+// An event that fails maxAttempts times is dead-lettered: it and the rest of its aggregate stop
+// being relayed until ResetAttempts is called for it, and DeadLettered lists such events.
+type OutboxRelay struct {
+	repo        interfaces.OutboxRepository
+	publisher   interfaces.EventPublisher
+	batchSize   int
+	interval    time.Duration
+	maxAttempts int
+	gapWait     time.Duration
+	now         func() time.Time
+	logger      *logrus.Logger
+
+	// highWater is the highest event ID seen; IDs below it are either fetched already or gaps
+	// that were waited out. Zero until the first event is seen.
+	highWater int64
+
+	// gapSeen is when the relay first saw the gap above highWater, read from the relay's own
+	// monotonic clock rather than the database's transaction timestamps. Zero with no gap open.
+	gapSeen time.Time
+}
+
+// NewOutboxRelay creates a relay; non-positive batchSize, interval and maxAttempts default to
+// 100 events, one second and 10 attempts
+func NewOutboxRelay(repo interfaces.OutboxRepository, publisher interfaces.EventPublisher, batchSize int, interval time.Duration, maxAttempts int, logger *logrus.Logger) *OutboxRelay {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = defaultOutboxMaxAttempts
+	}
+	return &OutboxRelay{
+		repo:        repo,
+		publisher:   publisher,
+		batchSize:   batchSize,
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		gapWait:     outboxGapWait,
+		now:         time.Now,
+		logger:      logger,
+	}
+}
+
+// Run relays events until the context is cancelled, polling again immediately after a full batch
+func (r *OutboxRelay) Run(ctx context.Context) {
+	for {
+		published, err := r.RelayOnce(ctx)
+		if err != nil {
+			r.logger.WithError(err).Warn("Failed to relay outbox events")
+		}
+		if err == nil && published == r.batchSize {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.interval):
+		}
+	}
+}
+
+// RelayOnce publishes one batch of unpublished events and returns how many were published
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	events, err := r.repo.FetchUnpublished(ctx, r.batchSize, r.maxAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	blocked := make(map[string]bool)
+	published := make([]int64, 0, len(events))
+	for _, event := range events {
+		if event.EventID > r.highWater {
+			if r.highWater > 0 && event.EventID > r.highWater+1 {
+				if r.gapSeen.IsZero() {
+					r.gapSeen = r.now()
+				}
+				if r.now().Sub(r.gapSeen) < r.gapWait {
+					r.logger.WithFields(logrus.Fields{
+						"after_event_id": r.highWater,
+						"event_id":       event.EventID,
+					}).Debug("Waiting on outbox event ID gap")
+					break
+				}
+			}
+			r.highWater = event.EventID
+			r.gapSeen = time.Time{}
+		}
+
+		aggregate := event.AggregateType + ":" + event.AggregateID
+		if blocked[aggregate] {
+			continue
+		}
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			blocked[aggregate] = true
+			entry := r.logger.WithError(err).WithFields(logrus.Fields{
+				"event_id":     event.EventID,
+				"event_type":   event.EventType,
+				"aggregate_id": event.AggregateID,
+				"attempts":     event.Attempts + 1,
+			})
+			if event.Attempts+1 >= r.maxAttempts {
+				entry.Error("Outbox event dead-lettered, holding back its aggregate")
+			} else {
+				entry.Warn("Failed to publish outbox event")
+			}
+			if markErr := r.repo.MarkFailed(ctx, event.EventID, err.Error()); markErr != nil {
+				r.logger.WithError(markErr).WithField("event_id", event.EventID).Warn("Failed to record outbox failure")
+			}
+			continue
+		}
+		published = append(published, event.EventID)
+	}
+
+	// A crash before this point republishes the batch, which at-least-once delivery permits
+	if err := r.repo.MarkPublished(ctx, published); err != nil {
+		return 0, fmt.Errorf("failed to acknowledge published events: %w", err)
+	}
+	return len(published), nil
+}
+
+// DeadLettered returns up to limit events that exhausted their attempts, in event ID order
+func (r *OutboxRelay) DeadLettered(ctx context.Context, limit int) ([]*models.OutboxEvent, error) {
+	return r.repo.FetchDeadLettered(ctx, limit, r.maxAttempts)
+}
+
+// ResetAttempts returns dead-lettered events, and the aggregates they hold back, to the relay
+func (r *OutboxRelay) ResetAttempts(ctx context.Context, eventIDs []int64) error {
+	return r.repo.ResetAttempts(ctx, eventIDs)
+}
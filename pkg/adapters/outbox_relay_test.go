@@ -0,0 +1,268 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// memoryOutboxRepository holds outbox events in event ID order
+type memoryOutboxRepository struct {
+	events []*models.OutboxEvent
+}
+
+func (m *memoryOutboxRepository) add(event models.DomainEvent) {
+	m.commit(event, int64(len(m.events)+1), time.Time{})
+}
+
+// commit inserts an event with a given ID, as when a transaction holding that ID commits
+func (m *memoryOutboxRepository) commit(event models.DomainEvent, eventID int64, createdAt time.Time) {
+	row, _ := models.NewOutboxEvent(event)
+	row.EventID = eventID
+	row.CreatedAt = createdAt
+	m.events = append(m.events, row)
+	sort.Slice(m.events, func(i, j int) bool { return m.events[i].EventID < m.events[j].EventID })
+}
+
+func (m *memoryOutboxRepository) find(eventID int64) *models.OutboxEvent {
+	for _, event := range m.events {
+		if event.EventID == eventID {
+			return event
+		}
+	}
+	return nil
+}
+
+func (m *memoryOutboxRepository) FetchUnpublished(ctx context.Context, limit, maxAttempts int) ([]*models.OutboxEvent, error) {
+	result := []*models.OutboxEvent{}
+	dead := make(map[string]bool)
+	for _, event := range m.events {
+		if event.PublishedAt != nil {
+			continue
+		}
+		aggregate := event.AggregateType + ":" + event.AggregateID
+		if maxAttempts > 0 && event.Attempts >= maxAttempts {
+			dead[aggregate] = true
+		}
+		if !dead[aggregate] && len(result) < limit {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryOutboxRepository) FetchDeadLettered(ctx context.Context, limit, maxAttempts int) ([]*models.OutboxEvent, error) {
+	result := []*models.OutboxEvent{}
+	for _, event := range m.events {
+		if event.PublishedAt == nil && event.Attempts >= maxAttempts && len(result) < limit {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryOutboxRepository) ResetAttempts(ctx context.Context, eventIDs []int64) error {
+	for _, id := range eventIDs {
+		m.find(id).Attempts = 0
+	}
+	return nil
+}
+
+func (m *memoryOutboxRepository) MarkPublished(ctx context.Context, eventIDs []int64) error {
+	now := time.Now()
+	for _, id := range eventIDs {
+		m.find(id).PublishedAt = &now
+	}
+	return nil
+}
+
+func (m *memoryOutboxRepository) MarkFailed(ctx context.Context, eventID int64, reason string) error {
+	m.find(eventID).Attempts++
+	m.find(eventID).LastError = &reason
+	return nil
+}
+
+func (m *memoryOutboxRepository) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// TestOutboxRelayOrdering tests that a failed event holds back later events of the same aggregate only
+func TestOutboxRelayOrdering(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx := context.Background()
+
+	repo := &memoryOutboxRepository{}
+	repo.add(&models.OrderCreated{Order: models.Order{OrderID: "order-1"}})
+	repo.add(&models.OrderCreated{Order: models.Order{OrderID: "order-2"}})
+	repo.add(&models.OrderStatusChanged{OrderID: "order-1", Status: models.OrderStatusCancelled})
+	repo.add(&models.OrderStatusChanged{OrderID: "order-2", Status: models.OrderStatusFilled})
+
+	var delivered []int64
+	failing := true
+	publisher := interfaces.EventPublisherFunc(func(ctx context.Context, event *models.OutboxEvent) error {
+		if failing && event.EventID == 1 {
+			return errors.New("broker unavailable")
+		}
+		delivered = append(delivered, event.EventID)
+		return nil
+	})
+	relay := NewOutboxRelay(repo, publisher, 10, time.Second, 0, logger)
+
+	if published, err := relay.RelayOnce(ctx); err != nil || published != 2 {
+		t.Fatalf("first RelayOnce() = %d, %v; expected 2", published, err)
+	}
+	if !reflect.DeepEqual(delivered, []int64{2, 4}) {
+		t.Errorf("delivered after failure = %v, expected [2 4]", delivered)
+	}
+	if repo.events[0].Attempts != 1 || repo.events[0].LastError == nil {
+		t.Errorf("failed event = %+v, expected one recorded attempt", repo.events[0])
+	}
+
+	failing = false
+	if published, err := relay.RelayOnce(ctx); err != nil || published != 2 {
+		t.Fatalf("second RelayOnce() = %d, %v; expected 2", published, err)
+	}
+	if !reflect.DeepEqual(delivered, []int64{2, 4, 1, 3}) {
+		t.Errorf("delivered = %v, expected order-1 events in order after recovery", delivered)
+	}
+}
+
+// TestOutboxRelayGaps tests that the relay waits on gaps in event IDs from when it first sees
+// them, regardless of the events' database timestamps, and skips gaps that outlast the wait
+func TestOutboxRelayGaps(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx := context.Background()
+	// Database timestamps far from the relay's clock must not shorten or skip the wait
+	now := time.Now().Add(-time.Hour)
+
+	repo := &memoryOutboxRepository{}
+	repo.commit(&models.OrderCreated{Order: models.Order{OrderID: "order-1"}}, 1, now)
+	repo.commit(&models.OrderCreated{Order: models.Order{OrderID: "order-2"}}, 2, now)
+	repo.commit(&models.OrderStatusChanged{OrderID: "order-1", Status: models.OrderStatusFilled}, 4, now)
+
+	var delivered []int64
+	publisher := interfaces.EventPublisherFunc(func(ctx context.Context, event *models.OutboxEvent) error {
+		delivered = append(delivered, event.EventID)
+		return nil
+	})
+	relay := NewOutboxRelay(repo, publisher, 10, time.Second, 0, logger)
+	clock := time.Now()
+	relay.now = func() time.Time { return clock }
+
+	if published, err := relay.RelayOnce(ctx); err != nil || published != 2 {
+		t.Fatalf("RelayOnce() with gap = %d, %v; expected 2", published, err)
+	}
+
+	// Event 3 commits late and is published ahead of event 4
+	repo.commit(&models.OrderStatusChanged{OrderID: "order-1", Status: models.OrderStatusPartial}, 3, now)
+	if published, err := relay.RelayOnce(ctx); err != nil || published != 2 {
+		t.Fatalf("RelayOnce() after late commit = %d, %v; expected 2", published, err)
+	}
+
+	// Event 5 never commits; event 6 is held until the gap has been open for the gap wait
+	repo.commit(&models.OrderCreated{Order: models.Order{OrderID: "order-3"}}, 6, now)
+	if published, err := relay.RelayOnce(ctx); err != nil || published != 0 {
+		t.Fatalf("RelayOnce() on new gap = %d, %v; expected 0", published, err)
+	}
+	clock = clock.Add(outboxGapWait / 2)
+	if published, err := relay.RelayOnce(ctx); err != nil || published != 0 {
+		t.Fatalf("RelayOnce() within gap wait = %d, %v; expected 0", published, err)
+	}
+	clock = clock.Add(outboxGapWait / 2)
+	if published, err := relay.RelayOnce(ctx); err != nil || published != 1 {
+		t.Fatalf("RelayOnce() after gap wait = %d, %v; expected 1", published, err)
+	}
+
+	if !reflect.DeepEqual(delivered, []int64{1, 2, 3, 4, 6}) {
+		t.Errorf("delivered = %v, expected [1 2 3 4 6]", delivered)
+	}
+}
+
+// TestOutboxRelayDeadLetter tests that an event failing maxAttempts times holds back its
+// aggregate, is listed as dead-lettered and is relayed again once its attempts are reset
+func TestOutboxRelayDeadLetter(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx := context.Background()
+
+	repo := &memoryOutboxRepository{}
+	repo.add(&models.OrderCreated{Order: models.Order{OrderID: "order-1"}})
+	repo.add(&models.OrderCreated{Order: models.Order{OrderID: "order-2"}})
+	repo.add(&models.OrderStatusChanged{OrderID: "order-1", Status: models.OrderStatusCancelled})
+
+	var delivered []int64
+	failing := true
+	publisher := interfaces.EventPublisherFunc(func(ctx context.Context, event *models.OutboxEvent) error {
+		if failing && event.EventID == 1 {
+			return errors.New("payload rejected")
+		}
+		delivered = append(delivered, event.EventID)
+		return nil
+	})
+	relay := NewOutboxRelay(repo, publisher, 10, time.Second, 2, logger)
+
+	for i := 0; i < 3; i++ {
+		if _, err := relay.RelayOnce(ctx); err != nil {
+			t.Fatalf("RelayOnce() unexpected error: %v", err)
+		}
+	}
+	if repo.events[0].Attempts != 2 {
+		t.Errorf("dead-lettered event attempts = %d, expected to stop at 2", repo.events[0].Attempts)
+	}
+	dead, err := relay.DeadLettered(ctx, 10)
+	if err != nil || len(dead) != 1 || dead[0].EventID != 1 {
+		t.Fatalf("DeadLettered() = %v, %v; expected event 1", dead, err)
+	}
+
+	failing = false
+	if err := relay.ResetAttempts(ctx, []int64{1}); err != nil {
+		t.Fatalf("ResetAttempts() unexpected error: %v", err)
+	}
+	if published, err := relay.RelayOnce(ctx); err != nil || published != 2 {
+		t.Fatalf("RelayOnce() after reset = %d, %v; expected 2", published, err)
+	}
+	if !reflect.DeepEqual(delivered, []int64{2, 1, 3}) {
+		t.Errorf("delivered = %v, expected [2 1 3]", delivered)
+	}
+}
+
+// TestOutboxEventDecode tests that typed events survive the outbox round trip
+func TestOutboxEventDecode(t *testing.T) {
+	events := []models.DomainEvent{
+		&models.OrderCreated{Order: models.Order{OrderID: "order-1", Quantity: decimal.NewFromInt(2)}},
+		&models.OrderStatusChanged{OrderID: "order-1", Status: models.OrderStatusFilled},
+		&models.TradeExecuted{Trade: models.Trade{TradeID: "trade-1", OrderID: "order-1"}},
+		&models.BalanceChanged{AccountID: "acc-1", Symbol: "USD", AvailableDelta: decimal.NewFromInt(-5)},
+		&models.AccountStatusChanged{AccountID: "acc-1", PreviousStatus: models.AccountStatusActive, Status: models.AccountStatusSuspended},
+	}
+
+	for _, event := range events {
+		t.Run(string(event.EventType()), func(t *testing.T) {
+			row, err := models.NewOutboxEvent(event)
+			if err != nil {
+				t.Fatalf("NewOutboxEvent() unexpected error: %v", err)
+			}
+			if row.AggregateID != event.AggregateID() || row.AggregateType != event.AggregateType() {
+				t.Errorf("aggregate = %s/%s, expected %s/%s", row.AggregateType, row.AggregateID, event.AggregateType(), event.AggregateID())
+			}
+			decoded, err := row.Decode()
+			if err != nil {
+				t.Fatalf("Decode() unexpected error: %v", err)
+			}
+			if decoded.EventType() != event.EventType() || decoded.AggregateID() != event.AggregateID() {
+				t.Errorf("Decode() = %+v, expected %+v", decoded, event)
+			}
+		})
+	}
+}
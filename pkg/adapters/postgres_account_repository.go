@@ -3,22 +3,39 @@ package adapters
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
 	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
 	"github.com/sirupsen/logrus"
 )
 
+// accountDeletedCode is the SQLSTATE raised by the exchange.check_account_live trigger
+const accountDeletedCode = "EX001"
+
+// accountWriteError maps the trigger rejecting a write for a deleted account to ErrAccountDeleted
+func accountWriteError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == accountDeletedCode {
+		return fmt.Errorf("%w: %s", interfaces.ErrAccountDeleted, pqErr.Message)
+	}
+	return err
+}
+
 type PostgresAccountRepository struct {
 	db     *sql.DB
+	outbox bool
 	logger *logrus.Logger
 }
 
-func NewPostgresAccountRepository(db *sql.DB, logger *logrus.Logger) interfaces.AccountRepository {
+func NewPostgresAccountRepository(db *sql.DB, outbox bool, logger *logrus.Logger) interfaces.AccountRepository {
 	return &PostgresAccountRepository{
 		db:     db,
+		outbox: outbox,
 		logger: logger,
 	}
 }
@@ -26,14 +43,19 @@ func NewPostgresAccountRepository(db *sql.DB, logger *logrus.Logger) interfaces.
 func (r *PostgresAccountRepository) Create(ctx context.Context, account *models.Account) error {
 	query := `
 		INSERT INTO exchange.accounts (
-			account_id, user_id, account_type, status, kyc_status, created_at, updated_at, metadata
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			account_id, user_id, account_type, status, kyc_status, created_at, updated_at, metadata,
+			parent_account_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		account.AccountID, account.UserID, account.AccountType, account.Status,
-		account.KYCStatus, account.CreatedAt, account.UpdatedAt, account.Metadata,
-	)
+	err := writeWithEvents(ctx, r.db, r.outbox, func(exec sqlExecutor) ([]models.DomainEvent, error) {
+		_, err := exec.ExecContext(ctx, query,
+			account.AccountID, account.UserID, account.AccountType, account.Status,
+			account.KYCStatus, account.CreatedAt, account.UpdatedAt, account.Metadata,
+			account.ParentAccountID,
+		)
+		return nil, err
+	})
 
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to create account")
@@ -45,15 +67,15 @@ func (r *PostgresAccountRepository) Create(ctx context.Context, account *models.
 
 func (r *PostgresAccountRepository) GetByID(ctx context.Context, accountID string) (*models.Account, error) {
 	query := `
-		SELECT account_id, user_id, account_type, status, kyc_status, created_at, updated_at, metadata
+		SELECT account_id, user_id, account_type, status, kyc_status, created_at, updated_at, deleted_at, parent_account_id, metadata
 		FROM exchange.accounts
-		WHERE account_id = $1
+		WHERE account_id = $1 AND deleted_at IS NULL
 	`
 
 	account := &models.Account{}
 	err := r.db.QueryRowContext(ctx, query, accountID).Scan(
 		&account.AccountID, &account.UserID, &account.AccountType, &account.Status,
-		&account.KYCStatus, &account.CreatedAt, &account.UpdatedAt, &account.Metadata,
+		&account.KYCStatus, &account.CreatedAt, &account.UpdatedAt, &account.DeletedAt, &account.ParentAccountID, &account.Metadata,
 	)
 
 	if err == sql.ErrNoRows {
@@ -67,11 +89,46 @@ func (r *PostgresAccountRepository) GetByID(ctx context.Context, accountID strin
 	return account, nil
 }
 
+func (r *PostgresAccountRepository) GetByIDs(ctx context.Context, accountIDs []string) (map[string]*models.Account, []string, error) {
+	accounts := make(map[string]*models.Account, len(accountIDs))
+	if len(accountIDs) == 0 {
+		return accounts, []string{}, nil
+	}
+
+	query := `
+		SELECT account_id, user_id, account_type, status, kyc_status, created_at, updated_at, deleted_at, parent_account_id, metadata
+		FROM exchange.accounts
+		WHERE account_id = ANY($1) AND deleted_at IS NULL
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(accountIDs))
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to get accounts by IDs")
+		return nil, nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		account := &models.Account{}
+		if err := rows.Scan(&account.AccountID, &account.UserID, &account.AccountType,
+			&account.Status, &account.KYCStatus, &account.CreatedAt, &account.UpdatedAt,
+			&account.DeletedAt, &account.ParentAccountID, &account.Metadata); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		accounts[account.AccountID] = account
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to iterate accounts: %w", err)
+	}
+
+	return accounts, missingKeys(accountIDs, accounts), nil
+}
+
 func (r *PostgresAccountRepository) GetByUserID(ctx context.Context, userID string) ([]*models.Account, error) {
 	query := `
-		SELECT account_id, user_id, account_type, status, kyc_status, created_at, updated_at, metadata
+		SELECT account_id, user_id, account_type, status, kyc_status, created_at, updated_at, deleted_at, parent_account_id, metadata
 		FROM exchange.accounts
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, userID)
@@ -86,7 +143,7 @@ func (r *PostgresAccountRepository) GetByUserID(ctx context.Context, userID stri
 		account := &models.Account{}
 		if err := rows.Scan(&account.AccountID, &account.UserID, &account.AccountType,
 			&account.Status, &account.KYCStatus, &account.CreatedAt, &account.UpdatedAt,
-			&account.Metadata); err != nil {
+			&account.DeletedAt, &account.ParentAccountID, &account.Metadata); err != nil {
 			return nil, fmt.Errorf("failed to scan account: %w", err)
 		}
 		accounts = append(accounts, account)
@@ -97,7 +154,7 @@ func (r *PostgresAccountRepository) GetByUserID(ctx context.Context, userID stri
 
 func (r *PostgresAccountRepository) Query(ctx context.Context, query *models.AccountQuery) ([]*models.Account, error) {
 	sqlQuery := `
-		SELECT account_id, user_id, account_type, status, kyc_status, created_at, updated_at, metadata
+		SELECT account_id, user_id, account_type, status, kyc_status, created_at, updated_at, deleted_at, parent_account_id, metadata
 		FROM exchange.accounts
 		WHERE 1=1
 	`
@@ -105,12 +162,22 @@ func (r *PostgresAccountRepository) Query(ctx context.Context, query *models.Acc
 	args := []interface{}{}
 	argCount := 1
 
+	if !query.IncludeDeleted {
+		sqlQuery += " AND deleted_at IS NULL"
+	}
+
 	if query.UserID != nil {
 		sqlQuery += fmt.Sprintf(" AND user_id = $%d", argCount)
 		args = append(args, *query.UserID)
 		argCount++
 	}
 
+	if query.ParentAccountID != nil {
+		sqlQuery += fmt.Sprintf(" AND parent_account_id = $%d", argCount)
+		args = append(args, *query.ParentAccountID)
+		argCount++
+	}
+
 	if query.AccountType != nil {
 		sqlQuery += fmt.Sprintf(" AND account_type = $%d", argCount)
 		args = append(args, *query.AccountType)
@@ -135,6 +202,12 @@ func (r *PostgresAccountRepository) Query(ctx context.Context, query *models.Acc
 		argCount++
 	}
 
+	var err error
+	sqlQuery, args, argCount, err = appendMetadataFilter(sqlQuery, args, argCount, query.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
 	// Add sorting
 	if query.SortBy != "" {
 		sortOrder := "ASC"
@@ -171,7 +244,7 @@ func (r *PostgresAccountRepository) Query(ctx context.Context, query *models.Acc
 		account := &models.Account{}
 		if err := rows.Scan(&account.AccountID, &account.UserID, &account.AccountType,
 			&account.Status, &account.KYCStatus, &account.CreatedAt, &account.UpdatedAt,
-			&account.Metadata); err != nil {
+			&account.DeletedAt, &account.ParentAccountID, &account.Metadata); err != nil {
 			return nil, fmt.Errorf("failed to scan account: %w", err)
 		}
 		accounts = append(accounts, account)
@@ -181,28 +254,46 @@ func (r *PostgresAccountRepository) Query(ctx context.Context, query *models.Acc
 }
 
 func (r *PostgresAccountRepository) Update(ctx context.Context, account *models.Account) error {
+	// The CTE reads the pre-update row so a status change can be detected in one statement
 	query := `
+		WITH previous AS (
+			SELECT status FROM exchange.accounts WHERE account_id = $7 FOR UPDATE
+		)
 		UPDATE exchange.accounts
-		SET user_id = $1, account_type = $2, status = $3, kyc_status = $4, updated_at = $5, metadata = $6
-		WHERE account_id = $7
+		SET user_id = $1, account_type = $2, status = $3, kyc_status = $4, updated_at = $5, metadata = $6,
+			parent_account_id = $8
+		WHERE account_id = $7 AND deleted_at IS NULL
+		RETURNING (SELECT status FROM previous)
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
-		account.UserID, account.AccountType, account.Status, account.KYCStatus,
-		account.UpdatedAt, account.Metadata, account.AccountID,
-	)
+	found := true
+	err := writeWithEvents(ctx, r.db, r.outbox, func(exec sqlExecutor) ([]models.DomainEvent, error) {
+		var previous models.AccountStatus
+		err := exec.QueryRowContext(ctx, query,
+			account.UserID, account.AccountType, account.Status, account.KYCStatus,
+			account.UpdatedAt, account.Metadata, account.AccountID, account.ParentAccountID,
+		).Scan(&previous)
+		if err == sql.ErrNoRows {
+			found = false
+			return nil, nil
+		}
+		if err != nil || previous == account.Status {
+			return nil, err
+		}
+		return []models.DomainEvent{&models.AccountStatusChanged{
+			AccountID:      account.AccountID,
+			PreviousStatus: previous,
+			Status:         account.Status,
+			ChangedAt:      account.UpdatedAt,
+		}}, nil
+	})
 
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to update account")
 		return fmt.Errorf("failed to update account: %w", err)
 	}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rows == 0 {
+	if !found {
 		return fmt.Errorf("account not found: %s", account.AccountID)
 	}
 
@@ -211,46 +302,235 @@ func (r *PostgresAccountRepository) Update(ctx context.Context, account *models.
 
 func (r *PostgresAccountRepository) UpdateStatus(ctx context.Context, accountID string, status models.AccountStatus) error {
 	query := `
+		WITH previous AS (
+			SELECT status FROM exchange.accounts WHERE account_id = $3 FOR UPDATE
+		)
 		UPDATE exchange.accounts
 		SET status = $1, updated_at = $2
-		WHERE account_id = $3
+		WHERE account_id = $3 AND deleted_at IS NULL
+		RETURNING (SELECT status FROM previous)
 	`
 
-	result, err := r.db.ExecContext(ctx, query, status, sql.NullTime{}, accountID)
+	found := true
+	err := writeWithEvents(ctx, r.db, r.outbox, func(exec sqlExecutor) ([]models.DomainEvent, error) {
+		var previous models.AccountStatus
+		err := exec.QueryRowContext(ctx, query, status, sql.NullTime{}, accountID).Scan(&previous)
+		if err == sql.ErrNoRows {
+			found = false
+			return nil, nil
+		}
+		if err != nil || previous == status {
+			return nil, err
+		}
+		return []models.DomainEvent{&models.AccountStatusChanged{
+			AccountID:      accountID,
+			PreviousStatus: previous,
+			Status:         status,
+			ChangedAt:      time.Now(),
+		}}, nil
+	})
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to update account status")
 		return fmt.Errorf("failed to update account status: %w", err)
 	}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rows == 0 {
+	if !found {
 		return fmt.Errorf("account not found: %s", accountID)
 	}
 
 	return nil
 }
 
+// Delete closes an account: it is marked CLOSED and soft-deleted, so it drops out of reads
+// while its orders, trades and history remain. Accounts with non-zero balances, open orders
+// or live sub-accounts are refused. The account and its balance rows are locked before the
+// checks, and balance and order writes for the account are refused once it is deleted.
 func (r *PostgresAccountRepository) Delete(ctx context.Context, accountID string) error {
-	query := `DELETE FROM exchange.accounts WHERE account_id = $1`
+	found := true
+	err := writeInTransaction(ctx, r.db, r.outbox, func(exec sqlExecutor) ([]models.DomainEvent, error) {
+		// Lock the account so concurrent closes and updates serialise behind the checks
+		var previous models.AccountStatus
+		err := exec.QueryRowContext(ctx, `
+			SELECT status FROM exchange.accounts
+			WHERE account_id = $1 AND deleted_at IS NULL
+			FOR UPDATE
+		`, accountID).Scan(&previous)
+		if err == sql.ErrNoRows {
+			found = false
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Settlements already updating a balance finish before the check reads it
+		var hasBalances bool
+		err = exec.QueryRowContext(ctx, `
+			SELECT COALESCE(bool_or(available_balance <> 0 OR locked_balance <> 0), false)
+			FROM (
+				SELECT available_balance, locked_balance FROM exchange.balances
+				WHERE account_id = $1
+				FOR UPDATE
+			) locked
+		`, accountID).Scan(&hasBalances)
+		if err != nil {
+			return nil, err
+		}
 
-	result, err := r.db.ExecContext(ctx, query, accountID)
+		var hasOpenOrders, hasSubAccounts bool
+		err = exec.QueryRowContext(ctx, `
+			SELECT
+				EXISTS (SELECT 1 FROM exchange.orders
+						WHERE account_id = $1 AND status IN ($2, $3, $4)),
+				EXISTS (SELECT 1 FROM exchange.accounts
+						WHERE parent_account_id = $1 AND deleted_at IS NULL)
+		`, accountID, models.OrderStatusPending, models.OrderStatusOpen, models.OrderStatusPartial).Scan(&hasOpenOrders, &hasSubAccounts)
+		if err != nil {
+			return nil, err
+		}
+		if hasBalances {
+			return nil, interfaces.ErrAccountHasBalances
+		}
+		if hasOpenOrders {
+			return nil, interfaces.ErrAccountHasOpenOrders
+		}
+		if hasSubAccounts {
+			return nil, interfaces.ErrAccountHasSubAccounts
+		}
+
+		closedAt := time.Now()
+		_, err = exec.ExecContext(ctx, `
+			UPDATE exchange.accounts
+			SET status = $1, updated_at = $2, deleted_at = $2
+			WHERE account_id = $3
+		`, models.AccountStatusClosed, closedAt, accountID)
+		if err != nil || previous == models.AccountStatusClosed {
+			return nil, err
+		}
+		return []models.DomainEvent{&models.AccountStatusChanged{
+			AccountID:      accountID,
+			PreviousStatus: previous,
+			Status:         models.AccountStatusClosed,
+			ChangedAt:      closedAt,
+		}}, nil
+	})
+
+	if errors.Is(err, interfaces.ErrAccountHasBalances) || errors.Is(err, interfaces.ErrAccountHasOpenOrders) ||
+		errors.Is(err, interfaces.ErrAccountHasSubAccounts) {
+		return fmt.Errorf("failed to delete account %s: %w", accountID, err)
+	}
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to delete account")
 		return fmt.Errorf("failed to delete account: %w", err)
 	}
 
-	rows, err := result.RowsAffected()
+	if !found {
+		return fmt.Errorf("account not found: %s", accountID)
+	}
+
+	return nil
+}
+
+// Restore reverses Delete. The account stays CLOSED until reactivated with UpdateStatus, so
+// reopening is an explicit, separately audited step. A sub-account is only restored while its
+// parent is live, so the hierarchy never has a live account below a deleted one.
+func (r *PostgresAccountRepository) Restore(ctx context.Context, accountID string) error {
+	found := true
+	err := writeInTransaction(ctx, r.db, r.outbox, func(exec sqlExecutor) ([]models.DomainEvent, error) {
+		var parentID sql.NullString
+		err := exec.QueryRowContext(ctx, `
+			SELECT parent_account_id FROM exchange.accounts
+			WHERE account_id = $1 AND deleted_at IS NOT NULL
+			FOR UPDATE
+		`, accountID).Scan(&parentID)
+		if err == sql.ErrNoRows {
+			found = false
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Sharing the parent's lock waits out a Delete of the parent, which holds it FOR UPDATE
+		// while checking for live sub-accounts
+		if parentID.Valid {
+			var parentDeleted bool
+			err = exec.QueryRowContext(ctx, `
+				SELECT deleted_at IS NOT NULL FROM exchange.accounts
+				WHERE account_id = $1
+				FOR KEY SHARE
+			`, parentID.String).Scan(&parentDeleted)
+			if err != nil {
+				return nil, err
+			}
+			if parentDeleted {
+				return nil, fmt.Errorf("%w: %s", interfaces.ErrParentAccountDeleted, parentID.String)
+			}
+		}
+
+		_, err = exec.ExecContext(ctx, `
+			UPDATE exchange.accounts
+			SET deleted_at = NULL, updated_at = $1
+			WHERE account_id = $2
+		`, time.Now(), accountID)
+		return nil, err
+	})
+	if errors.Is(err, interfaces.ErrParentAccountDeleted) {
+		return fmt.Errorf("failed to restore account %s: %w", accountID, err)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		r.logger.WithError(err).Error("Failed to restore account")
+		return fmt.Errorf("failed to restore account: %w", err)
 	}
 
-	if rows == 0 {
-		return fmt.Errorf("account not found: %s", accountID)
+	if !found {
+		return fmt.Errorf("deleted account not found: %s", accountID)
 	}
 
 	return nil
 }
+
+// GetSubAccounts walks the hierarchy below an account breadth-first, so every sub-account
+// follows its parent
+func (r *PostgresAccountRepository) GetSubAccounts(ctx context.Context, accountID string) ([]*models.Account, error) {
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT account_id, 1 AS depth
+			FROM exchange.accounts
+			WHERE parent_account_id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT a.account_id, tree.depth + 1
+			FROM exchange.accounts a
+			JOIN tree ON a.parent_account_id = tree.account_id
+			WHERE a.deleted_at IS NULL
+		)
+		SELECT a.account_id, a.user_id, a.account_type, a.status, a.kyc_status, a.created_at, a.updated_at,
+			   a.deleted_at, a.parent_account_id, a.metadata
+		FROM tree
+		JOIN exchange.accounts a ON a.account_id = tree.account_id
+		ORDER BY tree.depth, a.parent_account_id, a.account_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, accountID)
+	if err != nil {
+		r.logger.WithError(err).WithField("account_id", accountID).Error("Failed to get sub-accounts")
+		return nil, fmt.Errorf("failed to get sub-accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := []*models.Account{}
+	for rows.Next() {
+		account := &models.Account{}
+		if err := rows.Scan(&account.AccountID, &account.UserID, &account.AccountType,
+			&account.Status, &account.KYCStatus, &account.CreatedAt, &account.UpdatedAt,
+			&account.DeletedAt, &account.ParentAccountID, &account.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sub-accounts: %w", err)
+	}
+
+	return accounts, nil
+}
@@ -0,0 +1,168 @@
+package adapters
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// TestAccountDelete tests that Delete locks the account and its balances before checking them
+// and only soft-deletes an empty account
+func TestAccountDelete(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	tests := []struct {
+		name          string
+		hasBalances   bool
+		hasOpenOrders bool
+		hasSubs       bool
+		expectedErr   error
+		expectDeleted bool
+	}{
+		{name: "empty account", expectDeleted: true},
+		{name: "non-zero balance", hasBalances: true, expectedErr: interfaces.ErrAccountHasBalances},
+		{name: "open orders", hasOpenOrders: true, expectedErr: interfaces.ErrAccountHasOpenOrders},
+		{name: "open sub-accounts", hasSubs: true, expectedErr: interfaces.ErrAccountHasSubAccounts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, script := openScriptedDB(t)
+			script.on("SELECT status FROM exchange.accounts", []string{"status"}, []driver.Value{string(models.AccountStatusActive)})
+			script.on("FROM exchange.balances", []string{"bool_or"}, []driver.Value{tt.hasBalances})
+			script.on("EXISTS", []string{"orders", "subs"}, []driver.Value{tt.hasOpenOrders, tt.hasSubs})
+			repo := NewPostgresAccountRepository(db, false, logger)
+
+			err := repo.Delete(context.Background(), "acc-1")
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Delete() error = %v, expected %v", err, tt.expectedErr)
+				}
+			} else if err != nil {
+				t.Errorf("Delete() unexpected error: %v", err)
+			}
+
+			statements := script.statements()
+			if len(statements) < 3 || !strings.Contains(statements[1], "FOR UPDATE") ||
+				!strings.Contains(statements[2], "FROM exchange.balances WHERE account_id = $1 FOR UPDATE") {
+				t.Errorf("statements = %q, expected the account then its balances locked FOR UPDATE", statements)
+			}
+			if deleted := len(script.find("SET status = $1, updated_at = $2, deleted_at = $2")) == 1; deleted != tt.expectDeleted {
+				t.Errorf("account deleted = %v, expected %v", deleted, tt.expectDeleted)
+			}
+		})
+	}
+}
+
+// TestAccountDeletedWrites tests that writes refused by the deleted-account trigger surface as
+// ErrAccountDeleted
+func TestAccountDeletedWrites(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	rejected := &pq.Error{Code: accountDeletedCode, Message: "account acc-1 is deleted"}
+
+	tests := []struct {
+		name  string
+		write func(t *testing.T) error
+	}{
+		{
+			name: "order create",
+			write: func(t *testing.T) error {
+				db, script := openScriptedDB(t)
+				script.on("INSERT INTO exchange.orders", nil).err = rejected
+				return NewPostgresOrderRepository(db, false, logger).Create(context.Background(), &models.Order{OrderID: "ord-1", AccountID: "acc-1"})
+			},
+		},
+		{
+			name: "order cancel",
+			write: func(t *testing.T) error {
+				db, script := openScriptedDB(t)
+				script.on("UPDATE exchange.orders", nil).err = rejected
+				return NewPostgresOrderRepository(db, false, logger).Cancel(context.Background(), "ord-1")
+			},
+		},
+		{
+			name: "balance upsert",
+			write: func(t *testing.T) error {
+				db, script := openScriptedDB(t)
+				script.on("exchange.balances", nil).err = rejected
+				return NewPostgresBalanceRepository(db, false, logger).Upsert(context.Background(), &models.Balance{BalanceID: "bal-1", AccountID: "acc-1"})
+			},
+		},
+		{
+			name: "balance atomic update",
+			write: func(t *testing.T) error {
+				db, script := openScriptedDB(t)
+				script.on("exchange.balances", nil).err = rejected
+				return NewPostgresBalanceRepository(db, false, logger).AtomicUpdate(context.Background(), "acc-1", "USD", decimal.NewFromInt(5), decimal.Zero)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.write(t); !errors.Is(err, interfaces.ErrAccountDeleted) {
+				t.Errorf("write error = %v, expected %v", err, interfaces.ErrAccountDeleted)
+			}
+		})
+	}
+}
+
+// TestAccountRestore tests that a sub-account is only restored while its parent is live
+func TestAccountRestore(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	tests := []struct {
+		name           string
+		accountRows    [][]driver.Value
+		parentRows     [][]driver.Value
+		expectedErr    error
+		expectRestored bool
+	}{
+		{name: "top-level account", accountRows: [][]driver.Value{{nil}}, expectRestored: true},
+		{name: "live parent", accountRows: [][]driver.Value{{"parent-1"}}, parentRows: [][]driver.Value{{false}}, expectRestored: true},
+		{name: "deleted parent", accountRows: [][]driver.Value{{"parent-1"}}, parentRows: [][]driver.Value{{true}}, expectedErr: interfaces.ErrParentAccountDeleted},
+		{name: "not deleted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, script := openScriptedDB(t)
+			script.on("SELECT parent_account_id", []string{"parent_account_id"}, tt.accountRows...)
+			script.on("FOR KEY SHARE", []string{"deleted"}, tt.parentRows...)
+			repo := NewPostgresAccountRepository(db, false, logger)
+
+			err := repo.Restore(context.Background(), "acc-1")
+			switch {
+			case tt.expectedErr != nil:
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Restore() error = %v, expected %v", err, tt.expectedErr)
+				}
+			case tt.accountRows == nil:
+				if err == nil {
+					t.Error("Restore() expected an error for an account that is not deleted")
+				}
+			case err != nil:
+				t.Errorf("Restore() unexpected error: %v", err)
+			}
+
+			if restored := len(script.find("SET deleted_at = NULL")) == 1; restored != tt.expectRestored {
+				t.Errorf("account restored = %v, expected %v", restored, tt.expectRestored)
+			}
+			if parentLocked := len(script.find("FOR KEY SHARE")) == 1; parentLocked != (tt.parentRows != nil) {
+				t.Errorf("parent locked = %v, expected %v", parentLocked, tt.parentRows != nil)
+			}
+		})
+	}
+}
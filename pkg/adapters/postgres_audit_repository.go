@@ -0,0 +1,126 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+type PostgresAuditRepository struct {
+	db     *sql.DB
+	logger *logrus.Logger
+}
+
+func NewPostgresAuditRepository(db *sql.DB, logger *logrus.Logger) interfaces.AuditRepository {
+	return &PostgresAuditRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// buildAuditQuery renders an AuditQuery as SQL and arguments
+func buildAuditQuery(query *models.AuditQuery) (string, []interface{}) {
+	sqlQuery := `
+		SELECT audit_id, entity_type, entity_id, action, actor, reason, before, after, occurred_at
+		FROM exchange.audit_log
+		WHERE 1=1
+	`
+
+	args := []interface{}{}
+	argCount := 1
+
+	if query.EntityType != nil {
+		sqlQuery += fmt.Sprintf(" AND entity_type = $%d", argCount)
+		args = append(args, *query.EntityType)
+		argCount++
+	}
+
+	if query.EntityID != nil {
+		sqlQuery += fmt.Sprintf(" AND entity_id = $%d", argCount)
+		args = append(args, *query.EntityID)
+		argCount++
+	}
+
+	if query.Actor != nil {
+		sqlQuery += fmt.Sprintf(" AND actor = $%d", argCount)
+		args = append(args, *query.Actor)
+		argCount++
+	}
+
+	if query.Action != nil {
+		sqlQuery += fmt.Sprintf(" AND action = $%d", argCount)
+		args = append(args, *query.Action)
+		argCount++
+	}
+
+	if query.From != nil {
+		sqlQuery += fmt.Sprintf(" AND occurred_at >= $%d", argCount)
+		args = append(args, *query.From)
+		argCount++
+	}
+
+	if query.To != nil {
+		sqlQuery += fmt.Sprintf(" AND occurred_at < $%d", argCount)
+		args = append(args, *query.To)
+		argCount++
+	}
+
+	// audit_id breaks ties between entries written in the same instant
+	if strings.ToUpper(query.SortOrder) == "DESC" {
+		sqlQuery += " ORDER BY occurred_at DESC, audit_id DESC"
+	} else {
+		sqlQuery += " ORDER BY occurred_at, audit_id"
+	}
+
+	if query.Limit > 0 {
+		sqlQuery += fmt.Sprintf(" LIMIT $%d", argCount)
+		args = append(args, query.Limit)
+		argCount++
+	}
+
+	if query.Offset > 0 {
+		sqlQuery += fmt.Sprintf(" OFFSET $%d", argCount)
+		args = append(args, query.Offset)
+	}
+
+	return sqlQuery, args
+}
+
+func (r *PostgresAuditRepository) Query(ctx context.Context, query *models.AuditQuery) ([]*models.AuditEntry, error) {
+	sqlQuery, args := buildAuditQuery(query)
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to query audit log")
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*models.AuditEntry{}
+	for rows.Next() {
+		// Before is NULL for creates and After for deletes, which json.RawMessage cannot scan
+		entry := &models.AuditEntry{}
+		var before, after []byte
+		if err := rows.Scan(&entry.AuditID, &entry.EntityType, &entry.EntityID, &entry.Action,
+			&entry.Actor, &entry.Reason, &before, &after, &entry.OccurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entry.Before, entry.After = before, after
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (r *PostgresAuditRepository) GetByAccount(ctx context.Context, accountID string) ([]*models.AuditEntry, error) {
+	entityType := models.AuditEntityAccount
+	return r.Query(ctx, &models.AuditQuery{EntityType: &entityType, EntityID: &accountID})
+}
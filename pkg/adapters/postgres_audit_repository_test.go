@@ -0,0 +1,123 @@
+package adapters
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// TestBuildAuditQuery tests translation of audit queries into SQL predicates
+func TestBuildAuditQuery(t *testing.T) {
+	entityType := models.AuditEntityAccount
+	accountID := "acc-1"
+	actor := "ops@example.com"
+	action := models.AuditActionUpdate
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	tests := []struct {
+		name         string
+		query        *models.AuditQuery
+		expectedSQL  []string
+		expectedArgs []interface{}
+	}{
+		{
+			name:         "no filters",
+			query:        &models.AuditQuery{},
+			expectedSQL:  []string{"WHERE 1=1", "ORDER BY occurred_at, audit_id"},
+			expectedArgs: []interface{}{},
+		},
+		{
+			name:  "by account",
+			query: &models.AuditQuery{EntityType: &entityType, EntityID: &accountID},
+			expectedSQL: []string{"AND entity_type = $1", "AND entity_id = $2",
+				"ORDER BY occurred_at, audit_id"},
+			expectedArgs: []interface{}{"account", "acc-1"},
+		},
+		{
+			name: "by actor and time range, newest first",
+			query: &models.AuditQuery{Actor: &actor, Action: &action, From: &from, To: &to,
+				Limit: 50, Offset: 100, SortOrder: "desc"},
+			expectedSQL: []string{"AND actor = $1", "AND action = $2", "AND occurred_at >= $3",
+				"AND occurred_at < $4", "ORDER BY occurred_at DESC, audit_id DESC", "LIMIT $5", "OFFSET $6"},
+			expectedArgs: []interface{}{"ops@example.com", models.AuditActionUpdate, from, to, 50, 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlQuery, args := buildAuditQuery(tt.query)
+			for _, fragment := range tt.expectedSQL {
+				if !strings.Contains(sqlQuery, fragment) {
+					t.Errorf("query missing %q:\n%s", fragment, sqlQuery)
+				}
+			}
+			if !reflect.DeepEqual(args, tt.expectedArgs) {
+				t.Errorf("args = %v, expected %v", args, tt.expectedArgs)
+			}
+		})
+	}
+}
+
+// TestWriteContext tests that actor, reason and causation ID travel through the context
+func TestWriteContext(t *testing.T) {
+	ctx := context.Background()
+	if hasWriteContext(ctx) {
+		t.Error("hasWriteContext() = true for an empty context")
+	}
+
+	ctx = interfaces.WithReason(interfaces.WithActor(ctx, "ops@example.com"), "KYC documents expired")
+	if !hasWriteContext(ctx) {
+		t.Error("hasWriteContext() = false with an actor and reason")
+	}
+	if actor := interfaces.ActorFromContext(ctx); actor != "ops@example.com" {
+		t.Errorf("ActorFromContext() = %q", actor)
+	}
+	if reason := interfaces.ReasonFromContext(ctx); reason != "KYC documents expired" {
+		t.Errorf("ReasonFromContext() = %q", reason)
+	}
+	if causationID := interfaces.CausationIDFromContext(ctx); causationID != "" {
+		t.Errorf("CausationIDFromContext() = %q, expected empty", causationID)
+	}
+}
+
+// TestAuditRepositoryScansNullDiffs tests reading create and delete entries, whose before and
+// after diffs are NULL
+func TestAuditRepositoryScansNullDiffs(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	occurredAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	db := openStubDB(t,
+		[]string{"audit_id", "entity_type", "entity_id", "action", "actor", "reason", "before", "after", "occurred_at"},
+		[]driver.Value{int64(1), "account", "acc-1", "CREATE", "ops", nil, nil, []byte(`{"status":"ACTIVE"}`), occurredAt},
+		[]driver.Value{int64(2), "account", "acc-1", "UPDATE", "ops", "KYC expired", []byte(`{"status":"ACTIVE"}`), []byte(`{"status":"SUSPENDED"}`), occurredAt},
+		[]driver.Value{int64(3), "account", "acc-1", "DELETE", "ops", nil, []byte(`{"status":"SUSPENDED"}`), nil, occurredAt},
+	)
+	repo := NewPostgresAuditRepository(db, logger)
+
+	entries, err := repo.GetByAccount(context.Background(), "acc-1")
+	if err != nil {
+		t.Fatalf("GetByAccount() unexpected error: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("GetByAccount() returned %d entries, expected 3", len(entries))
+	}
+	if entries[0].Before != nil || string(entries[0].After) != `{"status":"ACTIVE"}` {
+		t.Errorf("create entry before = %s, after = %s", entries[0].Before, entries[0].After)
+	}
+	if entries[1].Reason == nil || *entries[1].Reason != "KYC expired" {
+		t.Errorf("update entry reason = %v, expected KYC expired", entries[1].Reason)
+	}
+	if string(entries[2].Before) != `{"status":"SUSPENDED"}` || entries[2].After != nil {
+		t.Errorf("delete entry before = %s, after = %s", entries[2].Before, entries[2].After)
+	}
+}
@@ -3,9 +3,11 @@ package adapters
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
 	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
 	"github.com/shopspring/decimal"
@@ -14,11 +16,12 @@ import (
 
 type PostgresBalanceRepository struct {
 	db     *sql.DB
+	outbox bool
 	logger *logrus.Logger
 }
 
-func NewPostgresBalanceRepository(db *sql.DB, logger *logrus.Logger) interfaces.BalanceRepository {
-	return &PostgresBalanceRepository{db: db, logger: logger}
+func NewPostgresBalanceRepository(db *sql.DB, outbox bool, logger *logrus.Logger) interfaces.BalanceRepository {
+	return &PostgresBalanceRepository{db: db, outbox: outbox, logger: logger}
 }
 
 func (r *PostgresBalanceRepository) Upsert(ctx context.Context, balance *models.Balance) error {
@@ -28,11 +31,27 @@ func (r *PostgresBalanceRepository) Upsert(ctx context.Context, balance *models.
 		ON CONFLICT (account_id, symbol) DO UPDATE SET
 			available_balance = $4, locked_balance = $5, total_balance = $6, last_updated = $7, metadata = $8
 	`
-	_, err := r.db.ExecContext(ctx, query, balance.BalanceID, balance.AccountID, balance.Symbol,
-		balance.AvailableBalance, balance.LockedBalance, balance.TotalBalance, balance.LastUpdated, balance.Metadata)
+	err := writeWithEvents(ctx, r.db, r.outbox, func(exec sqlExecutor) ([]models.DomainEvent, error) {
+		var balanceID string
+		err := exec.QueryRowContext(ctx, query+" RETURNING balance_id", balance.BalanceID, balance.AccountID, balance.Symbol,
+			balance.AvailableBalance, balance.LockedBalance, balance.TotalBalance, balance.LastUpdated, balance.Metadata,
+		).Scan(&balanceID)
+		if err != nil {
+			return nil, err
+		}
+		return []models.DomainEvent{&models.BalanceChanged{
+			BalanceID:        balanceID,
+			AccountID:        balance.AccountID,
+			Symbol:           balance.Symbol,
+			AvailableBalance: balance.AvailableBalance,
+			LockedBalance:    balance.LockedBalance,
+			TotalBalance:     balance.TotalBalance,
+			ChangedAt:        balance.LastUpdated,
+		}}, nil
+	})
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to upsert balance")
-		return fmt.Errorf("failed to upsert balance: %w", err)
+		return fmt.Errorf("failed to upsert balance: %w", accountWriteError(err))
 	}
 	return nil
 }
@@ -53,6 +72,68 @@ func (r *PostgresBalanceRepository) GetByID(ctx context.Context, balanceID strin
 	return balance, nil
 }
 
+func (r *PostgresBalanceRepository) GetByIDs(ctx context.Context, balanceIDs []string) (map[string]*models.Balance, []string, error) {
+	balances := make(map[string]*models.Balance, len(balanceIDs))
+	if len(balanceIDs) == 0 {
+		return balances, []string{}, nil
+	}
+
+	query := `SELECT balance_id, account_id, symbol, available_balance, locked_balance, total_balance, last_updated, metadata
+		FROM exchange.balances WHERE balance_id = ANY($1)`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(balanceIDs))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get balances: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		balance := &models.Balance{}
+		if err := rows.Scan(&balance.BalanceID, &balance.AccountID, &balance.Symbol,
+			&balance.AvailableBalance, &balance.LockedBalance, &balance.TotalBalance,
+			&balance.LastUpdated, &balance.Metadata); err != nil {
+			r.logger.WithError(err).Error("Failed to scan balance")
+			return nil, nil, fmt.Errorf("failed to scan balance: %w", err)
+		}
+		balances[balance.BalanceID] = balance
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to iterate balances: %w", err)
+	}
+
+	return balances, missingKeys(balanceIDs, balances), nil
+}
+
+func (r *PostgresBalanceRepository) GetMany(ctx context.Context, accountID string, symbols []string) (map[string]*models.Balance, []string, error) {
+	balances := make(map[string]*models.Balance, len(symbols))
+	if len(symbols) == 0 {
+		return balances, []string{}, nil
+	}
+
+	query := `SELECT balance_id, account_id, symbol, available_balance, locked_balance, total_balance, last_updated, metadata
+		FROM exchange.balances WHERE account_id = $1 AND symbol = ANY($2)`
+	rows, err := r.db.QueryContext(ctx, query, accountID, pq.Array(symbols))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get balances: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		balance := &models.Balance{}
+		if err := rows.Scan(&balance.BalanceID, &balance.AccountID, &balance.Symbol,
+			&balance.AvailableBalance, &balance.LockedBalance, &balance.TotalBalance,
+			&balance.LastUpdated, &balance.Metadata); err != nil {
+			r.logger.WithError(err).Error("Failed to scan balance")
+			return nil, nil, fmt.Errorf("failed to scan balance: %w", err)
+		}
+		balances[balance.Symbol] = balance
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to iterate balances: %w", err)
+	}
+
+	return balances, missingKeys(symbols, balances), nil
+}
+
 func (r *PostgresBalanceRepository) GetByAccountAndSymbol(ctx context.Context, accountID, symbol string) (*models.Balance, error) {
 	query := `SELECT balance_id, account_id, symbol, available_balance, locked_balance, total_balance, last_updated, metadata
 		FROM exchange.balances WHERE account_id = $1 AND symbol = $2`
@@ -86,6 +167,12 @@ func (r *PostgresBalanceRepository) Query(ctx context.Context, query *models.Bal
 		argCount++
 	}
 
+	var err error
+	sqlQuery, args, argCount, err = appendMetadataFilter(sqlQuery, args, argCount, query.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
 	sqlQuery += " ORDER BY last_updated DESC"
 	if query.Limit > 0 {
 		sqlQuery += fmt.Sprintf(" LIMIT $%d", argCount)
@@ -104,20 +191,42 @@ func (r *PostgresBalanceRepository) Query(ctx context.Context, query *models.Bal
 		if err := rows.Scan(&balance.BalanceID, &balance.AccountID, &balance.Symbol,
 			&balance.AvailableBalance, &balance.LockedBalance, &balance.TotalBalance,
 			&balance.LastUpdated, &balance.Metadata); err != nil {
-			return nil, err
+			r.logger.WithError(err).Error("Failed to scan balance")
+			return nil, fmt.Errorf("failed to scan balance: %w", err)
 		}
 		balances = append(balances, balance)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate balances: %w", err)
+	}
 	return balances, nil
 }
 
 func (r *PostgresBalanceRepository) UpdateAvailableBalance(ctx context.Context, balanceID string, availableBalance, lockedBalance decimal.Decimal) error {
 	totalBalance := availableBalance.Add(lockedBalance)
-	query := `UPDATE exchange.balances SET available_balance = $1, locked_balance = $2, total_balance = $3, last_updated = $4 WHERE balance_id = $5`
-	_, err := r.db.ExecContext(ctx, query, availableBalance, lockedBalance, totalBalance, time.Now(), balanceID)
+	query := `UPDATE exchange.balances SET available_balance = $1, locked_balance = $2, total_balance = $3, last_updated = $4 WHERE balance_id = $5
+		RETURNING account_id, symbol`
+	err := writeWithEvents(ctx, r.db, r.outbox, func(exec sqlExecutor) ([]models.DomainEvent, error) {
+		event := &models.BalanceChanged{
+			BalanceID:        balanceID,
+			AvailableBalance: availableBalance,
+			LockedBalance:    lockedBalance,
+			TotalBalance:     totalBalance,
+			ChangedAt:        time.Now(),
+		}
+		err := exec.QueryRowContext(ctx, query, availableBalance, lockedBalance, totalBalance, event.ChangedAt, balanceID).
+			Scan(&event.AccountID, &event.Symbol)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []models.DomainEvent{event}, nil
+	})
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to update balance")
-		return fmt.Errorf("failed to update balance: %w", err)
+		return fmt.Errorf("failed to update balance: %w", accountWriteError(err))
 	}
 	return nil
 }
@@ -137,23 +246,74 @@ func (r *PostgresBalanceRepository) GetByAccount(ctx context.Context, accountID
 		if err := rows.Scan(&balance.BalanceID, &balance.AccountID, &balance.Symbol,
 			&balance.AvailableBalance, &balance.LockedBalance, &balance.TotalBalance,
 			&balance.LastUpdated, &balance.Metadata); err != nil {
-			return nil, err
+			r.logger.WithError(err).Error("Failed to scan balance")
+			return nil, fmt.Errorf("failed to scan balance: %w", err)
 		}
 		balances = append(balances, balance)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate balances: %w", err)
+	}
 	return balances, nil
 }
 
+// AtomicUpdate applies deltas in one statement. Credits (no negative delta) upsert, so a missing
+// balance row is created rather than the funds being dropped. Any debit is conditional on the
+// resulting balances staying non-negative, and fails with ErrInsufficientFunds otherwise; a
+// missing row counts as a zero balance.
 func (r *PostgresBalanceRepository) AtomicUpdate(ctx context.Context, accountID, symbol string, availableDelta, lockedDelta decimal.Decimal) error {
-	query := `
+	credit := !availableDelta.IsNegative() && !lockedDelta.IsNegative()
+	updateQuery := `
 		UPDATE exchange.balances
 		SET available_balance = available_balance + $1,
 			locked_balance = locked_balance + $2,
 			total_balance = total_balance + $1 + $2,
 			last_updated = $3
 		WHERE account_id = $4 AND symbol = $5
+		  AND available_balance + $1 >= 0 AND locked_balance + $2 >= 0
+		RETURNING balance_id, available_balance, locked_balance, total_balance
+	`
+	upsertQuery := `
+		INSERT INTO exchange.balances (balance_id, account_id, symbol, available_balance, locked_balance, total_balance, last_updated)
+		VALUES ($6, $4, $5, $1, $2, $1 + $2, $3)
+		ON CONFLICT (account_id, symbol) DO UPDATE SET
+			available_balance = exchange.balances.available_balance + $1,
+			locked_balance = exchange.balances.locked_balance + $2,
+			total_balance = exchange.balances.total_balance + $1 + $2,
+			last_updated = $3
+		RETURNING balance_id, available_balance, locked_balance, total_balance
 	`
-	_, err := r.db.ExecContext(ctx, query, availableDelta, lockedDelta, time.Now(), accountID, symbol)
+	err := writeWithEvents(ctx, r.db, r.outbox, func(exec sqlExecutor) ([]models.DomainEvent, error) {
+		event := &models.BalanceChanged{
+			AccountID:      accountID,
+			Symbol:         symbol,
+			AvailableDelta: availableDelta,
+			LockedDelta:    lockedDelta,
+			ChangedAt:      time.Now(),
+		}
+		args := []interface{}{availableDelta, lockedDelta, event.ChangedAt, accountID, symbol}
+		query := updateQuery
+		if credit {
+			balanceID, err := newRandomID()
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate balance ID: %w", err)
+			}
+			query = upsertQuery
+			args = append(args, balanceID)
+		}
+		err := exec.QueryRowContext(ctx, query, args...).
+			Scan(&event.BalanceID, &event.AvailableBalance, &event.LockedBalance, &event.TotalBalance)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s %s", interfaces.ErrInsufficientFunds, accountID, symbol)
+		}
+		if err != nil {
+			return nil, accountWriteError(err)
+		}
+		return []models.DomainEvent{event}, nil
+	})
+	if errors.Is(err, interfaces.ErrInsufficientFunds) || errors.Is(err, interfaces.ErrAccountDeleted) {
+		return fmt.Errorf("failed to atomically update balance: %w", err)
+	}
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to atomically update balance")
 		return fmt.Errorf("failed to atomically update balance: %w", err)
@@ -0,0 +1,124 @@
+package adapters
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// TestAtomicUpdateGuards tests that debits are conditional on non-negative results and that
+// credits create a missing balance row
+func TestAtomicUpdateGuards(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	returning := []string{"balance_id", "available_balance", "locked_balance", "total_balance"}
+
+	tests := []struct {
+		name          string
+		available     string
+		locked        string
+		rows          [][]driver.Value
+		expectedQuery string
+		expectedErr   error
+	}{
+		{
+			name:          "debit within balance",
+			available:     "-5",
+			locked:        "5",
+			rows:          [][]driver.Value{{"bal-1", "5", "5", "10"}},
+			expectedQuery: "AND available_balance + $1 >= 0 AND locked_balance + $2 >= 0",
+		},
+		{
+			name:          "debit beyond balance",
+			available:     "-50",
+			locked:        "50",
+			expectedQuery: "AND available_balance + $1 >= 0 AND locked_balance + $2 >= 0",
+			expectedErr:   interfaces.ErrInsufficientFunds,
+		},
+		{
+			name:          "release beyond locked",
+			available:     "5",
+			locked:        "-5",
+			expectedQuery: "AND available_balance + $1 >= 0 AND locked_balance + $2 >= 0",
+			expectedErr:   interfaces.ErrInsufficientFunds,
+		},
+		{
+			name:          "credit to missing row",
+			available:     "5",
+			locked:        "0",
+			rows:          [][]driver.Value{{"bal-new", "5", "0", "5"}},
+			expectedQuery: "ON CONFLICT (account_id, symbol) DO UPDATE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, script := openScriptedDB(t)
+			script.on("exchange.balances", returning, tt.rows...)
+			repo := NewPostgresBalanceRepository(db, false, logger)
+
+			err := repo.AtomicUpdate(context.Background(), "acc-1", "USD",
+				decimal.RequireFromString(tt.available), decimal.RequireFromString(tt.locked))
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("AtomicUpdate() error = %v, expected %v", err, tt.expectedErr)
+				}
+			} else if err != nil {
+				t.Errorf("AtomicUpdate() unexpected error: %v", err)
+			}
+
+			statements := script.statements()
+			if len(statements) != 1 || !strings.Contains(statements[0], tt.expectedQuery) {
+				t.Errorf("statements = %q, expected one containing %q", statements, tt.expectedQuery)
+			}
+		})
+	}
+}
+
+// TestBalanceScanErrors tests that rows which cannot be scanned fail with a wrapped error
+func TestBalanceScanErrors(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx := context.Background()
+	columns := []string{"balance_id", "account_id", "symbol", "available_balance", "locked_balance",
+		"total_balance", "last_updated", "metadata"}
+	// An available balance that is not a number cannot be scanned into a decimal
+	row := []driver.Value{"bal-1", "acc-1", "USD", "not-a-number", "0", "0", time.Now(), nil}
+
+	tests := []struct {
+		name string
+		read func(repo interfaces.BalanceRepository) error
+	}{
+		{name: "GetByIDs", read: func(repo interfaces.BalanceRepository) error {
+			_, _, err := repo.GetByIDs(ctx, []string{"bal-1"})
+			return err
+		}},
+		{name: "GetMany", read: func(repo interfaces.BalanceRepository) error {
+			_, _, err := repo.GetMany(ctx, "acc-1", []string{"USD"})
+			return err
+		}},
+		{name: "GetByAccount", read: func(repo interfaces.BalanceRepository) error {
+			_, err := repo.GetByAccount(ctx, "acc-1")
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewPostgresBalanceRepository(openStubDB(t, columns, row), false, logger)
+
+			err := tt.read(repo)
+			if err == nil || !strings.HasPrefix(err.Error(), "failed to scan balance: ") {
+				t.Errorf("%s() error = %v, expected a wrapped scan error", tt.name, err)
+			}
+		})
+	}
+}
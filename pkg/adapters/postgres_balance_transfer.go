@@ -0,0 +1,312 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// validateTransfer checks the fields a transfer needs before touching the database
+func validateTransfer(transfer *models.Transfer) error {
+	switch {
+	case transfer.TransferID == "":
+		return fmt.Errorf("%w: transfer ID is required", interfaces.ErrInvalidTransfer)
+	case transfer.Symbol == "":
+		return fmt.Errorf("%w: symbol is required", interfaces.ErrInvalidTransfer)
+	case !transfer.Amount.IsPositive():
+		return fmt.Errorf("%w: amount must be positive, got %s", interfaces.ErrInvalidTransfer, transfer.Amount)
+	case transfer.FromAccountID == "" || transfer.ToAccountID == "":
+		return fmt.Errorf("%w: source and destination accounts are required", interfaces.ErrInvalidTransfer)
+	case transfer.FromAccountID == transfer.ToAccountID:
+		return fmt.Errorf("%w: source and destination accounts must differ", interfaces.ErrInvalidTransfer)
+	}
+	return nil
+}
+
+// Transfer debits the source and credits the destination available balance in one transaction.
+// Both accounts are share-locked so neither can be closed mid-transfer, and both balance rows
+// are locked in account ID order so opposing transfers cannot deadlock. The destination balance
+// is created when missing.
+func (r *PostgresBalanceRepository) Transfer(ctx context.Context, transfer *models.Transfer) error {
+	if err := validateTransfer(transfer); err != nil {
+		return err
+	}
+	if transfer.CreatedAt.IsZero() {
+		transfer.CreatedAt = time.Now()
+	}
+	accountIDs := []string{transfer.FromAccountID, transfer.ToAccountID}
+
+	err := writeInTransaction(ctx, r.db, r.outbox, func(exec sqlExecutor) ([]models.DomainEvent, error) {
+		if err := r.checkTransferAccounts(ctx, exec, transfer); err != nil {
+			return nil, err
+		}
+
+		balanceID, err := newRandomID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate balance ID: %w", err)
+		}
+		_, err = exec.ExecContext(ctx, `
+			INSERT INTO exchange.balances (balance_id, account_id, symbol, available_balance, locked_balance, total_balance, last_updated)
+			VALUES ($1, $2, $3, 0, 0, 0, $4)
+			ON CONFLICT (account_id, symbol) DO NOTHING
+		`, balanceID, transfer.ToAccountID, transfer.Symbol, transfer.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create destination balance: %w", err)
+		}
+
+		rows, err := exec.QueryContext(ctx, `
+			SELECT account_id, available_balance FROM exchange.balances
+			WHERE symbol = $1 AND account_id = ANY($2)
+			ORDER BY account_id
+			FOR UPDATE
+		`, transfer.Symbol, pq.Array(accountIDs))
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock balances: %w", err)
+		}
+		available := make(map[string]decimal.Decimal, 2)
+		for rows.Next() {
+			var accountID string
+			var balance decimal.Decimal
+			if err := rows.Scan(&accountID, &balance); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan balance: %w", err)
+			}
+			available[accountID] = balance
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to iterate balances: %w", err)
+		}
+		if source, ok := available[transfer.FromAccountID]; !ok || source.LessThan(transfer.Amount) {
+			return nil, fmt.Errorf("%w: %s has %s %s available, transfer needs %s", interfaces.ErrInsufficientFunds,
+				transfer.FromAccountID, source, transfer.Symbol, transfer.Amount)
+		}
+
+		_, err = exec.ExecContext(ctx, `
+			INSERT INTO exchange.transfers (transfer_id, from_account_id, to_account_id, symbol, amount, created_at, metadata)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, transfer.TransferID, transfer.FromAccountID, transfer.ToAccountID, transfer.Symbol, transfer.Amount,
+			transfer.CreatedAt, transfer.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record transfer: %w", err)
+		}
+
+		events := make([]models.DomainEvent, 0, 2)
+		for _, movement := range []struct {
+			accountID string
+			amount    decimal.Decimal
+		}{
+			{transfer.FromAccountID, transfer.Amount.Neg()},
+			{transfer.ToAccountID, transfer.Amount},
+		} {
+			event := &models.BalanceChanged{
+				AccountID:      movement.accountID,
+				Symbol:         transfer.Symbol,
+				AvailableDelta: movement.amount,
+				ChangedAt:      transfer.CreatedAt,
+			}
+			err := exec.QueryRowContext(ctx, `
+				UPDATE exchange.balances
+				SET available_balance = available_balance + $1,
+					total_balance = total_balance + $1,
+					last_updated = $2
+				WHERE account_id = $3 AND symbol = $4
+				RETURNING balance_id, available_balance, locked_balance, total_balance
+			`, movement.amount, transfer.CreatedAt, movement.accountID, transfer.Symbol).
+				Scan(&event.BalanceID, &event.AvailableBalance, &event.LockedBalance, &event.TotalBalance)
+			if err != nil {
+				return nil, fmt.Errorf("failed to apply transfer to %s: %w", movement.accountID, err)
+			}
+
+			_, err = exec.ExecContext(ctx, `
+				INSERT INTO exchange.ledger_entries (transfer_id, account_id, symbol, amount, balance_after, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, transfer.TransferID, movement.accountID, transfer.Symbol, movement.amount, event.AvailableBalance,
+				transfer.CreatedAt)
+			if err != nil {
+				return nil, fmt.Errorf("failed to record ledger entry: %w", err)
+			}
+			events = append(events, event)
+		}
+		return events, nil
+	})
+
+	if errors.Is(err, interfaces.ErrInsufficientFunds) || errors.Is(err, interfaces.ErrTransferOutsideHierarchy) {
+		return fmt.Errorf("failed to transfer %s: %w", transfer.TransferID, err)
+	}
+	if err != nil {
+		r.logger.WithError(err).WithFields(logrus.Fields{
+			"transfer_id": transfer.TransferID,
+			"from":        transfer.FromAccountID,
+			"to":          transfer.ToAccountID,
+		}).Error("Failed to transfer funds")
+		return fmt.Errorf("failed to transfer funds: %w", err)
+	}
+
+	return nil
+}
+
+// checkTransferAccounts share-locks both accounts, failing if either is missing or closed, and
+// checks that they share a master account
+func (r *PostgresBalanceRepository) checkTransferAccounts(ctx context.Context, exec sqlExecutor, transfer *models.Transfer) error {
+	accountIDs := []string{transfer.FromAccountID, transfer.ToAccountID}
+
+	rows, err := exec.QueryContext(ctx, `
+		SELECT account_id FROM exchange.accounts
+		WHERE account_id = ANY($1) AND deleted_at IS NULL
+		ORDER BY account_id
+		FOR SHARE
+	`, pq.Array(accountIDs))
+	if err != nil {
+		return fmt.Errorf("failed to lock accounts: %w", err)
+	}
+	open := make(map[string]bool, 2)
+	for rows.Next() {
+		var accountID string
+		if err := rows.Scan(&accountID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan account: %w", err)
+		}
+		open[accountID] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate accounts: %w", err)
+	}
+	for _, accountID := range accountIDs {
+		if !open[accountID] {
+			return fmt.Errorf("account not found: %s", accountID)
+		}
+	}
+
+	rows, err = exec.QueryContext(ctx, `
+		WITH RECURSIVE ancestors AS (
+			SELECT account_id AS start_id, account_id, parent_account_id
+			FROM exchange.accounts
+			WHERE account_id = ANY($1)
+			UNION ALL
+			SELECT ancestors.start_id, a.account_id, a.parent_account_id
+			FROM exchange.accounts a
+			JOIN ancestors ON a.account_id = ancestors.parent_account_id
+		)
+		SELECT start_id, account_id FROM ancestors WHERE parent_account_id IS NULL
+	`, pq.Array(accountIDs))
+	if err != nil {
+		return fmt.Errorf("failed to resolve account hierarchy: %w", err)
+	}
+	roots := make(map[string]string, 2)
+	for rows.Next() {
+		var accountID, rootID string
+		if err := rows.Scan(&accountID, &rootID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan account hierarchy: %w", err)
+		}
+		roots[accountID] = rootID
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate account hierarchy: %w", err)
+	}
+
+	if roots[transfer.FromAccountID] != roots[transfer.ToAccountID] {
+		return fmt.Errorf("%w: %s and %s", interfaces.ErrTransferOutsideHierarchy, transfer.FromAccountID, transfer.ToAccountID)
+	}
+	return nil
+}
+
+func (r *PostgresBalanceRepository) GetTransfer(ctx context.Context, transferID string) (*models.Transfer, error) {
+	query := `SELECT transfer_id, from_account_id, to_account_id, symbol, amount, created_at, metadata
+		FROM exchange.transfers WHERE transfer_id = $1`
+	// Metadata is nullable, which json.RawMessage cannot scan
+	transfer := &models.Transfer{}
+	var metadata []byte
+	err := r.db.QueryRowContext(ctx, query, transferID).Scan(&transfer.TransferID, &transfer.FromAccountID,
+		&transfer.ToAccountID, &transfer.Symbol, &transfer.Amount, &transfer.CreatedAt, &metadata)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("transfer not found: %s", transferID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+	transfer.Metadata = metadata
+	return transfer, nil
+}
+
+func (r *PostgresBalanceRepository) GetLedger(ctx context.Context, accountID, symbol string, limit int) ([]*models.LedgerEntry, error) {
+	query := `SELECT entry_id, transfer_id, account_id, symbol, amount, balance_after, created_at
+		FROM exchange.ledger_entries WHERE account_id = $1 AND symbol = $2 ORDER BY entry_id DESC`
+	args := []interface{}{accountID, symbol}
+	if limit > 0 {
+		query += " LIMIT $3"
+		args = append(args, limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.WithError(err).WithField("account_id", accountID).Error("Failed to get ledger")
+		return nil, fmt.Errorf("failed to get ledger: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*models.LedgerEntry{}
+	for rows.Next() {
+		entry := &models.LedgerEntry{}
+		if err := rows.Scan(&entry.EntryID, &entry.TransferID, &entry.AccountID, &entry.Symbol,
+			&entry.Amount, &entry.BalanceAfter, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan ledger entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate ledger: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *PostgresBalanceRepository) GetHierarchyBalances(ctx context.Context, accountID string) ([]*models.AggregatedBalance, error) {
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT account_id FROM exchange.accounts WHERE account_id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT a.account_id
+			FROM exchange.accounts a
+			JOIN tree ON a.parent_account_id = tree.account_id
+			WHERE a.deleted_at IS NULL
+		)
+		SELECT b.symbol, SUM(b.available_balance), SUM(b.locked_balance), SUM(b.total_balance),
+			   COUNT(DISTINCT b.account_id)
+		FROM tree
+		JOIN exchange.balances b ON b.account_id = tree.account_id
+		GROUP BY b.symbol
+		ORDER BY b.symbol
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, accountID)
+	if err != nil {
+		r.logger.WithError(err).WithField("account_id", accountID).Error("Failed to aggregate hierarchy balances")
+		return nil, fmt.Errorf("failed to aggregate hierarchy balances: %w", err)
+	}
+	defer rows.Close()
+
+	balances := []*models.AggregatedBalance{}
+	for rows.Next() {
+		balance := &models.AggregatedBalance{}
+		if err := rows.Scan(&balance.Symbol, &balance.AvailableBalance, &balance.LockedBalance,
+			&balance.TotalBalance, &balance.AccountCount); err != nil {
+			return nil, fmt.Errorf("failed to scan aggregated balance: %w", err)
+		}
+		balances = append(balances, balance)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate aggregated balances: %w", err)
+	}
+	return balances, nil
+}
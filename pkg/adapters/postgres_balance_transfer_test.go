@@ -0,0 +1,216 @@
+package adapters
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// TestValidateTransfer tests rejection of malformed transfers before any database work
+func TestValidateTransfer(t *testing.T) {
+	valid := func() *models.Transfer {
+		return &models.Transfer{
+			TransferID:    "tr-1",
+			FromAccountID: "master",
+			ToAccountID:   "sub-1",
+			Symbol:        "USD",
+			Amount:        decimal.RequireFromString("10.5"),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*models.Transfer)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(*models.Transfer) {}},
+		{name: "missing transfer ID", modify: func(tr *models.Transfer) { tr.TransferID = "" }, wantErr: true},
+		{name: "missing symbol", modify: func(tr *models.Transfer) { tr.Symbol = "" }, wantErr: true},
+		{name: "zero amount", modify: func(tr *models.Transfer) { tr.Amount = decimal.Zero }, wantErr: true},
+		{name: "negative amount", modify: func(tr *models.Transfer) { tr.Amount = decimal.NewFromInt(-1) }, wantErr: true},
+		{name: "missing destination", modify: func(tr *models.Transfer) { tr.ToAccountID = "" }, wantErr: true},
+		{name: "same account", modify: func(tr *models.Transfer) { tr.ToAccountID = tr.FromAccountID }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfer := valid()
+			tt.modify(transfer)
+			err := validateTransfer(transfer)
+			if tt.wantErr {
+				if !errors.Is(err, interfaces.ErrInvalidTransfer) {
+					t.Errorf("validateTransfer() = %v, expected ErrInvalidTransfer", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("validateTransfer() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestGetTransferScansNullMetadata tests reading back a transfer recorded without metadata
+func TestGetTransferScansNullMetadata(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	db := openStubDB(t,
+		[]string{"transfer_id", "from_account_id", "to_account_id", "symbol", "amount", "created_at", "metadata"},
+		[]driver.Value{"tr-1", "master", "sub-1", "USD", "10.5", createdAt, nil},
+	)
+	repo := NewPostgresBalanceRepository(db, false, logger)
+
+	transfer, err := repo.GetTransfer(context.Background(), "tr-1")
+	if err != nil {
+		t.Fatalf("GetTransfer() unexpected error: %v", err)
+	}
+	if transfer.Metadata != nil {
+		t.Errorf("Metadata = %s, expected nil", transfer.Metadata)
+	}
+	if !transfer.Amount.Equal(decimal.RequireFromString("10.5")) || transfer.ToAccountID != "sub-1" {
+		t.Errorf("GetTransfer() = %+v", transfer)
+	}
+}
+
+// TestTransfer tests the transfer transaction: both accounts checked, balances locked in
+// account order, the source debited only when it has the funds, and a ledger entry per account
+// that nets to zero
+func TestTransfer(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	returning := []string{"balance_id", "available_balance", "locked_balance", "total_balance"}
+
+	tests := []struct {
+		name        string
+		open        [][]driver.Value
+		roots       [][]driver.Value
+		balances    [][]driver.Value
+		expectedErr error
+		wantErr     bool
+		sequence    []string
+	}{
+		{
+			name:     "within hierarchy",
+			open:     [][]driver.Value{{"master"}, {"sub-1"}},
+			roots:    [][]driver.Value{{"master", "master"}, {"sub-1", "master"}},
+			balances: [][]driver.Value{{"master", "100"}, {"sub-1", "0"}},
+			sequence: []string{
+				"BEGIN",
+				"ORDER BY account_id FOR SHARE",
+				"WITH RECURSIVE ancestors",
+				"ON CONFLICT (account_id, symbol) DO NOTHING",
+				"ORDER BY account_id FOR UPDATE",
+				"INSERT INTO exchange.transfers",
+				"UPDATE exchange.balances",
+				"INSERT INTO exchange.ledger_entries",
+				"UPDATE exchange.balances",
+				"INSERT INTO exchange.ledger_entries",
+				"COMMIT",
+			},
+		},
+		{
+			name:        "outside hierarchy",
+			open:        [][]driver.Value{{"master"}, {"sub-1"}},
+			roots:       [][]driver.Value{{"master", "master"}, {"sub-1", "other"}},
+			expectedErr: interfaces.ErrTransferOutsideHierarchy,
+			sequence:    []string{"BEGIN", "FOR SHARE", "WITH RECURSIVE ancestors", "ROLLBACK"},
+		},
+		{
+			name:        "insufficient funds",
+			open:        [][]driver.Value{{"master"}, {"sub-1"}},
+			roots:       [][]driver.Value{{"master", "master"}, {"sub-1", "master"}},
+			balances:    [][]driver.Value{{"master", "39.99"}, {"sub-1", "0"}},
+			expectedErr: interfaces.ErrInsufficientFunds,
+			sequence:    []string{"BEGIN", "FOR SHARE", "WITH RECURSIVE ancestors", "FOR UPDATE", "ROLLBACK"},
+		},
+		{
+			name:        "no source balance",
+			open:        [][]driver.Value{{"master"}, {"sub-1"}},
+			roots:       [][]driver.Value{{"master", "master"}, {"sub-1", "master"}},
+			balances:    [][]driver.Value{{"sub-1", "0"}},
+			expectedErr: interfaces.ErrInsufficientFunds,
+			sequence:    []string{"BEGIN", "FOR SHARE", "WITH RECURSIVE ancestors", "FOR UPDATE", "ROLLBACK"},
+		},
+		{
+			name:     "closed destination",
+			open:     [][]driver.Value{{"master"}},
+			wantErr:  true,
+			sequence: []string{"BEGIN", "FOR SHARE", "ROLLBACK"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, script := openScriptedDB(t)
+			script.on("FOR SHARE", []string{"account_id"}, tt.open...)
+			script.on("WITH RECURSIVE ancestors", []string{"start_id", "account_id"}, tt.roots...)
+			script.on("FOR UPDATE", []string{"account_id", "available_balance"}, tt.balances...)
+			script.on("UPDATE exchange.balances", returning, []driver.Value{"bal-master", "60", "0", "60"})
+			script.on("UPDATE exchange.balances", returning, []driver.Value{"bal-sub-1", "40", "0", "40"})
+			repo := NewPostgresBalanceRepository(db, false, logger)
+
+			err := repo.Transfer(context.Background(), &models.Transfer{
+				TransferID:    "tr-1",
+				FromAccountID: "master",
+				ToAccountID:   "sub-1",
+				Symbol:        "USD",
+				Amount:        decimal.NewFromInt(40),
+			})
+			switch {
+			case tt.expectedErr != nil:
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Transfer() error = %v, expected %v", err, tt.expectedErr)
+				}
+			case tt.wantErr:
+				if err == nil {
+					t.Error("Transfer() expected error")
+				}
+			case err != nil:
+				t.Errorf("Transfer() unexpected error: %v", err)
+			}
+
+			statements := script.statements()
+			next := 0
+			for _, statement := range statements {
+				if next < len(tt.sequence) && strings.Contains(statement, tt.sequence[next]) {
+					next++
+				}
+			}
+			if next != len(tt.sequence) {
+				t.Errorf("statements = %q, expected in order %q", statements, tt.sequence)
+			}
+
+			entries := script.find("INSERT INTO exchange.ledger_entries")
+			if tt.expectedErr != nil || tt.wantErr {
+				if len(entries) != 0 {
+					t.Errorf("ledger entries = %d, expected none", len(entries))
+				}
+				return
+			}
+			if len(entries) != 2 {
+				t.Fatalf("ledger entries = %d, expected 2", len(entries))
+			}
+			sum := decimal.Zero
+			for _, entry := range entries {
+				sum = sum.Add(decimal.RequireFromString(entry.args[3].(string)))
+			}
+			if !sum.IsZero() {
+				t.Errorf("ledger entries sum to %s, expected 0", sum)
+			}
+			if entries[0].args[1] != "master" || entries[0].args[4] != "60" {
+				t.Errorf("source ledger entry args = %v, expected master with balance after 60", entries[0].args)
+			}
+		})
+	}
+}
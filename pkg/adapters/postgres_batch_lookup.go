@@ -0,0 +1,18 @@
+package adapters
+
+// missingKeys returns the requested keys absent from found, preserving request order
+// and dropping duplicates
+func missingKeys[T any](keys []string, found map[string]T) []string {
+	missing := []string{}
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if _, ok := found[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
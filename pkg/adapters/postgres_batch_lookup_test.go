@@ -0,0 +1,31 @@
+package adapters
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestMissingKeys tests reporting of IDs absent from batch lookups
+func TestMissingKeys(t *testing.T) {
+	found := map[string]int{"a": 1, "c": 3}
+
+	tests := []struct {
+		name     string
+		keys     []string
+		expected []string
+	}{
+		{name: "all found", keys: []string{"a", "c"}, expected: []string{}},
+		{name: "some missing", keys: []string{"a", "b", "c", "d"}, expected: []string{"b", "d"}},
+		{name: "duplicates reported once", keys: []string{"b", "a", "b"}, expected: []string{"b"}},
+		{name: "no keys", keys: nil, expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := missingKeys(tt.keys, found)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("missingKeys(%v) = %v, expected %v", tt.keys, result, tt.expected)
+			}
+		})
+	}
+}
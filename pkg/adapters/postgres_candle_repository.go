@@ -0,0 +1,155 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// candleAggregateQuery buckets trades with date_bin anchored at the Unix epoch so that
+// bucket boundaries are stable across queries. Open and close are the first and last
+// prices by execution time, with trade_id as a tie-breaker. Maker-side rows written by the
+// matching engine duplicate their taker row and are excluded so volume is not double counted.
+const candleAggregateQuery = `
+	SELECT date_bin(make_interval(secs => $2), executed_at, TIMESTAMPTZ 'epoch') AS open_time,
+		(array_agg(price ORDER BY executed_at ASC, trade_id ASC))[1] AS open,
+		MAX(price) AS high,
+		MIN(price) AS low,
+		(array_agg(price ORDER BY executed_at DESC, trade_id DESC))[1] AS close,
+		SUM(quantity) AS volume,
+		COUNT(*) AS trade_count
+	FROM exchange.trades
+	WHERE symbol = $1 AND executed_at >= $3 AND executed_at < $4
+		AND metadata->>'liquidity' IS DISTINCT FROM 'MAKER'
+	GROUP BY open_time
+`
+
+type PostgresCandleRepository struct {
+	db     *sql.DB
+	logger *logrus.Logger
+}
+
+func NewPostgresCandleRepository(db *sql.DB, logger *logrus.Logger) interfaces.CandleRepository {
+	return &PostgresCandleRepository{db: db, logger: logger}
+}
+
+// candleIntervalSeconds validates a candle interval and converts it to whole seconds
+func candleIntervalSeconds(interval time.Duration) (int64, error) {
+	if interval < models.MinCandleInterval || interval > models.MaxCandleInterval {
+		return 0, fmt.Errorf("candle interval must be between %s and %s (got: %s)",
+			models.MinCandleInterval, models.MaxCandleInterval, interval)
+	}
+	if interval%time.Second != 0 {
+		return 0, fmt.Errorf("candle interval must be a whole number of seconds (got: %s)", interval)
+	}
+	return int64(interval / time.Second), nil
+}
+
+func (r *PostgresCandleRepository) GetCandles(ctx context.Context, query *models.CandleQuery) ([]*models.Candle, error) {
+	seconds, err := candleIntervalSeconds(query.Interval)
+	if err != nil {
+		return nil, err
+	}
+	if !query.To.After(query.From) {
+		return nil, fmt.Errorf("candle range end must be after start")
+	}
+
+	var sqlQuery string
+	if query.Materialized {
+		sqlQuery = `SELECT open_time, open, high, low, close, volume, trade_count
+			FROM exchange.candles
+			WHERE symbol = $1 AND interval_seconds = $2 AND open_time >= $3 AND open_time < $4`
+	} else {
+		sqlQuery = candleAggregateQuery
+	}
+	sqlQuery += " ORDER BY open_time ASC"
+
+	args := []interface{}{query.Symbol, seconds, query.From, query.To}
+	if query.Limit > 0 {
+		sqlQuery += " LIMIT $5"
+		args = append(args, query.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to get candles")
+		return nil, fmt.Errorf("failed to get candles: %w", err)
+	}
+	defer rows.Close()
+
+	candles := []*models.Candle{}
+	for rows.Next() {
+		candle := &models.Candle{Symbol: query.Symbol, Interval: query.Interval}
+		if err := rows.Scan(&candle.OpenTime, &candle.Open, &candle.High, &candle.Low,
+			&candle.Close, &candle.Volume, &candle.TradeCount); err != nil {
+			return nil, fmt.Errorf("failed to scan candle: %w", err)
+		}
+		candle.CloseTime = candle.OpenTime.Add(query.Interval)
+		candles = append(candles, candle)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate candles: %w", err)
+	}
+
+	return candles, nil
+}
+
+func (r *PostgresCandleRepository) Materialize(ctx context.Context, symbol string, interval time.Duration, from, to time.Time) error {
+	seconds, err := candleIntervalSeconds(interval)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO exchange.candles (
+			symbol, interval_seconds, open_time, open, high, low, close, volume, trade_count, updated_at
+		)
+		SELECT $1, $2, agg.open_time, agg.open, agg.high, agg.low, agg.close, agg.volume, agg.trade_count, NOW()
+		FROM (` + candleAggregateQuery + `) agg
+		ON CONFLICT (symbol, interval_seconds, open_time) DO UPDATE SET
+			open = EXCLUDED.open, high = EXCLUDED.high, low = EXCLUDED.low, close = EXCLUDED.close,
+			volume = EXCLUDED.volume, trade_count = EXCLUDED.trade_count, updated_at = EXCLUDED.updated_at
+	`
+
+	result, err := r.db.ExecContext(ctx, query, symbol, seconds, from, to)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to materialize candles")
+		return fmt.Errorf("failed to materialize candles: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	r.logger.WithFields(logrus.Fields{
+		"symbol":   symbol,
+		"interval": interval.String(),
+		"candles":  rows,
+	}).Debug("Candles materialized")
+
+	return nil
+}
+
+func (r *PostgresCandleRepository) Refresh(ctx context.Context, symbol string, interval time.Duration) error {
+	seconds, err := candleIntervalSeconds(interval)
+	if err != nil {
+		return err
+	}
+
+	// Recompute from the last stored bucket, which may still have been open when it was written
+	var lastOpen sql.NullTime
+	query := `SELECT MAX(open_time) FROM exchange.candles WHERE symbol = $1 AND interval_seconds = $2`
+	if err := r.db.QueryRowContext(ctx, query, symbol, seconds).Scan(&lastOpen); err != nil {
+		r.logger.WithError(err).Error("Failed to get last materialized candle")
+		return fmt.Errorf("failed to get last materialized candle: %w", err)
+	}
+
+	from := time.Unix(0, 0).UTC()
+	if lastOpen.Valid {
+		from = lastOpen.Time
+	}
+
+	return r.Materialize(ctx, symbol, interval, from, time.Now().Add(interval))
+}
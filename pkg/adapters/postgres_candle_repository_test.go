@@ -0,0 +1,43 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCandleIntervalSeconds tests candle interval validation
+func TestCandleIntervalSeconds(t *testing.T) {
+	tests := []struct {
+		name      string
+		interval  time.Duration
+		expected  int64
+		expectErr bool
+	}{
+		{name: "one second", interval: time.Second, expected: 1},
+		{name: "one minute", interval: time.Minute, expected: 60},
+		{name: "fifteen minutes", interval: 15 * time.Minute, expected: 900},
+		{name: "one day", interval: 24 * time.Hour, expected: 86400},
+		{name: "below minimum", interval: 500 * time.Millisecond, expectErr: true},
+		{name: "above maximum", interval: 25 * time.Hour, expectErr: true},
+		{name: "fractional seconds", interval: 1500 * time.Millisecond, expectErr: true},
+		{name: "zero", interval: 0, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := candleIntervalSeconds(tt.interval)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("candleIntervalSeconds(%s) expected error, got %d", tt.interval, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("candleIntervalSeconds(%s) unexpected error: %v", tt.interval, err)
+			}
+			if result != tt.expected {
+				t.Errorf("candleIntervalSeconds(%s) = %d, expected %d", tt.interval, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	changeListenerMinReconnect = time.Second
+	changeListenerMaxReconnect = 30 * time.Second
+	changeListenerPingInterval = 60 * time.Second
+
+	changeCatchUpLimit = 10000
+	changeBufferSize   = 256
+
+	// changeCursorQuery reads the oldest transaction still running; every transaction below it
+	// has committed or aborted
+	changeCursorQuery = "SELECT pg_snapshot_xmin(pg_current_snapshot())::text"
+)
+
+// changeCatchUpQueries select rows written by transactions at or above the cursor $1, shaped
+// as id, account_id, symbol, order_id, status, changed_at
+var changeCatchUpQueries = map[models.ChangeTable]string{
+	models.ChangeTableOrders: `SELECT order_id, account_id, symbol, NULL, status, changed_at
+		FROM exchange.orders WHERE changed_xid >= $1::xid8 ORDER BY changed_xid LIMIT $2`,
+	models.ChangeTableTrades: `SELECT trade_id, account_id, symbol, order_id, NULL, changed_at
+		FROM exchange.trades WHERE changed_xid >= $1::xid8 ORDER BY changed_xid LIMIT $2`,
+	models.ChangeTableBalances: `SELECT balance_id, account_id, symbol, NULL, NULL, changed_at
+		FROM exchange.balances WHERE changed_xid >= $1::xid8 ORDER BY changed_xid LIMIT $2`,
+}
+
+// changeCursor is a snapshot xmin together with the listener's disconnect count when it was
+// read
+type changeCursor struct {
+	xmin        string
+	disconnects int64
+}
+
+// PostgresChangeListener delivers the change feed raised by the schema/004_change_notify.sql
+// triggers. It listens on a dedicated connection, separate from the repository pool, which
+// reconnects automatically. Notifications sent while disconnected are lost, so after each
+// reconnect the listener queries each table for rows written at or above a transaction cursor
+// and delivers them as CATCH_UP notifications.
+//
+// The cursor is a snapshot xmin, so it follows commit order rather than transaction start
+// times and long transactions are never skipped. It advances on the keepalive ping: an xmin
+// becomes the cursor once a later ping succeeds with no disconnect in between, by which time
+// every transaction below it has committed and had its notifications delivered. A long
+// writing transaction holds the xmin back, so catch-ups after it repeat more rows.
+//
+// Delivery is at least once: consumers should treat notifications as idempotent hints and may
+// see a change both from the feed and a catch-up.
+type PostgresChangeListener struct {
+	connStr string
+	db      *sql.DB
+	logger  *logrus.Logger
+}
+
+func NewPostgresChangeListener(connStr string, db *sql.DB, logger *logrus.Logger) *PostgresChangeListener {
+	return &PostgresChangeListener{
+		connStr: connStr,
+		db:      db,
+		logger:  logger,
+	}
+}
+
+// changeChannel returns the NOTIFY channel for a table
+func changeChannel(table models.ChangeTable) string {
+	return fmt.Sprintf("exchange_%s_changes", table)
+}
+
+func decodeChangeNotification(payload string) (models.ChangeNotification, error) {
+	var notification models.ChangeNotification
+	if err := json.Unmarshal([]byte(payload), &notification); err != nil {
+		return notification, fmt.Errorf("failed to decode change notification: %w", err)
+	}
+	return notification, nil
+}
+
+// Listen subscribes to the given tables (all of them when none are given) and returns a channel
+// of notifications. The channel is closed, and the dedicated connection released, when the
+// context is cancelled.
+func (l *PostgresChangeListener) Listen(ctx context.Context, tables ...models.ChangeTable) (<-chan models.ChangeNotification, error) {
+	if len(tables) == 0 {
+		tables = []models.ChangeTable{models.ChangeTableOrders, models.ChangeTableTrades, models.ChangeTableBalances}
+	}
+	for _, table := range tables {
+		if _, ok := changeCatchUpQueries[table]; !ok {
+			return nil, fmt.Errorf("unsupported change table: %s", table)
+		}
+	}
+
+	// Read before listening, so the first catch-up covers anything committed meanwhile
+	disconnects := &atomic.Int64{}
+	start, err := l.readCursor(ctx, disconnects)
+	if err != nil {
+		return nil, err
+	}
+
+	listener := pq.NewListener(l.connStr, changeListenerMinReconnect, changeListenerMaxReconnect,
+		func(event pq.ListenerEventType, err error) {
+			switch event {
+			case pq.ListenerEventDisconnected:
+				disconnects.Add(1)
+				l.logger.WithError(err).Warn("Change listener disconnected")
+			case pq.ListenerEventConnectionAttemptFailed:
+				l.logger.WithError(err).Warn("Change listener reconnect attempt failed")
+			case pq.ListenerEventReconnected:
+				l.logger.Info("Change listener reconnected")
+			}
+		})
+
+	for _, table := range tables {
+		if err := listener.Listen(changeChannel(table)); err != nil {
+			listener.Close()
+			return nil, fmt.Errorf("failed to listen for %s changes: %w", table, err)
+		}
+	}
+
+	out := make(chan models.ChangeNotification, changeBufferSize)
+	go l.run(ctx, listener, tables, start.xmin, disconnects, out)
+	return out, nil
+}
+
+// readCursor reads the current snapshot xmin, noting the disconnect count beforehand
+func (l *PostgresChangeListener) readCursor(ctx context.Context, disconnects *atomic.Int64) (changeCursor, error) {
+	cursor := changeCursor{disconnects: disconnects.Load()}
+	if err := l.db.QueryRowContext(ctx, changeCursorQuery).Scan(&cursor.xmin); err != nil {
+		return cursor, fmt.Errorf("failed to read change cursor: %w", err)
+	}
+	return cursor, nil
+}
+
+// confirmCursor returns the catch-up cursor after a successful ping. pending, read before the
+// previous ping, replaces cursor only if the listener has not disconnected since it was read:
+// every transaction below it finished before that ping, which flushed their notifications.
+func confirmCursor(cursor string, pending changeCursor, disconnects int64) string {
+	if pending.xmin == "" || pending.disconnects != disconnects {
+		return cursor
+	}
+	return pending.xmin
+}
+
+// run delivers notifications until ctx is cancelled, catching up from cursor after each
+// reconnect
+func (l *PostgresChangeListener) run(ctx context.Context, listener *pq.Listener, tables []models.ChangeTable, cursor string, disconnects *atomic.Int64, out chan<- models.ChangeNotification) {
+	defer close(out)
+	defer listener.Close()
+
+	ping := time.NewTicker(changeListenerPingInterval)
+	defer ping.Stop()
+
+	// pending is the xmin awaiting confirmation; each ping confirms the one read before the
+	// previous ping and reads the next
+	var pending changeCursor
+	pinged := make(chan changeCursor)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case n, ok := <-listener.Notify:
+			if !ok {
+				return
+			}
+
+			// A nil notification follows a reconnect; anything sent meanwhile was dropped
+			if n == nil {
+				for _, table := range tables {
+					if err := l.catchUp(ctx, table, cursor, out); err != nil {
+						l.logger.WithError(err).WithField("table", table).Error("Failed to catch up on changes")
+					}
+				}
+				continue
+			}
+
+			notification, err := decodeChangeNotification(n.Extra)
+			if err != nil {
+				l.logger.WithError(err).WithField("channel", n.Channel).Warn("Dropping malformed change notification")
+				continue
+			}
+			select {
+			case out <- notification:
+			case <-ctx.Done():
+				return
+			}
+
+		case <-ping.C:
+			go func() {
+				next, err := l.readCursor(ctx, disconnects)
+				if err != nil {
+					l.logger.WithError(err).Debug("Failed to read change cursor")
+					return
+				}
+				if err := listener.Ping(); err != nil {
+					l.logger.WithError(err).Debug("Change listener ping failed")
+					return
+				}
+				select {
+				case pinged <- next:
+				case <-ctx.Done():
+				}
+			}()
+
+		case next := <-pinged:
+			cursor = confirmCursor(cursor, pending, disconnects.Load())
+			pending = next
+		}
+	}
+}
+
+// catchUp delivers rows of a table written by transactions at or above cursor
+func (l *PostgresChangeListener) catchUp(ctx context.Context, table models.ChangeTable, cursor string, out chan<- models.ChangeNotification) error {
+	rows, err := l.db.QueryContext(ctx, changeCatchUpQueries[table], cursor, changeCatchUpLimit)
+	if err != nil {
+		return fmt.Errorf("failed to query %s changes: %w", table, err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var id string
+		var accountID, symbol, orderID, status sql.NullString
+		var changedAt time.Time
+		if err := rows.Scan(&id, &accountID, &symbol, &orderID, &status, &changedAt); err != nil {
+			return fmt.Errorf("failed to scan %s change: %w", table, err)
+		}
+
+		notification := models.ChangeNotification{
+			Table:     table,
+			Operation: models.ChangeOperationCatchUp,
+			ID:        id,
+			AccountID: accountID.String,
+			Symbol:    symbol.String,
+			OrderID:   orderID.String,
+			Status:    status.String,
+			ChangedAt: changedAt,
+		}
+		select {
+		case out <- notification:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate %s changes: %w", table, err)
+	}
+
+	if count == changeCatchUpLimit {
+		l.logger.WithFields(logrus.Fields{
+			"table": table,
+			"limit": changeCatchUpLimit,
+		}).Warn("Change catch-up hit its limit; consumers should resynchronise from the repositories")
+	}
+	l.logger.WithFields(logrus.Fields{
+		"table": table,
+		"count": count,
+	}).Info("Caught up on changes after reconnect")
+
+	return nil
+}
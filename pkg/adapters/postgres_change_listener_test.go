@@ -0,0 +1,154 @@
+package adapters
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// TestDecodeChangeNotification tests decoding payloads raised by the change feed triggers
+func TestDecodeChangeNotification(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		expected models.ChangeNotification
+		wantErr  bool
+	}{
+		{
+			name:    "order status update",
+			payload: `{"table":"orders","operation":"UPDATE","id":"order-1","account_id":"acc-1","symbol":"BTC-USD","status":"FILLED","previous_status":"PARTIAL","changed_at":"2025-01-02T03:04:05.123456+00:00"}`,
+			expected: models.ChangeNotification{
+				Table:          models.ChangeTableOrders,
+				Operation:      models.ChangeOperationUpdate,
+				ID:             "order-1",
+				AccountID:      "acc-1",
+				Symbol:         "BTC-USD",
+				Status:         "FILLED",
+				PreviousStatus: "PARTIAL",
+				ChangedAt:      time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.UTC),
+			},
+		},
+		{
+			name:    "trade insert",
+			payload: `{"table":"trades","operation":"INSERT","id":"trade-1","account_id":"acc-1","symbol":"BTC-USD","order_id":"order-1","changed_at":"2025-01-02T03:04:05+00:00"}`,
+			expected: models.ChangeNotification{
+				Table:     models.ChangeTableTrades,
+				Operation: models.ChangeOperationInsert,
+				ID:        "trade-1",
+				AccountID: "acc-1",
+				Symbol:    "BTC-USD",
+				OrderID:   "order-1",
+				ChangedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			},
+		},
+		{
+			name:    "malformed payload",
+			payload: `{"table":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeChangeNotification(tt.payload)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("decodeChangeNotification() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeChangeNotification() unexpected error: %v", err)
+			}
+			if !got.ChangedAt.Equal(tt.expected.ChangedAt) {
+				t.Errorf("ChangedAt = %v, expected %v", got.ChangedAt, tt.expected.ChangedAt)
+			}
+			got.ChangedAt = tt.expected.ChangedAt
+			if got != tt.expected {
+				t.Errorf("decodeChangeNotification() = %+v, expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestChangeChannel tests that channel names match those raised by exchange.notify_change()
+func TestChangeChannel(t *testing.T) {
+	for table, expected := range map[models.ChangeTable]string{
+		models.ChangeTableOrders:   "exchange_orders_changes",
+		models.ChangeTableTrades:   "exchange_trades_changes",
+		models.ChangeTableBalances: "exchange_balances_changes",
+	} {
+		if channel := changeChannel(table); channel != expected {
+			t.Errorf("changeChannel(%s) = %q, expected %q", table, channel, expected)
+		}
+		// Catch-up must follow commit order through the transaction stamped on each row
+		if query, ok := changeCatchUpQueries[table]; !ok || !strings.Contains(query, "WHERE changed_xid >= $1::xid8") {
+			t.Errorf("catch-up query for %s = %q, expected to filter on changed_xid", table, query)
+		}
+	}
+}
+
+// TestConfirmCursor tests that a pending xmin only becomes the catch-up cursor when the
+// listener stayed connected from reading it until a later ping
+func TestConfirmCursor(t *testing.T) {
+	tests := []struct {
+		name        string
+		pending     changeCursor
+		disconnects int64
+		expected    string
+	}{
+		{name: "nothing pending", disconnects: 0, expected: "100"},
+		{name: "connected throughout", pending: changeCursor{xmin: "250", disconnects: 2}, disconnects: 2, expected: "250"},
+		{name: "disconnected since read", pending: changeCursor{xmin: "250", disconnects: 2}, disconnects: 3, expected: "100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := confirmCursor("100", tt.pending, tt.disconnects); got != tt.expected {
+				t.Errorf("confirmCursor() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestChangeCatchUp tests that catch-up queries from the cursor and delivers CATCH_UP
+// notifications
+func TestChangeCatchUp(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	changedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	db, script := openScriptedDB(t)
+	script.on("FROM exchange.orders", []string{"order_id", "account_id", "symbol", "order_id", "status", "changed_at"},
+		[]driver.Value{"order-1", "acc-1", "BTC-USD", nil, "FILLED", changedAt})
+	listener := NewPostgresChangeListener("", db, logger)
+
+	out := make(chan models.ChangeNotification, 1)
+	if err := listener.catchUp(context.Background(), models.ChangeTableOrders, "731", out); err != nil {
+		t.Fatalf("catchUp() unexpected error: %v", err)
+	}
+
+	calls := script.find("FROM exchange.orders")
+	if len(calls) != 1 || calls[0].args[0] != "731" {
+		t.Errorf("catch-up calls = %v, expected one from cursor 731", calls)
+	}
+	notification := <-out
+	expected := models.ChangeNotification{
+		Table:     models.ChangeTableOrders,
+		Operation: models.ChangeOperationCatchUp,
+		ID:        "order-1",
+		AccountID: "acc-1",
+		Symbol:    "BTC-USD",
+		Status:    "FILLED",
+		ChangedAt: changedAt,
+	}
+	if notification != expected {
+		t.Errorf("catch-up notification = %+v, expected %+v", notification, expected)
+	}
+}
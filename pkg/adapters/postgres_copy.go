@@ -0,0 +1,143 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// copyRowFunc returns the column values for the i-th row of a batch
+type copyRowFunc func(i int) []interface{}
+
+// copyChunkHook runs in a chunk's transaction after its rows have been copied
+type copyChunkHook func(ctx context.Context, tx *sql.Tx, chunk [2]int) error
+
+// batchChunks splits n rows into [start, end) ranges of at most chunkSize rows
+func batchChunks(n, chunkSize int) [][2]int {
+	if chunkSize <= 0 {
+		chunkSize = models.DefaultBatchChunkSize
+	}
+	chunks := [][2]int{}
+	for start := 0; start < n; start += chunkSize {
+		end := start + chunkSize
+		if end > n {
+			end = n
+		}
+		chunks = append(chunks, [2]int{start, end})
+	}
+	return chunks
+}
+
+// copyIn bulk inserts n rows into exchange.<table> using the COPY protocol.
+// In transactional mode the first failing chunk aborts the whole batch; otherwise
+// each chunk commits on its own and failures are reported per chunk. A non-nil afterChunk
+// runs in the same transaction as each chunk.
+func copyIn(ctx context.Context, db *sql.DB, logger *logrus.Logger, table string, columns []string,
+	n int, opts *models.BatchOptions, row copyRowFunc, afterChunk copyChunkHook) (*models.BatchResult, error) {
+	if opts == nil {
+		opts = &models.BatchOptions{}
+	}
+	result := &models.BatchResult{}
+	chunks := batchChunks(n, opts.ChunkSize)
+
+	if opts.Transactional {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback()
+
+		for i, chunk := range chunks {
+			if err := copyChunkWithHook(ctx, tx, table, columns, chunk, row, afterChunk); err != nil {
+				chunkErr := &models.BatchChunkError{Chunk: i, Start: chunk[0], End: chunk[1], Err: err}
+				result.Failed = n
+				result.Errors = append(result.Errors, chunkErr)
+				logger.WithError(err).WithField("table", table).Error("Failed to copy batch")
+				return result, fmt.Errorf("failed to copy %s batch: %w", table, chunkErr)
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			result.Failed = n
+			return result, fmt.Errorf("failed to commit %s batch: %w", table, err)
+		}
+		result.Inserted = n
+		return result, nil
+	}
+
+	for i, chunk := range chunks {
+		err := func() error {
+			tx, err := db.BeginTx(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("failed to begin transaction: %w", err)
+			}
+			defer tx.Rollback()
+
+			if err := copyChunkWithHook(ctx, tx, table, columns, chunk, row, afterChunk); err != nil {
+				return err
+			}
+			return tx.Commit()
+		}()
+		if err != nil {
+			logger.WithError(err).WithFields(logrus.Fields{
+				"table": table,
+				"chunk": i,
+			}).Error("Failed to copy batch chunk")
+			result.Failed += chunk[1] - chunk[0]
+			result.Errors = append(result.Errors, &models.BatchChunkError{Chunk: i, Start: chunk[0], End: chunk[1], Err: err})
+			continue
+		}
+		result.Inserted += chunk[1] - chunk[0]
+	}
+
+	if len(result.Errors) > 0 {
+		return result, fmt.Errorf("failed to copy %d of %d %s rows", result.Failed, n, table)
+	}
+	return result, nil
+}
+
+func copyChunkWithHook(ctx context.Context, tx *sql.Tx, table string, columns []string, chunk [2]int, row copyRowFunc, afterChunk copyChunkHook) error {
+	if err := setWriteContext(ctx, tx); err != nil {
+		return err
+	}
+	if err := copyChunk(ctx, tx, table, columns, chunk, row); err != nil {
+		return err
+	}
+	if afterChunk != nil {
+		return afterChunk(ctx, tx, chunk)
+	}
+	return nil
+}
+
+func copyChunk(ctx context.Context, tx *sql.Tx, table string, columns []string, chunk [2]int, row copyRowFunc) error {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyInSchema("exchange", table, columns...))
+	if err != nil {
+		return fmt.Errorf("failed to prepare copy: %w", err)
+	}
+	defer stmt.Close()
+
+	for i := chunk[0]; i < chunk[1]; i++ {
+		if _, err := stmt.ExecContext(ctx, row(i)...); err != nil {
+			return fmt.Errorf("failed to copy row %d: %w", i, err)
+		}
+	}
+
+	// Flush buffered rows
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("failed to flush copy: %w", err)
+	}
+	return nil
+}
+
+// copyJSON converts a JSONB column value for COPY, which would otherwise encode []byte as bytea
+func copyJSON(data json.RawMessage) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
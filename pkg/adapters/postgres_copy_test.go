@@ -0,0 +1,38 @@
+package adapters
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+)
+
+// TestBatchChunks tests splitting bulk inserts into COPY chunks
+func TestBatchChunks(t *testing.T) {
+	tests := []struct {
+		name      string
+		rows      int
+		chunkSize int
+		expected  [][2]int
+	}{
+		{name: "empty batch", rows: 0, chunkSize: 10, expected: [][2]int{}},
+		{name: "single partial chunk", rows: 3, chunkSize: 10, expected: [][2]int{{0, 3}}},
+		{name: "exact multiple", rows: 20, chunkSize: 10, expected: [][2]int{{0, 10}, {10, 20}}},
+		{name: "remainder chunk", rows: 25, chunkSize: 10, expected: [][2]int{{0, 10}, {10, 20}, {20, 25}}},
+		{
+			name:      "default chunk size",
+			rows:      models.DefaultBatchChunkSize + 1,
+			chunkSize: 0,
+			expected:  [][2]int{{0, models.DefaultBatchChunkSize}, {models.DefaultBatchChunkSize, models.DefaultBatchChunkSize + 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := batchChunks(tt.rows, tt.chunkSize)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("batchChunks(%d, %d) = %v, expected %v", tt.rows, tt.chunkSize, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+)
+
+// appendMetadataFilter translates a metadata filter into JSONB predicates that can be
+// served by a GIN index on the metadata column (@> for containment, ?& for key existence).
+// It returns the extended query, arguments and next placeholder index.
+func appendMetadataFilter(sqlQuery string, args []interface{}, argCount int, filter *models.MetadataFilter) (string, []interface{}, int, error) {
+	if filter.IsEmpty() {
+		return sqlQuery, args, argCount, nil
+	}
+
+	if len(filter.Equals) > 0 {
+		data, err := json.Marshal(filter.Equals)
+		if err != nil {
+			return "", nil, 0, fmt.Errorf("failed to marshal metadata filter: %w", err)
+		}
+		sqlQuery += fmt.Sprintf(" AND metadata @> $%d::jsonb", argCount)
+		args = append(args, string(data))
+		argCount++
+	}
+
+	if len(filter.HasKeys) > 0 {
+		sqlQuery += fmt.Sprintf(" AND metadata ?& $%d::text[]", argCount)
+		args = append(args, pq.Array(filter.HasKeys))
+		argCount++
+	}
+
+	if len(filter.Contains) > 0 {
+		if !json.Valid(filter.Contains) {
+			return "", nil, 0, fmt.Errorf("metadata containment filter is not valid JSON")
+		}
+		sqlQuery += fmt.Sprintf(" AND metadata @> $%d::jsonb", argCount)
+		args = append(args, string(filter.Contains))
+		argCount++
+	}
+
+	return sqlQuery, args, argCount, nil
+}
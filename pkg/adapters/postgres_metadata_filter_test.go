@@ -0,0 +1,87 @@
+package adapters
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+)
+
+// TestAppendMetadataFilter tests translation of metadata filters into JSONB predicates
+func TestAppendMetadataFilter(t *testing.T) {
+	tests := []struct {
+		name          string
+		filter        *models.MetadataFilter
+		expectedSQL   string
+		expectedArgs  []interface{}
+		expectedCount int
+		expectErr     bool
+	}{
+		{
+			name:          "nil filter",
+			filter:        nil,
+			expectedSQL:   "WHERE 1=1",
+			expectedArgs:  []interface{}{"acc-1"},
+			expectedCount: 2,
+		},
+		{
+			name: "key equals value",
+			filter: &models.MetadataFilter{
+				Equals: map[string]interface{}{"strategy": "momentum", "run_id": 42},
+			},
+			expectedSQL:   "WHERE 1=1 AND metadata @> $2::jsonb",
+			expectedArgs:  []interface{}{"acc-1", `{"run_id":42,"strategy":"momentum"}`},
+			expectedCount: 3,
+		},
+		{
+			name:          "key exists",
+			filter:        &models.MetadataFilter{HasKeys: []string{"simulation_run"}},
+			expectedSQL:   "WHERE 1=1 AND metadata ?& $2::text[]",
+			expectedArgs:  []interface{}{"acc-1", pq.Array([]string{"simulation_run"})},
+			expectedCount: 3,
+		},
+		{
+			name: "all predicates",
+			filter: &models.MetadataFilter{
+				Equals:   map[string]interface{}{"strategy": "arb"},
+				HasKeys:  []string{"run_id"},
+				Contains: json.RawMessage(`{"tags":["hedge"]}`),
+			},
+			expectedSQL: "WHERE 1=1 AND metadata @> $2::jsonb AND metadata ?& $3::text[] AND metadata @> $4::jsonb",
+			expectedArgs: []interface{}{"acc-1", `{"strategy":"arb"}`,
+				pq.Array([]string{"run_id"}), `{"tags":["hedge"]}`},
+			expectedCount: 5,
+		},
+		{
+			name:      "invalid containment JSON",
+			filter:    &models.MetadataFilter{Contains: json.RawMessage(`{"tags":`)},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlQuery, args, argCount, err := appendMetadataFilter("WHERE 1=1", []interface{}{"acc-1"}, 2, tt.filter)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got query %q", sqlQuery)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sqlQuery != tt.expectedSQL {
+				t.Errorf("query = %q, expected %q", sqlQuery, tt.expectedSQL)
+			}
+			if !reflect.DeepEqual(args, tt.expectedArgs) {
+				t.Errorf("args = %#v, expected %#v", args, tt.expectedArgs)
+			}
+			if argCount != tt.expectedCount {
+				t.Errorf("argCount = %d, expected %d", argCount, tt.expectedCount)
+			}
+		})
+	}
+}
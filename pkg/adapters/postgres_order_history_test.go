@@ -0,0 +1,140 @@
+package adapters
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// TestReplayOrderEvents tests reconstructing orders from history rows shaped like those
+// written by exchange.record_order_event()
+func TestReplayOrderEvents(t *testing.T) {
+	created := &models.OrderEvent{
+		EventID:   1,
+		OrderID:   "order-1",
+		EventType: models.OrderEventCreated,
+		Changes: json.RawMessage(`{"order_id":"order-1","account_id":"acc-1","symbol":"BTC-USD","order_type":"LIMIT",
+			"side":"BUY","quantity":2.5,"price":50000.10,"filled_quantity":0,"average_price":null,"status":"OPEN",
+			"time_in_force":"GTC","created_at":"2025-01-02T10:30:00+00:00","updated_at":"2025-01-02T10:30:00+00:00",
+			"filled_at":null,"cancelled_at":null,"metadata":{"client_order_id":"c-1"}}`),
+	}
+	filled := &models.OrderEvent{
+		EventID:   2,
+		OrderID:   "order-1",
+		EventType: models.OrderEventFilled,
+		Changes: json.RawMessage(`{"filled_quantity":1.0,"average_price":50000.10,
+			"updated_at":"2025-01-02T10:32:05+00:00","filled_at":"2025-01-02T10:32:05+00:00"}`),
+	}
+	partial := &models.OrderEvent{
+		EventID:   3,
+		OrderID:   "order-1",
+		EventType: models.OrderEventStatusChanged,
+		Changes:   json.RawMessage(`{"status":"PARTIALLY_FILLED","updated_at":"2025-01-02T10:32:05.5+00:00"}`),
+	}
+	cancelled := &models.OrderEvent{
+		EventID:   4,
+		OrderID:   "order-1",
+		EventType: models.OrderEventCancelled,
+		Changes: json.RawMessage(`{"status":"CANCELLED","updated_at":"2025-01-02T10:40:00+00:00",
+			"cancelled_at":"2025-01-02T10:40:00+00:00"}`),
+	}
+
+	backfilled := &models.OrderEvent{
+		EventID:   5,
+		OrderID:   "order-1",
+		EventType: models.OrderEventBackfilled,
+		Changes: json.RawMessage(`{"order_id":"order-1","account_id":"acc-1","symbol":"BTC-USD","order_type":"LIMIT",
+			"side":"BUY","quantity":2.5,"price":50000.10,"filled_quantity":1.0,"average_price":50000.10,
+			"status":"PARTIALLY_FILLED","time_in_force":"GTC","created_at":"2025-01-02T10:30:00+00:00",
+			"updated_at":"2025-01-02T10:32:05.5+00:00","filled_at":null,"cancelled_at":null,"metadata":null}`),
+	}
+
+	tests := []struct {
+		name           string
+		events         []*models.OrderEvent
+		expectedStatus models.OrderStatus
+		expectedFilled string
+		cancelled      bool
+		wantErr        bool
+	}{
+		{name: "created", events: []*models.OrderEvent{created}, expectedStatus: models.OrderStatusOpen, expectedFilled: "0"},
+		{name: "after fill", events: []*models.OrderEvent{created, filled}, expectedStatus: models.OrderStatusOpen, expectedFilled: "1"},
+		{name: "partially filled", events: []*models.OrderEvent{created, filled, partial}, expectedStatus: models.OrderStatusPartial, expectedFilled: "1"},
+		{name: "cancelled", events: []*models.OrderEvent{created, filled, partial, cancelled}, expectedStatus: models.OrderStatusCancelled, expectedFilled: "1", cancelled: true},
+		{name: "backfilled", events: []*models.OrderEvent{backfilled, cancelled}, expectedStatus: models.OrderStatusCancelled, expectedFilled: "1", cancelled: true},
+		{name: "missing creation", events: []*models.OrderEvent{filled, partial}, wantErr: true},
+		{name: "no events", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := models.ReplayOrderEvents(tt.events)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("ReplayOrderEvents() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReplayOrderEvents() unexpected error: %v", err)
+			}
+
+			if order.Status != tt.expectedStatus {
+				t.Errorf("Status = %s, expected %s", order.Status, tt.expectedStatus)
+			}
+			if !order.FilledQuantity.Equal(decimal.RequireFromString(tt.expectedFilled)) {
+				t.Errorf("FilledQuantity = %s, expected %s", order.FilledQuantity, tt.expectedFilled)
+			}
+			if !order.Quantity.Equal(decimal.RequireFromString("2.5")) || order.Symbol != "BTC-USD" {
+				t.Errorf("creation fields not preserved: %+v", order)
+			}
+			if (order.CancelledAt != nil) != tt.cancelled {
+				t.Errorf("CancelledAt = %v, expected set: %v", order.CancelledAt, tt.cancelled)
+			}
+			if len(tt.events) > 1 && !order.UpdatedAt.After(time.Date(2025, 1, 2, 10, 30, 0, 0, time.UTC)) {
+				t.Errorf("UpdatedAt = %v, expected a later update", order.UpdatedAt)
+			}
+		})
+	}
+}
+
+// TestGetAsOfMissingHistory tests distinguishing orders without history yet from backfilled orders
+func TestGetAsOfMissingHistory(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	at := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		rows            [][]driver.Value
+		wantUnavailable bool
+	}{
+		{name: "backfilled order", rows: [][]driver.Value{{"BACKFILLED"}}, wantUnavailable: true},
+		{name: "order created later", rows: [][]driver.Value{{"CREATED"}}},
+		{name: "unknown order"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openStubDB(t, []string{"event_type"}, tt.rows...)
+			repo := &PostgresOrderRepository{db: db, logger: logger}
+
+			err := repo.missingHistory(context.Background(), "order-1", at)
+			if err == nil {
+				t.Fatal("missingHistory() expected error")
+			}
+			if errors.Is(err, interfaces.ErrOrderHistoryUnavailable) != tt.wantUnavailable {
+				t.Errorf("missingHistory() = %v, expected ErrOrderHistoryUnavailable: %v", err, tt.wantUnavailable)
+			}
+		})
+	}
+}
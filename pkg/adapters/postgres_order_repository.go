@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
 	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
 	"github.com/shopspring/decimal"
@@ -15,12 +16,16 @@ import (
 
 type PostgresOrderRepository struct {
 	db     *sql.DB
+	outbox bool
 	logger *logrus.Logger
 }
 
-func NewPostgresOrderRepository(db *sql.DB, logger *logrus.Logger) interfaces.OrderRepository {
+// NewPostgresOrderRepository creates an order repository; with outbox set, mutations also
+// write domain events to exchange.outbox in the same transaction
+func NewPostgresOrderRepository(db *sql.DB, outbox bool, logger *logrus.Logger) interfaces.OrderRepository {
 	return &PostgresOrderRepository{
 		db:     db,
+		outbox: outbox,
 		logger: logger,
 	}
 }
@@ -33,20 +38,38 @@ func (r *PostgresOrderRepository) Create(ctx context.Context, order *models.Orde
 		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		order.OrderID, order.AccountID, order.Symbol, order.OrderType, order.Side,
-		order.Quantity, order.Price, order.FilledQuantity, order.AveragePrice,
-		order.Status, order.TimeInForce, order.CreatedAt, order.UpdatedAt, order.Metadata,
-	)
+	err := writeWithEvents(ctx, r.db, r.outbox, func(exec sqlExecutor) ([]models.DomainEvent, error) {
+		_, err := exec.ExecContext(ctx, query,
+			order.OrderID, order.AccountID, order.Symbol, order.OrderType, order.Side,
+			order.Quantity, order.Price, order.FilledQuantity, order.AveragePrice,
+			order.Status, order.TimeInForce, order.CreatedAt, order.UpdatedAt, order.Metadata,
+		)
+		return []models.DomainEvent{&models.OrderCreated{Order: *order}}, err
+	})
 
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to create order")
-		return fmt.Errorf("failed to create order: %w", err)
+		return fmt.Errorf("failed to create order: %w", accountWriteError(err))
 	}
 
 	return nil
 }
 
+func (r *PostgresOrderRepository) CreateBatch(ctx context.Context, orders []*models.Order, opts *models.BatchOptions) (*models.BatchResult, error) {
+	columns := []string{"order_id", "account_id", "symbol", "order_type", "side", "quantity", "price",
+		"filled_quantity", "average_price", "status", "time_in_force", "created_at", "updated_at",
+		"filled_at", "cancelled_at", "metadata"}
+	return copyIn(ctx, r.db, r.logger, "orders", columns, len(orders), opts, func(i int) []interface{} {
+		order := orders[i]
+		return []interface{}{order.OrderID, order.AccountID, order.Symbol, order.OrderType, order.Side,
+			order.Quantity, order.Price, order.FilledQuantity, order.AveragePrice, order.Status,
+			order.TimeInForce, order.CreatedAt, order.UpdatedAt, order.FilledAt, order.CancelledAt,
+			copyJSON(order.Metadata)}
+	}, outboxChunkWriter(r.outbox, func(i int) models.DomainEvent {
+		return &models.OrderCreated{Order: *orders[i]}
+	}))
+}
+
 func (r *PostgresOrderRepository) GetByID(ctx context.Context, orderID string) (*models.Order, error) {
 	query := `
 		SELECT order_id, account_id, symbol, order_type, side, quantity, price, filled_quantity,
@@ -74,6 +97,43 @@ func (r *PostgresOrderRepository) GetByID(ctx context.Context, orderID string) (
 	return order, nil
 }
 
+func (r *PostgresOrderRepository) GetByIDs(ctx context.Context, orderIDs []string) (map[string]*models.Order, []string, error) {
+	orders := make(map[string]*models.Order, len(orderIDs))
+	if len(orderIDs) == 0 {
+		return orders, []string{}, nil
+	}
+
+	query := `
+		SELECT order_id, account_id, symbol, order_type, side, quantity, price, filled_quantity,
+			   average_price, status, time_in_force, created_at, updated_at, filled_at, cancelled_at, metadata
+		FROM exchange.orders
+		WHERE order_id = ANY($1)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(orderIDs))
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to get orders by IDs")
+		return nil, nil, fmt.Errorf("failed to get orders: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		order := &models.Order{}
+		if err := rows.Scan(&order.OrderID, &order.AccountID, &order.Symbol, &order.OrderType,
+			&order.Side, &order.Quantity, &order.Price, &order.FilledQuantity, &order.AveragePrice,
+			&order.Status, &order.TimeInForce, &order.CreatedAt, &order.UpdatedAt,
+			&order.FilledAt, &order.CancelledAt, &order.Metadata); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan order: %w", err)
+		}
+		orders[order.OrderID] = order
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to iterate orders: %w", err)
+	}
+
+	return orders, missingKeys(orderIDs, orders), nil
+}
+
 func (r *PostgresOrderRepository) Query(ctx context.Context, query *models.OrderQuery) ([]*models.Order, error) {
 	sqlQuery := `
 		SELECT order_id, account_id, symbol, order_type, side, quantity, price, filled_quantity,
@@ -127,6 +187,12 @@ func (r *PostgresOrderRepository) Query(ctx context.Context, query *models.Order
 		argCount++
 	}
 
+	var err error
+	sqlQuery, args, argCount, err = appendMetadataFilter(sqlQuery, args, argCount, query.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
 	// Add sorting
 	if query.SortBy != "" {
 		sortOrder := "ASC"
@@ -179,15 +245,31 @@ func (r *PostgresOrderRepository) UpdateStatus(ctx context.Context, orderID stri
 		WHERE order_id = $3
 	`
 
-	_, err := r.db.ExecContext(ctx, query, status, time.Now(), orderID)
+	err := r.writeStatusChange(ctx, query, orderID, status)
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to update order status")
-		return fmt.Errorf("failed to update order status: %w", err)
+		return fmt.Errorf("failed to update order status: %w", accountWriteError(err))
 	}
 
 	return nil
 }
 
+// writeStatusChange runs a status update taking (status, changed_at, order_id) and emits
+// OrderStatusChanged when a row was updated
+func (r *PostgresOrderRepository) writeStatusChange(ctx context.Context, query, orderID string, status models.OrderStatus) error {
+	return writeWithEvents(ctx, r.db, r.outbox, func(exec sqlExecutor) ([]models.DomainEvent, error) {
+		changedAt := time.Now()
+		result, err := exec.ExecContext(ctx, query, status, changedAt, orderID)
+		if err != nil {
+			return nil, err
+		}
+		if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+			return nil, err
+		}
+		return []models.DomainEvent{&models.OrderStatusChanged{OrderID: orderID, Status: status, ChangedAt: changedAt}}, nil
+	})
+}
+
 func (r *PostgresOrderRepository) UpdateFilled(ctx context.Context, orderID string, filledQuantity, averagePrice decimal.Decimal) error {
 	query := `
 		UPDATE exchange.orders
@@ -195,10 +277,14 @@ func (r *PostgresOrderRepository) UpdateFilled(ctx context.Context, orderID stri
 		WHERE order_id = $5
 	`
 
-	_, err := r.db.ExecContext(ctx, query, filledQuantity, averagePrice, time.Now(), time.Now(), orderID)
+	err := writeWithEvents(ctx, r.db, r.outbox, func(exec sqlExecutor) ([]models.DomainEvent, error) {
+		now := time.Now()
+		_, err := exec.ExecContext(ctx, query, filledQuantity, averagePrice, now, now, orderID)
+		return nil, err
+	})
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to update order filled")
-		return fmt.Errorf("failed to update order filled: %w", err)
+		return fmt.Errorf("failed to update order filled: %w", accountWriteError(err))
 	}
 
 	return nil
@@ -207,14 +293,14 @@ func (r *PostgresOrderRepository) UpdateFilled(ctx context.Context, orderID stri
 func (r *PostgresOrderRepository) Cancel(ctx context.Context, orderID string) error {
 	query := `
 		UPDATE exchange.orders
-		SET status = $1, updated_at = $2, cancelled_at = $3
-		WHERE order_id = $4
+		SET status = $1, updated_at = $2, cancelled_at = $2
+		WHERE order_id = $3
 	`
 
-	_, err := r.db.ExecContext(ctx, query, models.OrderStatusCancelled, time.Now(), time.Now(), orderID)
+	err := r.writeStatusChange(ctx, query, orderID, models.OrderStatusCancelled)
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to cancel order")
-		return fmt.Errorf("failed to cancel order: %w", err)
+		return fmt.Errorf("failed to cancel order: %w", accountWriteError(err))
 	}
 
 	return nil
@@ -281,3 +367,75 @@ func (r *PostgresOrderRepository) GetByAccountAndSymbol(ctx context.Context, acc
 
 	return orders, nil
 }
+
+func (r *PostgresOrderRepository) GetHistory(ctx context.Context, orderID string) ([]*models.OrderEvent, error) {
+	return r.queryEvents(ctx, `
+		SELECT event_id, order_id, event_type, changes, causation_id, occurred_at
+		FROM exchange.order_events
+		WHERE order_id = $1
+		ORDER BY event_id
+	`, orderID)
+}
+
+func (r *PostgresOrderRepository) GetAsOf(ctx context.Context, orderID string, at time.Time) (*models.Order, error) {
+	events, err := r.queryEvents(ctx, `
+		SELECT event_id, order_id, event_type, changes, causation_id, occurred_at
+		FROM exchange.order_events
+		WHERE order_id = $1 AND occurred_at <= $2
+		ORDER BY event_id
+	`, orderID, at)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, r.missingHistory(ctx, orderID, at)
+	}
+
+	order, err := models.ReplayOrderEvents(events)
+	if err != nil {
+		r.logger.WithError(err).WithField("order_id", orderID).Error("Failed to replay order history")
+		return nil, fmt.Errorf("failed to reconstruct order: %w", err)
+	}
+	return order, nil
+}
+
+// missingHistory explains an empty history as of at: either the order did not exist yet or its
+// history starts with a later BACKFILLED snapshot
+func (r *PostgresOrderRepository) missingHistory(ctx context.Context, orderID string, at time.Time) error {
+	var first models.OrderEventType
+	err := r.db.QueryRowContext(ctx, `
+		SELECT event_type FROM exchange.order_events WHERE order_id = $1 ORDER BY event_id LIMIT 1
+	`, orderID).Scan(&first)
+	if err != nil && err != sql.ErrNoRows {
+		r.logger.WithError(err).Error("Failed to get order history")
+		return fmt.Errorf("failed to get order history: %w", err)
+	}
+	if first == models.OrderEventBackfilled {
+		return fmt.Errorf("%w: %s as of %s", interfaces.ErrOrderHistoryUnavailable, orderID, at.Format(time.RFC3339Nano))
+	}
+	return fmt.Errorf("order not found: %s as of %s", orderID, at.Format(time.RFC3339Nano))
+}
+
+func (r *PostgresOrderRepository) queryEvents(ctx context.Context, query string, args ...interface{}) ([]*models.OrderEvent, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to get order history")
+		return nil, fmt.Errorf("failed to get order history: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*models.OrderEvent{}
+	for rows.Next() {
+		event := &models.OrderEvent{}
+		if err := rows.Scan(&event.EventID, &event.OrderID, &event.EventType, &event.Changes,
+			&event.CausationID, &event.OccurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan order event: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate order history: %w", err)
+	}
+
+	return events, nil
+}
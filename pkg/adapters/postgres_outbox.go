@@ -0,0 +1,290 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// sqlExecutor is satisfied by both *sql.DB and *sql.Tx
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// outboxColumns are the columns written for each event
+var outboxColumns = []string{"aggregate_type", "aggregate_id", "event_type", "payload"}
+
+// writeWithEvents runs a mutation and records the domain events it returns. With the outbox
+// disabled and no write context (see setWriteContext) the mutation runs directly against db and
+// the events are discarded; otherwise the mutation runs in a transaction that also carries the
+// write context for the history and audit triggers and, with the outbox enabled, the outbox inserts.
+// Inside PostgresTransactor.InTransaction the mutation joins that transaction instead.
+func writeWithEvents(ctx context.Context, db *sql.DB, outbox bool, write func(exec sqlExecutor) ([]models.DomainEvent, error)) error {
+	if ambient := joinedTransaction(ctx, db); ambient != nil {
+		return writeInJoinedTransaction(ctx, ambient.tx, outbox, write)
+	}
+	if !outbox && !hasWriteContext(ctx) {
+		_, err := write(db)
+		return err
+	}
+	return writeInTransaction(ctx, db, outbox, write)
+}
+
+// writeInTransaction is writeWithEvents for mutations that always need a transaction, such as
+// check-then-write sequences
+func writeInTransaction(ctx context.Context, db *sql.DB, outbox bool, write func(exec sqlExecutor) ([]models.DomainEvent, error)) error {
+	if ambient := joinedTransaction(ctx, db); ambient != nil {
+		return writeInJoinedTransaction(ctx, ambient.tx, outbox, write)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setWriteContext(ctx, tx); err != nil {
+		return err
+	}
+
+	events, err := write(tx)
+	if err != nil {
+		return err
+	}
+	if outbox {
+		if err := insertOutboxEvents(ctx, tx, events); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// writeInJoinedTransaction runs a mutation in a transaction opened by PostgresTransactor. The
+// write context is always applied, so that each write carries its own causation ID, actor and
+// reason rather than those of an earlier write in the same transaction.
+func writeInJoinedTransaction(ctx context.Context, tx *sql.Tx, outbox bool, write func(exec sqlExecutor) ([]models.DomainEvent, error)) error {
+	if err := applyWriteContext(ctx, tx); err != nil {
+		return err
+	}
+
+	events, err := write(tx)
+	if err != nil {
+		return err
+	}
+	if outbox {
+		return insertOutboxEvents(ctx, tx, events)
+	}
+	return nil
+}
+
+// hasWriteContext reports whether the context carries a causation ID, actor or reason
+func hasWriteContext(ctx context.Context) bool {
+	return interfaces.CausationIDFromContext(ctx) != "" || interfaces.ActorFromContext(ctx) != "" ||
+		interfaces.ReasonFromContext(ctx) != ""
+}
+
+// setWriteContext copies the causation ID, actor and reason from the context into
+// transaction-local settings read by the order history and audit triggers
+func setWriteContext(ctx context.Context, tx *sql.Tx) error {
+	if !hasWriteContext(ctx) {
+		return nil
+	}
+	return applyWriteContext(ctx, tx)
+}
+
+// applyWriteContext sets the transaction-local write context, clearing values the context lacks
+func applyWriteContext(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		SELECT set_config('exchange.causation_id', $1, true),
+			   set_config('exchange.actor', $2, true),
+			   set_config('exchange.reason', $3, true)
+	`, interfaces.CausationIDFromContext(ctx), interfaces.ActorFromContext(ctx), interfaces.ReasonFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to set write context: %w", err)
+	}
+	return nil
+}
+
+// insertOutboxEvents writes events into exchange.outbox, using COPY for more than one event
+func insertOutboxEvents(ctx context.Context, tx *sql.Tx, events []models.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows := make([]*models.OutboxEvent, len(events))
+	for i, event := range events {
+		row, err := models.NewOutboxEvent(event)
+		if err != nil {
+			return err
+		}
+		rows[i] = row
+	}
+
+	if len(rows) == 1 {
+		row := rows[0]
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO exchange.outbox (aggregate_type, aggregate_id, event_type, payload)
+			VALUES ($1, $2, $3, $4)
+		`, row.AggregateType, row.AggregateID, row.EventType, string(row.Payload))
+		if err != nil {
+			return fmt.Errorf("failed to insert outbox event: %w", err)
+		}
+		return nil
+	}
+
+	err := copyChunk(ctx, tx, "outbox", outboxColumns, [2]int{0, len(rows)}, func(i int) []interface{} {
+		row := rows[i]
+		return []interface{}{row.AggregateType, row.AggregateID, string(row.EventType), string(row.Payload)}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to insert outbox events: %w", err)
+	}
+	return nil
+}
+
+// outboxChunkWriter returns a copyIn hook that writes one event per copied row in the chunk's
+// transaction, or nil when the outbox is disabled
+func outboxChunkWriter(outbox bool, event func(i int) models.DomainEvent) copyChunkHook {
+	if !outbox {
+		return nil
+	}
+	return func(ctx context.Context, tx *sql.Tx, chunk [2]int) error {
+		events := make([]models.DomainEvent, 0, chunk[1]-chunk[0])
+		for i := chunk[0]; i < chunk[1]; i++ {
+			events = append(events, event(i))
+		}
+		return insertOutboxEvents(ctx, tx, events)
+	}
+}
+
+type PostgresOutboxRepository struct {
+	db     *sql.DB
+	logger *logrus.Logger
+}
+
+func NewPostgresOutboxRepository(db *sql.DB, logger *logrus.Logger) interfaces.OutboxRepository {
+	return &PostgresOutboxRepository{db: db, logger: logger}
+}
+
+func (r *PostgresOutboxRepository) FetchUnpublished(ctx context.Context, limit, maxAttempts int) ([]*models.OutboxEvent, error) {
+	query := `
+		SELECT o.event_id, o.aggregate_type, o.aggregate_id, o.event_type, o.payload, o.created_at,
+			   o.published_at, o.attempts, o.last_error
+		FROM exchange.outbox o
+		WHERE o.published_at IS NULL
+		  AND ($2 <= 0 OR NOT EXISTS (
+			  SELECT 1 FROM exchange.outbox dead
+			  WHERE dead.published_at IS NULL AND dead.attempts >= $2
+			    AND dead.aggregate_type = o.aggregate_type AND dead.aggregate_id = o.aggregate_id
+			    AND dead.event_id <= o.event_id
+		  ))
+		ORDER BY o.event_id
+		LIMIT $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, maxAttempts)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to fetch outbox events")
+		return nil, fmt.Errorf("failed to fetch outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	return scanOutboxEvents(rows)
+}
+
+func (r *PostgresOutboxRepository) FetchDeadLettered(ctx context.Context, limit, maxAttempts int) ([]*models.OutboxEvent, error) {
+	query := `
+		SELECT event_id, aggregate_type, aggregate_id, event_type, payload, created_at,
+			   published_at, attempts, last_error
+		FROM exchange.outbox
+		WHERE published_at IS NULL AND attempts >= $2
+		ORDER BY event_id
+		LIMIT $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, maxAttempts)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to fetch dead-lettered outbox events")
+		return nil, fmt.Errorf("failed to fetch dead-lettered outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	return scanOutboxEvents(rows)
+}
+
+func scanOutboxEvents(rows *sql.Rows) ([]*models.OutboxEvent, error) {
+	events := []*models.OutboxEvent{}
+	for rows.Next() {
+		event := &models.OutboxEvent{}
+		if err := rows.Scan(&event.EventID, &event.AggregateType, &event.AggregateID, &event.EventType,
+			&event.Payload, &event.CreatedAt, &event.PublishedAt, &event.Attempts, &event.LastError); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate outbox events: %w", err)
+	}
+
+	return events, nil
+}
+
+func (r *PostgresOutboxRepository) MarkPublished(ctx context.Context, eventIDs []int64) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	query := `UPDATE exchange.outbox SET published_at = $1, attempts = attempts + 1 WHERE event_id = ANY($2)`
+	if _, err := r.db.ExecContext(ctx, query, time.Now(), pq.Array(eventIDs)); err != nil {
+		r.logger.WithError(err).Error("Failed to mark outbox events published")
+		return fmt.Errorf("failed to mark outbox events published: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresOutboxRepository) MarkFailed(ctx context.Context, eventID int64, reason string) error {
+	query := `UPDATE exchange.outbox SET attempts = attempts + 1, last_error = $1 WHERE event_id = $2`
+	if _, err := r.db.ExecContext(ctx, query, reason, eventID); err != nil {
+		r.logger.WithError(err).Error("Failed to mark outbox event failed")
+		return fmt.Errorf("failed to mark outbox event failed: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresOutboxRepository) ResetAttempts(ctx context.Context, eventIDs []int64) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	query := `UPDATE exchange.outbox SET attempts = 0 WHERE event_id = ANY($1) AND published_at IS NULL`
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(eventIDs)); err != nil {
+		r.logger.WithError(err).Error("Failed to reset outbox event attempts")
+		return fmt.Errorf("failed to reset outbox event attempts: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresOutboxRepository) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM exchange.outbox WHERE published_at IS NOT NULL AND published_at < $1`
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to delete published outbox events")
+		return 0, fmt.Errorf("failed to delete published outbox events: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows, nil
+}
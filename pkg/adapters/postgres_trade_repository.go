@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/lib/pq"
 	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
 	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
 	"github.com/sirupsen/logrus"
@@ -12,11 +13,12 @@ import (
 
 type PostgresTradeRepository struct {
 	db     *sql.DB
+	outbox bool
 	logger *logrus.Logger
 }
 
-func NewPostgresTradeRepository(db *sql.DB, logger *logrus.Logger) interfaces.TradeRepository {
-	return &PostgresTradeRepository{db: db, logger: logger}
+func NewPostgresTradeRepository(db *sql.DB, outbox bool, logger *logrus.Logger) interfaces.TradeRepository {
+	return &PostgresTradeRepository{db: db, outbox: outbox, logger: logger}
 }
 
 func (r *PostgresTradeRepository) Create(ctx context.Context, trade *models.Trade) error {
@@ -25,9 +27,12 @@ func (r *PostgresTradeRepository) Create(ctx context.Context, trade *models.Trad
 			trade_id, order_id, account_id, symbol, side, quantity, price, fee, fee_currency, executed_at, metadata
 		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
-	_, err := r.db.ExecContext(ctx, query, trade.TradeID, trade.OrderID, trade.AccountID,
-		trade.Symbol, trade.Side, trade.Quantity, trade.Price, trade.Fee, trade.FeeCurrency,
-		trade.ExecutedAt, trade.Metadata)
+	err := writeWithEvents(ctx, r.db, r.outbox, func(exec sqlExecutor) ([]models.DomainEvent, error) {
+		_, err := exec.ExecContext(ctx, query, trade.TradeID, trade.OrderID, trade.AccountID,
+			trade.Symbol, trade.Side, trade.Quantity, trade.Price, trade.Fee, trade.FeeCurrency,
+			trade.ExecutedAt, trade.Metadata)
+		return []models.DomainEvent{&models.TradeExecuted{Trade: *trade}}, err
+	})
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to create trade")
 		return fmt.Errorf("failed to create trade: %w", err)
@@ -35,6 +40,18 @@ func (r *PostgresTradeRepository) Create(ctx context.Context, trade *models.Trad
 	return nil
 }
 
+func (r *PostgresTradeRepository) CreateBatch(ctx context.Context, trades []*models.Trade, opts *models.BatchOptions) (*models.BatchResult, error) {
+	columns := []string{"trade_id", "order_id", "account_id", "symbol", "side", "quantity", "price",
+		"fee", "fee_currency", "executed_at", "metadata"}
+	return copyIn(ctx, r.db, r.logger, "trades", columns, len(trades), opts, func(i int) []interface{} {
+		trade := trades[i]
+		return []interface{}{trade.TradeID, trade.OrderID, trade.AccountID, trade.Symbol, trade.Side,
+			trade.Quantity, trade.Price, trade.Fee, trade.FeeCurrency, trade.ExecutedAt, copyJSON(trade.Metadata)}
+	}, outboxChunkWriter(r.outbox, func(i int) models.DomainEvent {
+		return &models.TradeExecuted{Trade: *trades[i]}
+	}))
+}
+
 func (r *PostgresTradeRepository) GetByID(ctx context.Context, tradeID string) (*models.Trade, error) {
 	query := `SELECT trade_id, order_id, account_id, symbol, side, quantity, price, fee, fee_currency, executed_at, metadata
 		FROM exchange.trades WHERE trade_id = $1`
@@ -51,6 +68,37 @@ func (r *PostgresTradeRepository) GetByID(ctx context.Context, tradeID string) (
 	return trade, nil
 }
 
+func (r *PostgresTradeRepository) GetByIDs(ctx context.Context, tradeIDs []string) (map[string]*models.Trade, []string, error) {
+	trades := make(map[string]*models.Trade, len(tradeIDs))
+	if len(tradeIDs) == 0 {
+		return trades, []string{}, nil
+	}
+
+	query := `SELECT trade_id, order_id, account_id, symbol, side, quantity, price, fee, fee_currency, executed_at, metadata
+		FROM exchange.trades WHERE trade_id = ANY($1)`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(tradeIDs))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get trades: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		trade := &models.Trade{}
+		if err := rows.Scan(&trade.TradeID, &trade.OrderID, &trade.AccountID, &trade.Symbol,
+			&trade.Side, &trade.Quantity, &trade.Price, &trade.Fee, &trade.FeeCurrency,
+			&trade.ExecutedAt, &trade.Metadata); err != nil {
+			r.logger.WithError(err).Error("Failed to scan trade")
+			return nil, nil, fmt.Errorf("failed to scan trade: %w", err)
+		}
+		trades[trade.TradeID] = trade
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to iterate trades: %w", err)
+	}
+
+	return trades, missingKeys(tradeIDs, trades), nil
+}
+
 func (r *PostgresTradeRepository) GetByOrderID(ctx context.Context, orderID string) ([]*models.Trade, error) {
 	query := `SELECT trade_id, order_id, account_id, symbol, side, quantity, price, fee, fee_currency, executed_at, metadata
 		FROM exchange.trades WHERE order_id = $1 ORDER BY executed_at DESC`
@@ -66,10 +114,14 @@ func (r *PostgresTradeRepository) GetByOrderID(ctx context.Context, orderID stri
 		if err := rows.Scan(&trade.TradeID, &trade.OrderID, &trade.AccountID, &trade.Symbol,
 			&trade.Side, &trade.Quantity, &trade.Price, &trade.Fee, &trade.FeeCurrency,
 			&trade.ExecutedAt, &trade.Metadata); err != nil {
-			return nil, err
+			r.logger.WithError(err).Error("Failed to scan trade")
+			return nil, fmt.Errorf("failed to scan trade: %w", err)
 		}
 		trades = append(trades, trade)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate trades: %w", err)
+	}
 	return trades, nil
 }
 
@@ -96,6 +148,12 @@ func (r *PostgresTradeRepository) Query(ctx context.Context, query *models.Trade
 		argCount++
 	}
 
+	var err error
+	sqlQuery, args, argCount, err = appendMetadataFilter(sqlQuery, args, argCount, query.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
 	sqlQuery += " ORDER BY executed_at DESC"
 	if query.Limit > 0 {
 		sqlQuery += fmt.Sprintf(" LIMIT $%d", argCount)
@@ -114,10 +172,14 @@ func (r *PostgresTradeRepository) Query(ctx context.Context, query *models.Trade
 		if err := rows.Scan(&trade.TradeID, &trade.OrderID, &trade.AccountID, &trade.Symbol,
 			&trade.Side, &trade.Quantity, &trade.Price, &trade.Fee, &trade.FeeCurrency,
 			&trade.ExecutedAt, &trade.Metadata); err != nil {
-			return nil, err
+			r.logger.WithError(err).Error("Failed to scan trade")
+			return nil, fmt.Errorf("failed to scan trade: %w", err)
 		}
 		trades = append(trades, trade)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate trades: %w", err)
+	}
 	return trades, nil
 }
 
@@ -136,10 +198,14 @@ func (r *PostgresTradeRepository) GetBySymbol(ctx context.Context, symbol string
 		if err := rows.Scan(&trade.TradeID, &trade.OrderID, &trade.AccountID, &trade.Symbol,
 			&trade.Side, &trade.Quantity, &trade.Price, &trade.Fee, &trade.FeeCurrency,
 			&trade.ExecutedAt, &trade.Metadata); err != nil {
-			return nil, err
+			r.logger.WithError(err).Error("Failed to scan trade")
+			return nil, fmt.Errorf("failed to scan trade: %w", err)
 		}
 		trades = append(trades, trade)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate trades: %w", err)
+	}
 	return trades, nil
 }
 
@@ -158,9 +224,13 @@ func (r *PostgresTradeRepository) GetByAccount(ctx context.Context, accountID st
 		if err := rows.Scan(&trade.TradeID, &trade.OrderID, &trade.AccountID, &trade.Symbol,
 			&trade.Side, &trade.Quantity, &trade.Price, &trade.Fee, &trade.FeeCurrency,
 			&trade.ExecutedAt, &trade.Metadata); err != nil {
-			return nil, err
+			r.logger.WithError(err).Error("Failed to scan trade")
+			return nil, fmt.Errorf("failed to scan trade: %w", err)
 		}
 		trades = append(trades, trade)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate trades: %w", err)
+	}
 	return trades, nil
 }
@@ -0,0 +1,59 @@
+package adapters
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// TestTradeScanErrors tests that rows which cannot be scanned fail with a wrapped error
+func TestTradeScanErrors(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx := context.Background()
+	columns := []string{"trade_id", "order_id", "account_id", "symbol", "side", "quantity", "price", "fee",
+		"fee_currency", "executed_at", "metadata"}
+	// A quantity that is not a number cannot be scanned into a decimal
+	row := []driver.Value{"trade-1", "order-1", "acc-1", "BTC-USD", "BUY", "not-a-number", "100", "0.1",
+		"USD", time.Now(), nil}
+
+	tests := []struct {
+		name string
+		read func(repo interfaces.TradeRepository) error
+	}{
+		{name: "GetByIDs", read: func(repo interfaces.TradeRepository) error {
+			_, _, err := repo.GetByIDs(ctx, []string{"trade-1"})
+			return err
+		}},
+		{name: "GetByOrderID", read: func(repo interfaces.TradeRepository) error {
+			_, err := repo.GetByOrderID(ctx, "order-1")
+			return err
+		}},
+		{name: "GetBySymbol", read: func(repo interfaces.TradeRepository) error {
+			_, err := repo.GetBySymbol(ctx, "BTC-USD", 10)
+			return err
+		}},
+		{name: "GetByAccount", read: func(repo interfaces.TradeRepository) error {
+			_, err := repo.GetByAccount(ctx, "acc-1")
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openStubDB(t, columns, row)
+			repo := NewPostgresTradeRepository(db, false, logger)
+
+			err := tt.read(repo)
+			if err == nil || !strings.HasPrefix(err.Error(), "failed to scan trade: ") {
+				t.Errorf("%s() error = %v, expected a wrapped scan error", tt.name, err)
+			}
+		})
+	}
+}
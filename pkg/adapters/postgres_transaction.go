@@ -0,0 +1,77 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// transactionKey carries the ambientTransaction opened by PostgresTransactor.InTransaction
+type transactionKey struct{}
+
+// ambientTransaction is a transaction that writes on the same database join, with the hooks
+// to run once it commits
+type ambientTransaction struct {
+	db        *sql.DB
+	tx        *sql.Tx
+	committed []func()
+}
+
+// PostgresTransactor runs repository writes in one transaction. Single-row and check-then-write
+// mutations join it; batch COPY writes still commit per chunk in their own transactions.
+type PostgresTransactor struct {
+	db     *sql.DB
+	logger *logrus.Logger
+}
+
+func NewPostgresTransactor(db *sql.DB, logger *logrus.Logger) interfaces.Transactor {
+	return &PostgresTransactor{db: db, logger: logger}
+}
+
+func (t *PostgresTransactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if joinedTransaction(ctx, t.db) != nil {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.logger.WithError(err).Error("Failed to begin transaction")
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ambient := &ambientTransaction{db: t.db, tx: tx}
+	if err := fn(context.WithValue(ctx, transactionKey{}, ambient)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		t.logger.WithError(err).Error("Failed to commit transaction")
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	for _, hook := range ambient.committed {
+		hook()
+	}
+	return nil
+}
+
+// joinedTransaction returns the context's transaction if it was opened on db
+func joinedTransaction(ctx context.Context, db *sql.DB) *ambientTransaction {
+	ambient, _ := ctx.Value(transactionKey{}).(*ambientTransaction)
+	if ambient == nil || ambient.db != db {
+		return nil
+	}
+	return ambient
+}
+
+// afterCommit runs fn once the context's transaction commits, or immediately outside one, so
+// that cache invalidation cannot race ahead of the write it follows
+func afterCommit(ctx context.Context, fn func()) {
+	if ambient, _ := ctx.Value(transactionKey{}).(*ambientTransaction); ambient != nil {
+		ambient.committed = append(ambient.committed, fn)
+		return
+	}
+	fn()
+}
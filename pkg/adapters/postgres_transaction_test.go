@@ -0,0 +1,85 @@
+package adapters
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// TestPostgresTransactor tests that repository writes join the ambient transaction and that
+// commit hooks run only once it commits
+func TestPostgresTransactor(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	failure := errors.New("fill failed")
+
+	tests := []struct {
+		name           string
+		err            error
+		expected       []string
+		expectedHooked bool
+	}{
+		{
+			name: "commit",
+			expected: []string{"BEGIN", "set_config", "INSERT INTO exchange.trades",
+				"set_config", "INSERT INTO exchange.balances", "COMMIT"},
+			expectedHooked: true,
+		},
+		{
+			name: "rollback",
+			err:  failure,
+			expected: []string{"BEGIN", "set_config", "INSERT INTO exchange.trades",
+				"set_config", "INSERT INTO exchange.balances", "ROLLBACK"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, script := openScriptedDB(t)
+			script.on("exchange.balances", []string{"balance_id", "available_balance", "locked_balance", "total_balance"},
+				[]driver.Value{"bal-1", "1", "0", "1"})
+			trades := NewPostgresTradeRepository(db, false, logger)
+			balances := NewPostgresBalanceRepository(db, false, logger)
+
+			hooked := false
+			err := NewPostgresTransactor(db, logger).InTransaction(context.Background(), func(ctx context.Context) error {
+				trade := &models.Trade{TradeID: "trade-1", OrderID: "order-1", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(1)}
+				if err := trades.Create(interfaces.WithCausationID(ctx, "match-1"), trade); err != nil {
+					return err
+				}
+				if err := balances.AtomicUpdate(ctx, "acc-1", "BTC", decimal.NewFromInt(1), decimal.Zero); err != nil {
+					return err
+				}
+				afterCommit(ctx, func() { hooked = true })
+				if hooked {
+					t.Error("commit hook ran before commit")
+				}
+				return tt.err
+			})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("InTransaction() error = %v, expected %v", err, tt.err)
+			}
+			if hooked != tt.expectedHooked {
+				t.Errorf("commit hook ran = %v, expected %v", hooked, tt.expectedHooked)
+			}
+
+			statements := script.statements()
+			if len(statements) != len(tt.expected) {
+				t.Fatalf("statements = %q, expected %d matching %q", statements, len(tt.expected), tt.expected)
+			}
+			for i, fragment := range tt.expected {
+				if !strings.Contains(statements[i], fragment) {
+					t.Errorf("statement %d = %q, expected it to contain %q", i, statements[i], fragment)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+func (r *RedisCacheRepository) MGet(ctx context.Context, keys []string) (map[string]string, error) {
+	result := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	fullKeys := make([]string, len(keys))
+	for i, key := range keys {
+		fullKeys[i] = r.keyWithNamespace(key)
+	}
+
+	values, err := r.client.MGet(ctx, fullKeys...).Result()
+	if err != nil {
+		r.logger.WithError(err).WithField("count", len(keys)).Error("Failed to get cache values")
+		return nil, fmt.Errorf("failed to get cache values: %w", err)
+	}
+
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			result[keys[i]] = s
+		}
+	}
+
+	return result, nil
+}
+
+func (r *RedisCacheRepository) MSet(ctx context.Context, values map[string]interface{}, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	encoded := make(map[string][]byte, len(values))
+	for key, value := range values {
+		data, err := r.encodeValue(value)
+		if err != nil {
+			return err
+		}
+		encoded[r.keyWithNamespace(key)] = data
+	}
+
+	// MSET cannot carry a TTL, so issue one SET per key in a single MULTI/EXEC round trip
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for fullKey, data := range encoded {
+			pipe.Set(ctx, fullKey, data, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.WithError(err).WithField("count", len(values)).Error("Failed to set cache values")
+		return fmt.Errorf("failed to set cache values: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisCacheRepository) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	fullKey := r.keyWithNamespace(key)
+
+	data, err := r.encodeValue(value)
+	if err != nil {
+		return false, err
+	}
+
+	set, err := r.client.SetNX(ctx, fullKey, data, ttl).Result()
+	if err != nil {
+		r.logger.WithError(err).WithField("key", fullKey).Error("Failed to set cache if absent")
+		return false, fmt.Errorf("failed to set cache if absent: %w", err)
+	}
+
+	return set, nil
+}
+
+func (r *RedisCacheRepository) GetSet(ctx context.Context, key string, value interface{}) (string, bool, error) {
+	fullKey := r.keyWithNamespace(key)
+
+	data, err := r.encodeValue(value)
+	if err != nil {
+		return "", false, err
+	}
+
+	previous, err := r.client.GetSet(ctx, fullKey, data).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		r.logger.WithError(err).WithField("key", fullKey).Error("Failed to swap cache value")
+		return "", false, fmt.Errorf("failed to swap cache value: %w", err)
+	}
+
+	return previous, true, nil
+}
+
+func (r *RedisCacheRepository) Incr(ctx context.Context, key string) (int64, error) {
+	return r.IncrBy(ctx, key, 1)
+}
+
+func (r *RedisCacheRepository) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	fullKey := r.keyWithNamespace(key)
+
+	value, err := r.client.IncrBy(ctx, fullKey, delta).Result()
+	if err != nil {
+		r.logger.WithError(err).WithField("key", fullKey).Error("Failed to increment counter")
+		return 0, fmt.Errorf("failed to increment counter: %w", err)
+	}
+
+	return value, nil
+}
+
+func (r *RedisCacheRepository) IncrByFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	fullKey := r.keyWithNamespace(key)
+
+	value, err := r.client.IncrByFloat(ctx, fullKey, delta).Result()
+	if err != nil {
+		r.logger.WithError(err).WithField("key", fullKey).Error("Failed to increment counter")
+		return 0, fmt.Errorf("failed to increment counter: %w", err)
+	}
+
+	return value, nil
+}
+
+func (r *RedisCacheRepository) HSet(ctx context.Context, key string, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	fullKey := r.keyWithNamespace(key)
+
+	encoded := make(map[string]interface{}, len(fields))
+	for field, value := range fields {
+		data, err := r.encodeValue(value)
+		if err != nil {
+			return err
+		}
+		encoded[field] = data
+	}
+
+	if err := r.client.HSet(ctx, fullKey, encoded).Err(); err != nil {
+		r.logger.WithError(err).WithField("key", fullKey).Error("Failed to set hash fields")
+		return fmt.Errorf("failed to set hash fields: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisCacheRepository) HGet(ctx context.Context, key, field string) (string, error) {
+	fullKey := r.keyWithNamespace(key)
+
+	value, err := r.client.HGet(ctx, fullKey, field).Result()
+	if err == redis.Nil {
+		return "", fmt.Errorf("%w: %s[%s]", interfaces.ErrCacheMiss, key, field)
+	}
+	if err != nil {
+		r.logger.WithError(err).WithFields(logrus.Fields{
+			"key":   fullKey,
+			"field": field,
+		}).Error("Failed to get hash field")
+		return "", fmt.Errorf("failed to get hash field: %w", err)
+	}
+
+	return value, nil
+}
+
+func (r *RedisCacheRepository) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	fullKey := r.keyWithNamespace(key)
+
+	fields, err := r.client.HGetAll(ctx, fullKey).Result()
+	if err != nil {
+		r.logger.WithError(err).WithField("key", fullKey).Error("Failed to get hash")
+		return nil, fmt.Errorf("failed to get hash: %w", err)
+	}
+
+	return fields, nil
+}
+
+func (r *RedisCacheRepository) HDel(ctx context.Context, key string, fields ...string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	fullKey := r.keyWithNamespace(key)
+
+	if err := r.client.HDel(ctx, fullKey, fields...).Err(); err != nil {
+		r.logger.WithError(err).WithField("key", fullKey).Error("Failed to delete hash fields")
+		return fmt.Errorf("failed to delete hash fields: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisCacheRepository) HIncrBy(ctx context.Context, key, field string, delta int64) (int64, error) {
+	fullKey := r.keyWithNamespace(key)
+
+	value, err := r.client.HIncrBy(ctx, fullKey, field, delta).Result()
+	if err != nil {
+		r.logger.WithError(err).WithFields(logrus.Fields{
+			"key":   fullKey,
+			"field": field,
+		}).Error("Failed to increment hash field")
+		return 0, fmt.Errorf("failed to increment hash field: %w", err)
+	}
+
+	return value, nil
+}
+
+func (r *RedisCacheRepository) TTL(ctx context.Context, key string) (time.Duration, error) {
+	fullKey := r.keyWithNamespace(key)
+
+	ttl, err := r.client.TTL(ctx, fullKey).Result()
+	if err != nil {
+		r.logger.WithError(err).WithField("key", fullKey).Error("Failed to get TTL")
+		return 0, fmt.Errorf("failed to get TTL: %w", err)
+	}
+
+	// Redis reports -2 for a missing key and -1 for a key without expiry
+	if ttl == -2 {
+		return 0, fmt.Errorf("%w: %s", interfaces.ErrCacheMiss, key)
+	}
+
+	return ttl, nil
+}
@@ -29,23 +29,29 @@ func (r *RedisCacheRepository) keyWithNamespace(key string) string {
 	return fmt.Sprintf("%s:%s", r.namespace, key)
 }
 
-func (r *RedisCacheRepository) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
-	fullKey := r.keyWithNamespace(key)
-
-	var data []byte
-	var err error
-
+// encodeValue stores strings and byte slices verbatim and JSON-encodes everything else
+func (r *RedisCacheRepository) encodeValue(value interface{}) ([]byte, error) {
 	switch v := value.(type) {
 	case string:
-		data = []byte(v)
+		return []byte(v), nil
 	case []byte:
-		data = v
+		return v, nil
 	default:
-		data, err = json.Marshal(value)
+		data, err := json.Marshal(value)
 		if err != nil {
 			r.logger.WithError(err).Error("Failed to marshal value")
-			return fmt.Errorf("failed to marshal value: %w", err)
+			return nil, fmt.Errorf("failed to marshal value: %w", err)
 		}
+		return data, nil
+	}
+}
+
+func (r *RedisCacheRepository) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	fullKey := r.keyWithNamespace(key)
+
+	data, err := r.encodeValue(value)
+	if err != nil {
+		return err
 	}
 
 	if err := r.client.Set(ctx, fullKey, data, ttl).Err(); err != nil {
@@ -61,7 +67,7 @@ func (r *RedisCacheRepository) Get(ctx context.Context, key string) (string, err
 
 	result, err := r.client.Get(ctx, fullKey).Result()
 	if err == redis.Nil {
-		return "", fmt.Errorf("key not found: %s", key)
+		return "", fmt.Errorf("%w: %s", interfaces.ErrCacheMiss, key)
 	}
 	if err != nil {
 		r.logger.WithError(err).WithField("key", fullKey).Error("Failed to get cache")
@@ -108,7 +114,7 @@ func (r *RedisCacheRepository) Expire(ctx context.Context, key string, ttl time.
 func (r *RedisCacheRepository) Keys(ctx context.Context, pattern string) ([]string, error) {
 	fullPattern := r.keyWithNamespace(pattern)
 
-	keys, err := r.client.Keys(ctx, fullPattern).Result()
+	keys, err := scanKeys(ctx, r.client, fullPattern)
 	if err != nil {
 		r.logger.WithError(err).WithField("pattern", fullPattern).Error("Failed to get keys")
 		return nil, fmt.Errorf("failed to get keys: %w", err)
@@ -124,23 +130,47 @@ func (r *RedisCacheRepository) Keys(ctx context.Context, pattern string) ([]stri
 	return result, nil
 }
 
-func (r *RedisCacheRepository) DeletePattern(ctx context.Context, pattern string) error {
-	keys, err := r.Keys(ctx, pattern)
-	if err != nil {
-		return err
+func (r *RedisCacheRepository) Scan(ctx context.Context, pattern string) interfaces.KeyIterator {
+	fullPattern := r.keyWithNamespace(pattern)
+
+	return &namespacedKeyIterator{
+		iter:   r.client.Scan(ctx, 0, fullPattern, scanBatchSize).Iterator(),
+		prefix: r.namespace + ":",
 	}
+}
 
-	if len(keys) == 0 {
+func (r *RedisCacheRepository) DeletePattern(ctx context.Context, pattern string) error {
+	fullPattern := r.keyWithNamespace(pattern)
+
+	// Unlink in bounded chunks as keys are scanned so memory stays flat for large keyspaces
+	batch := make([]string, 0, unlinkBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := r.client.Unlink(ctx, batch...).Err(); err != nil {
+			return err
+		}
+		batch = batch[:0]
 		return nil
 	}
 
-	// Delete all keys matching pattern
-	fullKeys := make([]string, len(keys))
-	for i, key := range keys {
-		fullKeys[i] = r.keyWithNamespace(key)
+	iter := r.client.Scan(ctx, 0, fullPattern, scanBatchSize).Iterator()
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == unlinkBatchSize {
+			if err := flush(); err != nil {
+				r.logger.WithError(err).WithField("pattern", pattern).Error("Failed to delete pattern")
+				return fmt.Errorf("failed to delete pattern: %w", err)
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		r.logger.WithError(err).WithField("pattern", fullPattern).Error("Failed to scan keys")
+		return fmt.Errorf("failed to scan keys: %w", err)
 	}
 
-	if err := r.client.Del(ctx, fullKeys...).Err(); err != nil {
+	if err := flush(); err != nil {
 		r.logger.WithError(err).WithField("pattern", pattern).Error("Failed to delete pattern")
 		return fmt.Errorf("failed to delete pattern: %w", err)
 	}
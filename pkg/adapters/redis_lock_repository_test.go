@@ -0,0 +1,115 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// openTestRedis connects to TEST_REDIS_URL and returns a namespace unique to the test, whose
+// keys are deleted afterwards. The test is skipped under -short or when Redis is unreachable.
+func openTestRedis(t *testing.T) (*redis.Client, string) {
+	t.Helper()
+	url := os.Getenv("TEST_REDIS_URL")
+	if testing.Short() || url == "" {
+		t.Skip("set TEST_REDIS_URL and drop -short to run Redis integration tests")
+	}
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatalf("failed to parse TEST_REDIS_URL: %v", err)
+	}
+	client := redis.NewClient(options)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		t.Skipf("Redis at TEST_REDIS_URL unreachable: %v", err)
+	}
+
+	namespace, err := newRandomID()
+	if err != nil {
+		t.Fatalf("failed to generate namespace: %v", err)
+	}
+	namespace = "test:" + namespace
+	t.Cleanup(func() {
+		ctx := context.Background()
+		if keys, err := client.Keys(ctx, namespace+":*").Result(); err == nil && len(keys) > 0 {
+			client.Del(ctx, keys...)
+		}
+		client.Close()
+	})
+	return client, namespace
+}
+
+// TestRedisLockScripts tests the acquire, renew and compare-and-delete scripts against Redis
+func TestRedisLockScripts(t *testing.T) {
+	client, namespace := openTestRedis(t)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	locks := NewRedisLockRepository(client, namespace, logger)
+	ctx := context.Background()
+
+	first, err := locks.Acquire(ctx, "relay", time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire() unexpected error: %v", err)
+	}
+	if _, err := locks.Acquire(ctx, "relay", time.Minute); !errors.Is(err, interfaces.ErrLockNotAcquired) {
+		t.Errorf("second Acquire() error = %v, expected ErrLockNotAcquired", err)
+	}
+
+	// Only the owner may renew or release
+	stranger := &interfaces.Lock{Name: "relay", Owner: "stranger"}
+	if err := locks.Renew(ctx, stranger, time.Minute); !errors.Is(err, interfaces.ErrLockNotHeld) {
+		t.Errorf("Renew() by stranger error = %v, expected ErrLockNotHeld", err)
+	}
+	if err := locks.Release(ctx, stranger); !errors.Is(err, interfaces.ErrLockNotHeld) {
+		t.Errorf("Release() by stranger error = %v, expected ErrLockNotHeld", err)
+	}
+
+	if err := locks.Renew(ctx, first, 2*time.Minute); err != nil {
+		t.Fatalf("Renew() unexpected error: %v", err)
+	}
+	ttl, err := client.PTTL(ctx, namespace+":lock:relay").Result()
+	if err != nil || ttl <= time.Minute {
+		t.Errorf("PTTL after Renew() = %v, %v; expected over a minute", ttl, err)
+	}
+	if time.Until(first.ExpiresAt) < ttl-time.Second {
+		t.Errorf("ExpiresAt %v ahead of Redis TTL %v", time.Until(first.ExpiresAt), ttl)
+	}
+	if err := locks.Release(ctx, first); err != nil {
+		t.Fatalf("Release() unexpected error: %v", err)
+	}
+
+	// An expired lock can be taken over, and its former owner can no longer renew or release it
+	expiring, err := locks.Acquire(ctx, "relay", 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Acquire() after release unexpected error: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	successor, err := locks.Acquire(ctx, "relay", time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire() after expiry unexpected error: %v", err)
+	}
+	if err := locks.Renew(ctx, expiring, time.Minute); !errors.Is(err, interfaces.ErrLockNotHeld) {
+		t.Errorf("Renew() of expired lock error = %v, expected ErrLockNotHeld", err)
+	}
+	if err := locks.Release(ctx, expiring); !errors.Is(err, interfaces.ErrLockNotHeld) {
+		t.Errorf("Release() of expired lock error = %v, expected ErrLockNotHeld", err)
+	}
+
+	// Fencing tokens increase with every acquisition and never on a refused one
+	tokens := []int64{first.FencingToken, expiring.FencingToken, successor.FencingToken}
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i] != tokens[i-1]+1 {
+			t.Errorf("fencing tokens = %v, expected consecutive increasing values", tokens)
+			break
+		}
+	}
+}
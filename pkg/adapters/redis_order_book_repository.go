@@ -0,0 +1,393 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// rebuildPageSize is the number of open orders read per query during Rebuild
+const rebuildPageSize = 1000
+
+// Order book scripts operate on five keys per symbol:
+// KEYS[1] meta hash (sequence, updated_at), KEYS[2] bid price index, KEYS[3] bid levels,
+// KEYS[4] ask price index, KEYS[5] ask levels.
+
+// applyOrderBookDeltaScript checks the sequence and applies level changes atomically.
+// ARGV[1] new sequence, ARGV[2] updated_at, then groups of (side, price, score, value);
+// an empty value removes the level. Returns {status, sequence} where status is
+// 1 applied, 0 sequence gap, -1 no book.
+var applyOrderBookDeltaScript = redis.NewScript(`
+local current = redis.call("HGET", KEYS[1], "sequence")
+if not current then
+	return {-1, 0}
+end
+current = tonumber(current)
+if tonumber(ARGV[1]) ~= current + 1 then
+	return {0, current}
+end
+
+for i = 3, #ARGV, 4 do
+	local index, levels = KEYS[2], KEYS[3]
+	if ARGV[i] == "SELL" then
+		index, levels = KEYS[4], KEYS[5]
+	end
+	if ARGV[i + 3] == "" then
+		redis.call("ZREM", index, ARGV[i + 1])
+		redis.call("HDEL", levels, ARGV[i + 1])
+	else
+		redis.call("ZADD", index, ARGV[i + 2], ARGV[i + 1])
+		redis.call("HSET", levels, ARGV[i + 1], ARGV[i + 3])
+	end
+end
+
+redis.call("HSET", KEYS[1], "sequence", ARGV[1], "updated_at", ARGV[2])
+return {1, tonumber(ARGV[1])}
+`)
+
+// readOrderBookScript returns a consistent view of the best ARGV[1] levels per side (0 for all)
+// as {sequence, updated_at, bid_prices, bid_values, ask_prices, ask_values}.
+var readOrderBookScript = redis.NewScript(`
+local meta = redis.call("HMGET", KEYS[1], "sequence", "updated_at")
+if not meta[1] then
+	return false
+end
+
+local stop = tonumber(ARGV[1]) - 1
+local bids = redis.call("ZREVRANGE", KEYS[2], 0, stop)
+local asks = redis.call("ZRANGE", KEYS[4], 0, stop)
+local bidValues, askValues = {}, {}
+for i, price in ipairs(bids) do
+	bidValues[i] = redis.call("HGET", KEYS[3], price)
+end
+for i, price in ipairs(asks) do
+	askValues[i] = redis.call("HGET", KEYS[5], price)
+end
+return {meta[1], meta[2] or "", bids, bidValues, asks, askValues}
+`)
+
+// RedisOrderBookRepository stores price-level aggregated books in sorted sets keyed by price,
+// with the level quantity and order count in a companion hash.
+type RedisOrderBookRepository struct {
+	client    *redis.Client
+	orders    interfaces.OrderRepository
+	namespace string
+	logger    *logrus.Logger
+}
+
+// NewRedisOrderBookRepository creates an order book repository; orders is used by Rebuild and may be nil
+func NewRedisOrderBookRepository(client *redis.Client, orders interfaces.OrderRepository, namespace string, logger *logrus.Logger) interfaces.OrderBookRepository {
+	return &RedisOrderBookRepository{
+		client:    client,
+		orders:    orders,
+		namespace: namespace,
+		logger:    logger,
+	}
+}
+
+func (r *RedisOrderBookRepository) bookKeys(symbol string) []string {
+	prefix := fmt.Sprintf("%s:orderbook:%s", r.namespace, symbol)
+	return []string{
+		prefix,
+		prefix + ":bids",
+		prefix + ":bid-levels",
+		prefix + ":asks",
+		prefix + ":ask-levels",
+	}
+}
+
+// encodePriceLevel stores a level as "quantity:order_count"
+func encodePriceLevel(level models.PriceLevel) string {
+	return level.Quantity.String() + ":" + strconv.Itoa(level.OrderCount)
+}
+
+func decodePriceLevel(price, value string) (models.PriceLevel, error) {
+	p, err := decimal.NewFromString(price)
+	if err != nil {
+		return models.PriceLevel{}, fmt.Errorf("invalid level price %q: %w", price, err)
+	}
+	quantity, count, ok := strings.Cut(value, ":")
+	if !ok {
+		return models.PriceLevel{}, fmt.Errorf("invalid level value %q", value)
+	}
+	q, err := decimal.NewFromString(quantity)
+	if err != nil {
+		return models.PriceLevel{}, fmt.Errorf("invalid level quantity %q: %w", quantity, err)
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil {
+		return models.PriceLevel{}, fmt.Errorf("invalid level order count %q: %w", count, err)
+	}
+	return models.PriceLevel{Price: p, Quantity: q, OrderCount: n}, nil
+}
+
+func (r *RedisOrderBookRepository) SaveSnapshot(ctx context.Context, snapshot *models.OrderBookSnapshot) error {
+	keys := r.bookKeys(snapshot.Symbol)
+	updatedAt := snapshot.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = time.Now()
+	}
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, keys...)
+		for i, levels := range [][]models.PriceLevel{snapshot.Bids, snapshot.Asks} {
+			index, values := keys[1+2*i], keys[2+2*i]
+			for _, level := range levels {
+				if !level.Quantity.IsPositive() {
+					continue
+				}
+				price := level.Price.String()
+				pipe.ZAdd(ctx, index, redis.Z{Score: level.Price.InexactFloat64(), Member: price})
+				pipe.HSet(ctx, values, price, encodePriceLevel(level))
+			}
+		}
+		pipe.HSet(ctx, keys[0], "sequence", snapshot.Sequence, "updated_at", updatedAt.UTC().Format(time.RFC3339Nano))
+		return nil
+	})
+	if err != nil {
+		r.logger.WithError(err).WithField("symbol", snapshot.Symbol).Error("Failed to save order book snapshot")
+		return fmt.Errorf("failed to save order book snapshot: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisOrderBookRepository) GetSnapshot(ctx context.Context, symbol string) (*models.OrderBookSnapshot, error) {
+	return r.read(ctx, symbol, 0)
+}
+
+func (r *RedisOrderBookRepository) GetDepth(ctx context.Context, symbol string, levels int) (*models.OrderBookSnapshot, error) {
+	if levels <= 0 {
+		return nil, fmt.Errorf("depth must be positive, got %d", levels)
+	}
+	return r.read(ctx, symbol, levels)
+}
+
+func (r *RedisOrderBookRepository) read(ctx context.Context, symbol string, levels int) (*models.OrderBookSnapshot, error) {
+	reply, err := readOrderBookScript.Run(ctx, r.client, r.bookKeys(symbol), levels).Slice()
+	if err == redis.Nil {
+		return nil, fmt.Errorf("%w: %s", interfaces.ErrOrderBookNotFound, symbol)
+	}
+	if err != nil {
+		r.logger.WithError(err).WithField("symbol", symbol).Error("Failed to read order book")
+		return nil, fmt.Errorf("failed to read order book: %w", err)
+	}
+
+	snapshot, err := parseOrderBookReply(symbol, reply)
+	if err != nil {
+		r.logger.WithError(err).WithField("symbol", symbol).Error("Failed to decode order book")
+		return nil, fmt.Errorf("failed to decode order book: %w", err)
+	}
+	return snapshot, nil
+}
+
+// parseOrderBookReply decodes the reply of readOrderBookScript
+func parseOrderBookReply(symbol string, reply []interface{}) (*models.OrderBookSnapshot, error) {
+	if len(reply) != 6 {
+		return nil, fmt.Errorf("unexpected reply length %d", len(reply))
+	}
+
+	sequence, err := strconv.ParseInt(fmt.Sprint(reply[0]), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sequence: %w", err)
+	}
+	snapshot := &models.OrderBookSnapshot{
+		Symbol:   symbol,
+		Sequence: sequence,
+		Bids:     []models.PriceLevel{},
+		Asks:     []models.PriceLevel{},
+	}
+	if updatedAt, _ := reply[1].(string); updatedAt != "" {
+		if snapshot.UpdatedAt, err = time.Parse(time.RFC3339Nano, updatedAt); err != nil {
+			return nil, fmt.Errorf("invalid updated_at: %w", err)
+		}
+	}
+
+	for i, side := range []*[]models.PriceLevel{&snapshot.Bids, &snapshot.Asks} {
+		prices, _ := reply[2+2*i].([]interface{})
+		values, _ := reply[3+2*i].([]interface{})
+		if len(prices) != len(values) {
+			return nil, fmt.Errorf("mismatched level count %d/%d", len(prices), len(values))
+		}
+		for j := range prices {
+			price, _ := prices[j].(string)
+			value, ok := values[j].(string)
+			if !ok {
+				// A price without a level value means the index and hash drifted; skip it
+				continue
+			}
+			level, err := decodePriceLevel(price, value)
+			if err != nil {
+				return nil, err
+			}
+			*side = append(*side, level)
+		}
+	}
+
+	return snapshot, nil
+}
+
+func (r *RedisOrderBookRepository) ApplyDelta(ctx context.Context, delta *models.OrderBookDelta) error {
+	updatedAt := delta.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = time.Now()
+	}
+
+	args := make([]interface{}, 0, 2+4*len(delta.Changes))
+	args = append(args, delta.Sequence, updatedAt.UTC().Format(time.RFC3339Nano))
+	for _, change := range delta.Changes {
+		if change.Side != models.OrderSideBuy && change.Side != models.OrderSideSell {
+			return fmt.Errorf("invalid order book side %q", change.Side)
+		}
+		value := ""
+		if change.Quantity.IsPositive() {
+			value = encodePriceLevel(models.PriceLevel{Quantity: change.Quantity, OrderCount: change.OrderCount})
+		}
+		args = append(args, string(change.Side), change.Price.String(), change.Price.InexactFloat64(), value)
+	}
+
+	reply, err := applyOrderBookDeltaScript.Run(ctx, r.client, r.bookKeys(delta.Symbol), args...).Int64Slice()
+	if err != nil {
+		r.logger.WithError(err).WithField("symbol", delta.Symbol).Error("Failed to apply order book delta")
+		return fmt.Errorf("failed to apply order book delta: %w", err)
+	}
+
+	switch reply[0] {
+	case -1:
+		return fmt.Errorf("%w: %s", interfaces.ErrOrderBookNotFound, delta.Symbol)
+	case 0:
+		return fmt.Errorf("%w: %s at sequence %d, delta sequence %d",
+			interfaces.ErrOrderBookSequenceGap, delta.Symbol, reply[1], delta.Sequence)
+	}
+
+	return nil
+}
+
+func (r *RedisOrderBookRepository) GetSequence(ctx context.Context, symbol string) (int64, error) {
+	sequence, err := r.client.HGet(ctx, r.bookKeys(symbol)[0], "sequence").Int64()
+	if err == redis.Nil {
+		return 0, fmt.Errorf("%w: %s", interfaces.ErrOrderBookNotFound, symbol)
+	}
+	if err != nil {
+		r.logger.WithError(err).WithField("symbol", symbol).Error("Failed to get order book sequence")
+		return 0, fmt.Errorf("failed to get order book sequence: %w", err)
+	}
+	return sequence, nil
+}
+
+// Rebuild reads all open and partially filled LIMIT orders for the symbol and replaces the stored
+// book. Deltas applied concurrently are overwritten, so it should run while the book is quiescent.
+func (r *RedisOrderBookRepository) Rebuild(ctx context.Context, symbol string) (*models.OrderBookSnapshot, error) {
+	if r.orders == nil {
+		return nil, fmt.Errorf("order book rebuild requires an order repository")
+	}
+
+	sequence, err := r.GetSequence(ctx, symbol)
+	if err != nil && !errors.Is(err, interfaces.ErrOrderBookNotFound) {
+		return nil, err
+	}
+
+	orderType := models.OrderTypeLimit
+	var open []*models.Order
+	for _, status := range []models.OrderStatus{models.OrderStatusOpen, models.OrderStatusPartial} {
+		for offset := 0; ; offset += rebuildPageSize {
+			page, err := r.orders.Query(ctx, &models.OrderQuery{
+				Symbol:    &symbol,
+				OrderType: &orderType,
+				Status:    &status,
+				Limit:     rebuildPageSize,
+				Offset:    offset,
+				SortBy:    "created_at",
+				SortOrder: "ASC",
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to query open orders: %w", err)
+			}
+			open = append(open, page...)
+			if len(page) < rebuildPageSize {
+				break
+			}
+		}
+	}
+
+	bids, asks := aggregateOrderBook(open)
+	snapshot := &models.OrderBookSnapshot{
+		Symbol:    symbol,
+		Sequence:  sequence + 1,
+		Bids:      bids,
+		Asks:      asks,
+		UpdatedAt: time.Now(),
+	}
+	if err := r.SaveSnapshot(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"symbol":   symbol,
+		"orders":   len(open),
+		"sequence": snapshot.Sequence,
+	}).Info("Order book rebuilt from open orders")
+
+	return snapshot, nil
+}
+
+// aggregateOrderBook sums the remaining quantity of resting orders per side and price,
+// returning bids best (highest) first and asks best (lowest) first
+func aggregateOrderBook(orders []*models.Order) ([]models.PriceLevel, []models.PriceLevel) {
+	levels := map[models.OrderSide]map[string]*models.PriceLevel{
+		models.OrderSideBuy:  {},
+		models.OrderSideSell: {},
+	}
+	for _, order := range orders {
+		if order.Price == nil {
+			continue
+		}
+		remaining := order.Quantity.Sub(order.FilledQuantity)
+		if !remaining.IsPositive() {
+			continue
+		}
+		side, ok := levels[order.Side]
+		if !ok {
+			continue
+		}
+		key := order.Price.String()
+		level, ok := side[key]
+		if !ok {
+			level = &models.PriceLevel{Price: *order.Price}
+			side[key] = level
+		}
+		level.Quantity = level.Quantity.Add(remaining)
+		level.OrderCount++
+	}
+
+	flatten := func(side map[string]*models.PriceLevel, better func(a, b decimal.Decimal) bool) []models.PriceLevel {
+		result := make([]models.PriceLevel, 0, len(side))
+		for _, level := range side {
+			result = append(result, *level)
+		}
+		sort.Slice(result, func(i, j int) bool { return better(result[i].Price, result[j].Price) })
+		return result
+	}
+
+	bids := flatten(levels[models.OrderSideBuy], func(a, b decimal.Decimal) bool { return a.GreaterThan(b) })
+	asks := flatten(levels[models.OrderSideSell], func(a, b decimal.Decimal) bool { return a.LessThan(b) })
+	return bids, asks
+}
+
+func (r *RedisOrderBookRepository) Delete(ctx context.Context, symbol string) error {
+	if err := r.client.Del(ctx, r.bookKeys(symbol)...).Err(); err != nil {
+		r.logger.WithError(err).WithField("symbol", symbol).Error("Failed to delete order book")
+		return fmt.Errorf("failed to delete order book: %w", err)
+	}
+	return nil
+}
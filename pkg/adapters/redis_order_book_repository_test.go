@@ -0,0 +1,89 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+)
+
+func testLimitOrder(side models.OrderSide, price, quantity, filled string) *models.Order {
+	p := decimal.RequireFromString(price)
+	return &models.Order{
+		Side:           side,
+		OrderType:      models.OrderTypeLimit,
+		Price:          &p,
+		Quantity:       decimal.RequireFromString(quantity),
+		FilledQuantity: decimal.RequireFromString(filled),
+	}
+}
+
+// TestAggregateOrderBook tests price-level aggregation and ordering of resting orders
+func TestAggregateOrderBook(t *testing.T) {
+	orders := []*models.Order{
+		testLimitOrder(models.OrderSideBuy, "99.5", "1", "0"),
+		testLimitOrder(models.OrderSideBuy, "100", "2", "0.5"),
+		testLimitOrder(models.OrderSideBuy, "100.00", "1", "0"),
+		testLimitOrder(models.OrderSideSell, "101", "3", "0"),
+		testLimitOrder(models.OrderSideSell, "100.5", "1", "1"),
+		testLimitOrder(models.OrderSideSell, "102", "1", "0"),
+		{Side: models.OrderSideSell, OrderType: models.OrderTypeMarket, Quantity: decimal.NewFromInt(5)},
+	}
+
+	bids, asks := aggregateOrderBook(orders)
+
+	expectedBids := []models.PriceLevel{
+		{Price: decimal.RequireFromString("100"), Quantity: decimal.RequireFromString("2.5"), OrderCount: 2},
+		{Price: decimal.RequireFromString("99.5"), Quantity: decimal.RequireFromString("1"), OrderCount: 1},
+	}
+	expectedAsks := []models.PriceLevel{
+		{Price: decimal.RequireFromString("101"), Quantity: decimal.RequireFromString("3"), OrderCount: 1},
+		{Price: decimal.RequireFromString("102"), Quantity: decimal.RequireFromString("1"), OrderCount: 1},
+	}
+
+	for name, tc := range map[string]struct{ got, expected []models.PriceLevel }{
+		"bids": {bids, expectedBids},
+		"asks": {asks, expectedAsks},
+	} {
+		if len(tc.got) != len(tc.expected) {
+			t.Fatalf("%s = %v, expected %v", name, tc.got, tc.expected)
+		}
+		for i := range tc.got {
+			got, expected := tc.got[i], tc.expected[i]
+			if !got.Price.Equal(expected.Price) || !got.Quantity.Equal(expected.Quantity) || got.OrderCount != expected.OrderCount {
+				t.Errorf("%s[%d] = %+v, expected %+v", name, i, got, expected)
+			}
+		}
+	}
+}
+
+// TestParseOrderBookReply tests decoding of the read script reply
+func TestParseOrderBookReply(t *testing.T) {
+	reply := []interface{}{
+		"42",
+		"2025-01-02T03:04:05Z",
+		[]interface{}{"100", "99.5"},
+		[]interface{}{"2.5:2", nil},
+		[]interface{}{"101"},
+		[]interface{}{"3:1"},
+	}
+
+	snapshot, err := parseOrderBookReply("BTC-USD", reply)
+	if err != nil {
+		t.Fatalf("parseOrderBookReply() unexpected error: %v", err)
+	}
+	if snapshot.Sequence != 42 || snapshot.UpdatedAt.Year() != 2025 {
+		t.Errorf("parseOrderBookReply() sequence/updated_at = %d/%s", snapshot.Sequence, snapshot.UpdatedAt)
+	}
+	if len(snapshot.Bids) != 1 || !snapshot.Bids[0].Quantity.Equal(decimal.RequireFromString("2.5")) || snapshot.Bids[0].OrderCount != 2 {
+		t.Errorf("parseOrderBookReply() bids = %+v", snapshot.Bids)
+	}
+	if len(snapshot.Asks) != 1 || !snapshot.Asks[0].Price.Equal(decimal.NewFromInt(101)) {
+		t.Errorf("parseOrderBookReply() asks = %+v", snapshot.Asks)
+	}
+
+	reply[5] = []interface{}{"3"}
+	if _, err := parseOrderBookReply("BTC-USD", reply); err == nil {
+		t.Error("parseOrderBookReply() expected error for malformed level")
+	}
+}
@@ -0,0 +1,53 @@
+package adapters
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// scanBatchSize is the COUNT hint passed to SCAN
+	scanBatchSize = 500
+
+	// unlinkBatchSize is the maximum number of keys per UNLINK call
+	unlinkBatchSize = 500
+)
+
+// scanKeys collects all keys matching pattern using cursor-based SCAN instead of KEYS.
+// SCAN may return a key more than once, so results are deduplicated.
+func scanKeys(ctx context.Context, client *redis.Client, pattern string) ([]string, error) {
+	keys := []string{}
+	seen := make(map[string]struct{})
+	iter := client.Scan(ctx, 0, pattern, scanBatchSize).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		keys = append(keys, key)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// namespacedKeyIterator wraps a SCAN iterator and strips the namespace prefix from keys
+type namespacedKeyIterator struct {
+	iter   *redis.ScanIterator
+	prefix string
+}
+
+func (it *namespacedKeyIterator) Next(ctx context.Context) bool {
+	return it.iter.Next(ctx)
+}
+
+func (it *namespacedKeyIterator) Key() string {
+	return it.iter.Val()[len(it.prefix):]
+}
+
+func (it *namespacedKeyIterator) Err() error {
+	return it.iter.Err()
+}
@@ -3,7 +3,9 @@ package adapters
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
@@ -11,17 +13,40 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+var errServiceNotFound = errors.New("service not found")
+
+const (
+	defaultServiceTTL       = 90 * time.Second
+	deregisterOnStopTimeout = 5 * time.Second
+
+	// serviceUpdateRetries bounds optimistic retries when concurrent writers race on a service entry
+	serviceUpdateRetries = 10
+)
+
 type RedisServiceDiscovery struct {
-	client    *redis.Client
-	namespace string
-	logger    *logrus.Logger
+	client            *redis.Client
+	namespace         string
+	ttl               time.Duration
+	heartbeatInterval time.Duration
+	logger            *logrus.Logger
 }
 
-func NewRedisServiceDiscovery(client *redis.Client, namespace string, logger *logrus.Logger) interfaces.ServiceDiscoveryRepository {
+// NewRedisServiceDiscovery creates a service discovery repository whose registrations expire
+// after ttl unless refreshed. A non-positive ttl defaults to 90s and a non-positive
+// heartbeatInterval defaults to a third of the TTL.
+func NewRedisServiceDiscovery(client *redis.Client, namespace string, ttl, heartbeatInterval time.Duration, logger *logrus.Logger) interfaces.ServiceDiscoveryRepository {
+	if ttl <= 0 {
+		ttl = defaultServiceTTL
+	}
+	if heartbeatInterval <= 0 {
+		heartbeatInterval = ttl / 3
+	}
 	return &RedisServiceDiscovery{
-		client:    client,
-		namespace: namespace,
-		logger:    logger,
+		client:            client,
+		namespace:         namespace,
+		ttl:               ttl,
+		heartbeatInterval: heartbeatInterval,
+		logger:            logger,
 	}
 }
 
@@ -33,9 +58,26 @@ func (r *RedisServiceDiscovery) heartbeatKey(serviceID string) string {
 	return fmt.Sprintf("%s:heartbeat:%s", r.namespace, serviceID)
 }
 
+// indexKey is the set of service IDs registered under a service name
+func (r *RedisServiceDiscovery) indexKey(serviceName string) string {
+	return fmt.Sprintf("%s:index:%s", r.namespace, serviceName)
+}
+
 func (r *RedisServiceDiscovery) Register(ctx context.Context, info *interfaces.ServiceInfo) error {
 	key := r.serviceKey(info.ServiceID)
 	heartbeatKey := r.heartbeatKey(info.ServiceID)
+	indexKey := r.indexKey(info.ServiceName)
+
+	// Instances registering without a status are assumed ready to serve
+	if info.Status == "" {
+		info.Status = interfaces.ServiceStatusHealthy
+	}
+
+	now := time.Now()
+	if info.RegisteredAt.IsZero() {
+		info.RegisteredAt = now
+	}
+	info.LastHeartbeat = now
 
 	data, err := json.Marshal(info)
 	if err != nil {
@@ -43,17 +85,24 @@ func (r *RedisServiceDiscovery) Register(ctx context.Context, info *interfaces.S
 		return fmt.Errorf("failed to marshal service info: %w", err)
 	}
 
-	// Set service info with 90s TTL
-	if err := r.client.Set(ctx, key, data, 90*time.Second).Err(); err != nil {
+	// Set service info, initial heartbeat and name index with the service TTL
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, key, data, r.ttl)
+	pipe.Set(ctx, heartbeatKey, now.Unix(), r.ttl)
+	pipe.SAdd(ctx, indexKey, info.ServiceID)
+	pipe.Expire(ctx, indexKey, r.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
 		r.logger.WithError(err).Error("Failed to register service")
 		return fmt.Errorf("failed to register service: %w", err)
 	}
 
-	// Set initial heartbeat
-	if err := r.client.Set(ctx, heartbeatKey, time.Now().Unix(), 90*time.Second).Err(); err != nil {
-		r.logger.WithError(err).Error("Failed to set heartbeat")
-		return fmt.Errorf("failed to set heartbeat: %w", err)
-	}
+	r.publishEvent(ctx, &interfaces.ServiceEvent{
+		Type:        interfaces.ServiceEventRegistered,
+		ServiceName: info.ServiceName,
+		ServiceID:   info.ServiceID,
+		Info:        info,
+		Timestamp:   time.Now(),
+	})
 
 	r.logger.WithField("service_id", info.ServiceID).Info("Service registered")
 	return nil
@@ -63,54 +112,215 @@ func (r *RedisServiceDiscovery) Deregister(ctx context.Context, serviceID string
 	key := r.serviceKey(serviceID)
 	heartbeatKey := r.heartbeatKey(serviceID)
 
-	if err := r.client.Del(ctx, key, heartbeatKey).Err(); err != nil {
+	// Resolve the service name for the index; an already expired entry is pruned lazily
+	info, err := r.GetServiceInfo(ctx, serviceID)
+	if err != nil && !errors.Is(err, errServiceNotFound) {
+		return err
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, key, heartbeatKey)
+	if info != nil {
+		pipe.SRem(ctx, r.indexKey(info.ServiceName), serviceID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
 		r.logger.WithError(err).Error("Failed to deregister service")
 		return fmt.Errorf("failed to deregister service: %w", err)
 	}
 
+	if info != nil {
+		r.publishEvent(ctx, &interfaces.ServiceEvent{
+			Type:        interfaces.ServiceEventDeregistered,
+			ServiceName: info.ServiceName,
+			ServiceID:   serviceID,
+			Info:        info,
+			Timestamp:   time.Now(),
+		})
+	}
+
 	r.logger.WithField("service_id", serviceID).Info("Service deregistered")
 	return nil
 }
 
+// updateServiceInfo applies mutate to a service entry under WATCH/MULTI and writes the result
+// through write, retrying if the entry changes in between. Heartbeats and status changes
+// rewrite the same JSON entry, so without this a heartbeat could restore a status that a
+// concurrent UpdateStatus had just replaced.
+func (r *RedisServiceDiscovery) updateServiceInfo(ctx context.Context, serviceID string, mutate func(info *interfaces.ServiceInfo), write func(pipe redis.Pipeliner, info *interfaces.ServiceInfo, data []byte)) (*interfaces.ServiceInfo, error) {
+	key := r.serviceKey(serviceID)
+
+	var info *interfaces.ServiceInfo
+	update := func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, key).Result()
+		if err == redis.Nil {
+			return fmt.Errorf("%w: %s", errServiceNotFound, serviceID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get service info: %w", err)
+		}
+
+		info = &interfaces.ServiceInfo{}
+		if err := json.Unmarshal([]byte(data), info); err != nil {
+			return fmt.Errorf("failed to unmarshal service info: %w", err)
+		}
+		mutate(info)
+
+		updated, err := json.Marshal(info)
+		if err != nil {
+			return fmt.Errorf("failed to marshal service info: %w", err)
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			write(pipe, info, updated)
+			return nil
+		})
+		return err
+	}
+
+	for attempt := 0; attempt < serviceUpdateRetries; attempt++ {
+		err := r.client.Watch(ctx, update, key)
+		if err == redis.TxFailedErr {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return info, nil
+	}
+	return nil, fmt.Errorf("service %s changed concurrently %d times", serviceID, serviceUpdateRetries)
+}
+
 func (r *RedisServiceDiscovery) Heartbeat(ctx context.Context, serviceID string) error {
+	_, err := r.heartbeat(ctx, serviceID)
+	return err
+}
+
+// heartbeat refreshes a registration and returns the service info as written
+func (r *RedisServiceDiscovery) heartbeat(ctx context.Context, serviceID string) (*interfaces.ServiceInfo, error) {
 	heartbeatKey := r.heartbeatKey(serviceID)
 	serviceKey := r.serviceKey(serviceID)
 
-	// Update heartbeat timestamp
-	if err := r.client.Set(ctx, heartbeatKey, time.Now().Unix(), 90*time.Second).Err(); err != nil {
+	now := time.Now()
+	info, err := r.updateServiceInfo(ctx, serviceID,
+		func(info *interfaces.ServiceInfo) {
+			info.LastHeartbeat = now
+		},
+		func(pipe redis.Pipeliner, info *interfaces.ServiceInfo, data []byte) {
+			// Update heartbeat timestamps and refresh service and index TTLs
+			pipe.Set(ctx, heartbeatKey, now.Unix(), r.ttl)
+			pipe.Set(ctx, serviceKey, data, r.ttl)
+			pipe.SAdd(ctx, r.indexKey(info.ServiceName), serviceID)
+			pipe.Expire(ctx, r.indexKey(info.ServiceName), r.ttl)
+		})
+	if errors.Is(err, errServiceNotFound) {
+		return nil, err
+	}
+	if err != nil {
 		r.logger.WithError(err).Error("Failed to update heartbeat")
-		return fmt.Errorf("failed to update heartbeat: %w", err)
+		return nil, fmt.Errorf("failed to update heartbeat: %w", err)
 	}
 
-	// Refresh service key TTL
-	if err := r.client.Expire(ctx, serviceKey, 90*time.Second).Err(); err != nil {
-		r.logger.WithError(err).Error("Failed to refresh service TTL")
-		return fmt.Errorf("failed to refresh service TTL: %w", err)
+	return info, nil
+}
+
+func (r *RedisServiceDiscovery) StartHeartbeat(ctx context.Context, info *interfaces.ServiceInfo) (func(), error) {
+	if err := r.Register(ctx, info); err != nil {
+		return nil, err
 	}
 
-	return nil
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	// last is the registration as most recently seen in Redis, so that re-registering after
+	// expiry keeps a status set through UpdateStatus (e.g. DRAINING) instead of the original one
+	last := *info
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(r.heartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				// Deregister with a fresh context since ctx is already cancelled
+				deregisterCtx, cancelDeregister := context.WithTimeout(context.Background(), deregisterOnStopTimeout)
+				if err := r.Deregister(deregisterCtx, info.ServiceID); err != nil {
+					r.logger.WithError(err).WithField("service_id", info.ServiceID).Warn("Failed to deregister service on shutdown")
+				}
+				cancelDeregister()
+				return
+			case <-ticker.C:
+				current, err := r.heartbeat(ctx, info.ServiceID)
+				if err == nil {
+					last = *current
+				}
+				if errors.Is(err, errServiceNotFound) {
+					// Registration expired (e.g. Redis restart or missed heartbeats), register again
+					r.logger.WithFields(logrus.Fields{
+						"service_id": info.ServiceID,
+						"status":     last.Status,
+					}).Warn("Service registration expired, re-registering")
+					registration := last
+					if err = r.Register(ctx, &registration); err == nil {
+						last = registration
+					}
+				}
+				if err != nil && ctx.Err() == nil {
+					r.logger.WithError(err).WithField("service_id", info.ServiceID).Warn("Heartbeat failed")
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+
+	return stop, nil
 }
 
 func (r *RedisServiceDiscovery) Discover(ctx context.Context, serviceName string) ([]*interfaces.ServiceInfo, error) {
-	pattern := fmt.Sprintf("%s:service:*", r.namespace)
+	indexKey := r.indexKey(serviceName)
 
-	keys, err := r.client.Keys(ctx, pattern).Result()
+	serviceIDs, err := r.client.SMembers(ctx, indexKey).Result()
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to discover services")
 		return nil, fmt.Errorf("failed to discover services: %w", err)
 	}
 
 	services := []*interfaces.ServiceInfo{}
-	for _, key := range keys {
-		data, err := r.client.Get(ctx, key).Result()
-		if err != nil {
-			r.logger.WithError(err).WithField("key", key).Warn("Failed to get service data")
+	if len(serviceIDs) == 0 {
+		return services, nil
+	}
+
+	keys := make([]string, len(serviceIDs))
+	for i, serviceID := range serviceIDs {
+		keys[i] = r.serviceKey(serviceID)
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to discover services")
+		return nil, fmt.Errorf("failed to discover services: %w", err)
+	}
+
+	stale := []interface{}{}
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			// Service key expired without deregistering
+			stale = append(stale, serviceIDs[i])
 			continue
 		}
 
 		var info interfaces.ServiceInfo
 		if err := json.Unmarshal([]byte(data), &info); err != nil {
-			r.logger.WithError(err).WithField("key", key).Warn("Failed to unmarshal service info")
+			r.logger.WithError(err).WithField("key", keys[i]).Warn("Failed to unmarshal service info")
 			continue
 		}
 
@@ -119,15 +329,72 @@ func (r *RedisServiceDiscovery) Discover(ctx context.Context, serviceName string
 		}
 	}
 
+	if len(stale) > 0 {
+		if err := r.client.SRem(ctx, indexKey, stale...).Err(); err != nil {
+			r.logger.WithError(err).WithField("service_name", serviceName).Warn("Failed to prune stale index entries")
+		}
+	}
+
 	return services, nil
 }
 
+func (r *RedisServiceDiscovery) DiscoverHealthy(ctx context.Context, serviceName string, filter *interfaces.ServiceFilter) ([]*interfaces.ServiceInfo, error) {
+	var versionChecks []versionCheck
+	if filter != nil && filter.VersionConstraint != "" {
+		checks, err := parseVersionConstraint(filter.VersionConstraint)
+		if err != nil {
+			return nil, err
+		}
+		versionChecks = checks
+	}
+
+	services, err := r.Discover(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	healthy := []*interfaces.ServiceInfo{}
+	for _, info := range services {
+		if matchesServiceFilter(info, filter, versionChecks) {
+			healthy = append(healthy, info)
+		}
+	}
+
+	return healthy, nil
+}
+
+func (r *RedisServiceDiscovery) UpdateStatus(ctx context.Context, serviceID string, status interfaces.ServiceStatus) error {
+	serviceKey := r.serviceKey(serviceID)
+
+	_, err := r.updateServiceInfo(ctx, serviceID,
+		func(info *interfaces.ServiceInfo) {
+			info.Status = status
+		},
+		func(pipe redis.Pipeliner, info *interfaces.ServiceInfo, data []byte) {
+			// Keep the remaining TTL so a status change does not count as a heartbeat
+			pipe.Set(ctx, serviceKey, data, redis.KeepTTL)
+		})
+	if errors.Is(err, errServiceNotFound) {
+		return err
+	}
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to update service status")
+		return fmt.Errorf("failed to update service status: %w", err)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"service_id": serviceID,
+		"status":     status,
+	}).Info("Service status updated")
+	return nil
+}
+
 func (r *RedisServiceDiscovery) GetServiceInfo(ctx context.Context, serviceID string) (*interfaces.ServiceInfo, error) {
 	key := r.serviceKey(serviceID)
 
 	data, err := r.client.Get(ctx, key).Result()
 	if err == redis.Nil {
-		return nil, fmt.Errorf("service not found: %s", serviceID)
+		return nil, fmt.Errorf("%w: %s", errServiceNotFound, serviceID)
 	}
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to get service info")
@@ -146,7 +413,7 @@ func (r *RedisServiceDiscovery) GetServiceInfo(ctx context.Context, serviceID st
 func (r *RedisServiceDiscovery) ListServices(ctx context.Context) ([]*interfaces.ServiceInfo, error) {
 	pattern := fmt.Sprintf("%s:service:*", r.namespace)
 
-	keys, err := r.client.Keys(ctx, pattern).Result()
+	keys, err := scanKeys(ctx, r.client, pattern)
 	if err != nil {
 		r.logger.WithError(err).Error("Failed to list services")
 		return nil, fmt.Errorf("failed to list services: %w", err)
@@ -172,6 +439,78 @@ func (r *RedisServiceDiscovery) ListServices(ctx context.Context) ([]*interfaces
 	return services, nil
 }
 
+func (r *RedisServiceDiscovery) RepairIndex(ctx context.Context) (int, error) {
+	repaired := 0
+
+	// Add live services missing from their name index
+	serviceKeys, err := scanKeys(ctx, r.client, fmt.Sprintf("%s:service:*", r.namespace))
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to scan services for index repair")
+		return repaired, fmt.Errorf("failed to scan services: %w", err)
+	}
+
+	for _, key := range serviceKeys {
+		data, err := r.client.Get(ctx, key).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return repaired, fmt.Errorf("failed to get service data: %w", err)
+		}
+
+		var info interfaces.ServiceInfo
+		if err := json.Unmarshal([]byte(data), &info); err != nil {
+			r.logger.WithError(err).WithField("key", key).Warn("Failed to unmarshal service info")
+			continue
+		}
+
+		// Add and expire together, as Heartbeat does, so a repaired index never outlives its services
+		var added *redis.IntCmd
+		_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			added = pipe.SAdd(ctx, r.indexKey(info.ServiceName), info.ServiceID)
+			pipe.Expire(ctx, r.indexKey(info.ServiceName), r.ttl)
+			return nil
+		})
+		if err != nil {
+			r.logger.WithError(err).WithField("service_id", info.ServiceID).Error("Failed to repair service index")
+			return repaired, fmt.Errorf("failed to repair service index: %w", err)
+		}
+		repaired += int(added.Val())
+	}
+
+	// Remove index entries whose service key has expired
+	indexKeys, err := scanKeys(ctx, r.client, r.indexKey("*"))
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to scan service indexes for repair")
+		return repaired, fmt.Errorf("failed to scan service indexes: %w", err)
+	}
+
+	for _, indexKey := range indexKeys {
+		serviceIDs, err := r.client.SMembers(ctx, indexKey).Result()
+		if err != nil {
+			return repaired, fmt.Errorf("failed to read service index: %w", err)
+		}
+
+		for _, serviceID := range serviceIDs {
+			exists, err := r.client.Exists(ctx, r.serviceKey(serviceID)).Result()
+			if err != nil {
+				return repaired, fmt.Errorf("failed to check service existence: %w", err)
+			}
+			if exists > 0 {
+				continue
+			}
+			removed, err := r.client.SRem(ctx, indexKey, serviceID).Result()
+			if err != nil {
+				return repaired, fmt.Errorf("failed to prune service index: %w", err)
+			}
+			repaired += int(removed)
+		}
+	}
+
+	r.logger.WithField("repaired", repaired).Info("Service index repaired")
+	return repaired, nil
+}
+
 func (r *RedisServiceDiscovery) HealthCheck(ctx context.Context) error {
 	if err := r.client.Ping(ctx).Err(); err != nil {
 		return fmt.Errorf("service discovery health check failed: %w", err)
@@ -0,0 +1,51 @@
+package adapters
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// TestRedisServiceDiscoveryRepairIndex tests that RepairIndex restores a missing index entry
+// with an expiry and prunes entries whose service has gone
+func TestRedisServiceDiscoveryRepairIndex(t *testing.T) {
+	client, namespace := openTestRedis(t)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	discovery := NewRedisServiceDiscovery(client, namespace, time.Minute, 10*time.Second, logger)
+	r := discovery.(*RedisServiceDiscovery)
+	ctx := context.Background()
+
+	info := &interfaces.ServiceInfo{ServiceID: "sim-1", ServiceName: "exchange-simulator", Address: "localhost", Port: 8080}
+	if err := discovery.Register(ctx, info); err != nil {
+		t.Fatalf("Register() unexpected error: %v", err)
+	}
+	indexKey := r.indexKey(info.ServiceName)
+	if err := client.Del(ctx, indexKey).Err(); err != nil {
+		t.Fatalf("failed to drop service index: %v", err)
+	}
+	if err := client.SAdd(ctx, r.indexKey("gone"), "sim-gone").Err(); err != nil {
+		t.Fatalf("failed to add stale index entry: %v", err)
+	}
+
+	repaired, err := discovery.RepairIndex(ctx)
+	if err != nil {
+		t.Fatalf("RepairIndex() unexpected error: %v", err)
+	}
+	if repaired != 2 {
+		t.Errorf("RepairIndex() = %d, expected 2", repaired)
+	}
+	if members := client.SMembers(ctx, indexKey).Val(); len(members) != 1 || members[0] != "sim-1" {
+		t.Errorf("repaired index = %v, expected [sim-1]", members)
+	}
+	if ttl := client.TTL(ctx, indexKey).Val(); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("repaired index TTL = %v, expected an expiry within a minute", ttl)
+	}
+	if exists := client.Exists(ctx, r.indexKey("gone")).Val(); exists != 0 {
+		t.Error("stale index still exists")
+	}
+}
@@ -0,0 +1,201 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/redis/go-redis/v9"
+)
+
+// watchBufferSize is the capacity of the channel returned by Watch
+const watchBufferSize = 64
+
+// eventsChannel is the pub/sub channel Register and Deregister publish to for a service name
+func (r *RedisServiceDiscovery) eventsChannel(serviceName string) string {
+	return fmt.Sprintf("%s:events:%s", r.namespace, serviceName)
+}
+
+// expiredChannel is the keyspace notification channel for expired keys in the client's database
+func (r *RedisServiceDiscovery) expiredChannel() string {
+	return fmt.Sprintf("__keyevent@%d__:expired", r.client.Options().DB)
+}
+
+func (r *RedisServiceDiscovery) publishEvent(ctx context.Context, event *interfaces.ServiceEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		r.logger.WithError(err).Warn("Failed to marshal service event")
+		return
+	}
+	if err := r.client.Publish(ctx, r.eventsChannel(event.ServiceName), data).Err(); err != nil {
+		r.logger.WithError(err).WithField("service_id", event.ServiceID).Warn("Failed to publish service event")
+	}
+}
+
+// expiryNotificationsEnabled reports whether Redis emits keyevent notifications for expired keys.
+// CONFIG may be denied by ACL, in which case notifications are treated as unavailable.
+func (r *RedisServiceDiscovery) expiryNotificationsEnabled(ctx context.Context) bool {
+	config, err := r.client.ConfigGet(ctx, "notify-keyspace-events").Result()
+	if err != nil {
+		r.logger.WithError(err).Debug("Unable to read keyspace notification config")
+		return false
+	}
+	flags := config["notify-keyspace-events"]
+	return strings.Contains(flags, "E") && (strings.Contains(flags, "x") || strings.Contains(flags, "A"))
+}
+
+func (r *RedisServiceDiscovery) Watch(ctx context.Context, serviceName string) (<-chan interfaces.ServiceEvent, error) {
+	channels := []string{r.eventsChannel(serviceName)}
+	notifications := r.expiryNotificationsEnabled(ctx)
+	if notifications {
+		channels = append(channels, r.expiredChannel())
+	}
+
+	pubsub := r.client.Subscribe(ctx, channels...)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		r.logger.WithError(err).Error("Failed to subscribe to service events")
+		return nil, fmt.Errorf("failed to subscribe to service events: %w", err)
+	}
+
+	initial, err := r.Discover(ctx, serviceName)
+	if err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+	known := make(map[string]struct{}, len(initial))
+	for _, info := range initial {
+		known[info.ServiceID] = struct{}{}
+	}
+
+	// Without expiry notifications, poll to detect instances that stopped heartbeating
+	var ticker *time.Ticker
+	var poll <-chan time.Time
+	if !notifications {
+		r.logger.WithField("service_name", serviceName).Info("Keyspace notifications disabled, polling for expired services")
+		ticker = time.NewTicker(r.heartbeatInterval)
+		poll = ticker.C
+	}
+
+	events := make(chan interfaces.ServiceEvent, watchBufferSize)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+		if ticker != nil {
+			defer ticker.Stop()
+		}
+
+		send := func(event interfaces.ServiceEvent) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		messages := pubsub.Channel()
+		servicePrefix := fmt.Sprintf("%s:service:", r.namespace)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				event, ok := r.watchMessageEvent(msg, servicePrefix, serviceName, known)
+				if ok && !send(event) {
+					return
+				}
+
+			case <-poll:
+				for _, event := range r.pollServiceEvents(ctx, serviceName, known) {
+					if !send(event) {
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// watchMessageEvent converts a pub/sub or keyspace message into an event and updates known instances
+func (r *RedisServiceDiscovery) watchMessageEvent(msg *redis.Message, servicePrefix, serviceName string, known map[string]struct{}) (interfaces.ServiceEvent, bool) {
+	if msg.Channel == r.eventsChannel(serviceName) {
+		var event interfaces.ServiceEvent
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			r.logger.WithError(err).Warn("Failed to unmarshal service event")
+			return event, false
+		}
+		switch event.Type {
+		case interfaces.ServiceEventRegistered:
+			known[event.ServiceID] = struct{}{}
+		case interfaces.ServiceEventDeregistered:
+			delete(known, event.ServiceID)
+		}
+		return event, true
+	}
+
+	// Expired keyspace event: only service keys of instances we know belong to this name
+	if !strings.HasPrefix(msg.Payload, servicePrefix) {
+		return interfaces.ServiceEvent{}, false
+	}
+	serviceID := strings.TrimPrefix(msg.Payload, servicePrefix)
+	if _, ok := known[serviceID]; !ok {
+		return interfaces.ServiceEvent{}, false
+	}
+	delete(known, serviceID)
+
+	return interfaces.ServiceEvent{
+		Type:        interfaces.ServiceEventExpired,
+		ServiceName: serviceName,
+		ServiceID:   serviceID,
+		Timestamp:   time.Now(),
+	}, true
+}
+
+// pollServiceEvents diffs the current registrations against known instances
+func (r *RedisServiceDiscovery) pollServiceEvents(ctx context.Context, serviceName string, known map[string]struct{}) []interfaces.ServiceEvent {
+	services, err := r.Discover(ctx, serviceName)
+	if err != nil {
+		r.logger.WithError(err).WithField("service_name", serviceName).Warn("Failed to poll services")
+		return nil
+	}
+
+	now := time.Now()
+	events := []interfaces.ServiceEvent{}
+	current := make(map[string]struct{}, len(services))
+	for _, info := range services {
+		current[info.ServiceID] = struct{}{}
+		if _, ok := known[info.ServiceID]; !ok {
+			known[info.ServiceID] = struct{}{}
+			events = append(events, interfaces.ServiceEvent{
+				Type:        interfaces.ServiceEventRegistered,
+				ServiceName: serviceName,
+				ServiceID:   info.ServiceID,
+				Info:        info,
+				Timestamp:   now,
+			})
+		}
+	}
+	for serviceID := range known {
+		if _, ok := current[serviceID]; !ok {
+			delete(known, serviceID)
+			events = append(events, interfaces.ServiceEvent{
+				Type:        interfaces.ServiceEventExpired,
+				ServiceName: serviceName,
+				ServiceID:   serviceID,
+				Timestamp:   now,
+			})
+		}
+	}
+	return events
+}
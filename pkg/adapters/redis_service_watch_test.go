@@ -0,0 +1,91 @@
+package adapters
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// TestWatchMessageEvent tests conversion of pub/sub and keyspace messages into service events
+func TestWatchMessageEvent(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	r := &RedisServiceDiscovery{namespace: "exchange", logger: logger}
+
+	registered, _ := json.Marshal(interfaces.ServiceEvent{
+		Type: interfaces.ServiceEventRegistered, ServiceName: "exchange-simulator", ServiceID: "sim-2",
+	})
+	deregistered, _ := json.Marshal(interfaces.ServiceEvent{
+		Type: interfaces.ServiceEventDeregistered, ServiceName: "exchange-simulator", ServiceID: "sim-1",
+	})
+
+	tests := []struct {
+		name          string
+		msg           *redis.Message
+		expectedOK    bool
+		expectedType  interfaces.ServiceEventType
+		expectedKnown []string
+	}{
+		{
+			name:          "registered event adds instance",
+			msg:           &redis.Message{Channel: "exchange:events:exchange-simulator", Payload: string(registered)},
+			expectedOK:    true,
+			expectedType:  interfaces.ServiceEventRegistered,
+			expectedKnown: []string{"sim-1", "sim-2"},
+		},
+		{
+			name:          "deregistered event removes instance",
+			msg:           &redis.Message{Channel: "exchange:events:exchange-simulator", Payload: string(deregistered)},
+			expectedOK:    true,
+			expectedType:  interfaces.ServiceEventDeregistered,
+			expectedKnown: []string{},
+		},
+		{
+			name:          "expired key of known instance",
+			msg:           &redis.Message{Channel: "__keyevent@0__:expired", Payload: "exchange:service:sim-1"},
+			expectedOK:    true,
+			expectedType:  interfaces.ServiceEventExpired,
+			expectedKnown: []string{},
+		},
+		{
+			name:          "expired key of other service",
+			msg:           &redis.Message{Channel: "__keyevent@0__:expired", Payload: "exchange:service:risk-1"},
+			expectedKnown: []string{"sim-1"},
+		},
+		{
+			name:          "expired heartbeat key",
+			msg:           &redis.Message{Channel: "__keyevent@0__:expired", Payload: "exchange:heartbeat:sim-1"},
+			expectedKnown: []string{"sim-1"},
+		},
+		{
+			name:          "malformed event",
+			msg:           &redis.Message{Channel: "exchange:events:exchange-simulator", Payload: "{"},
+			expectedKnown: []string{"sim-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			known := map[string]struct{}{"sim-1": {}}
+			event, ok := r.watchMessageEvent(tt.msg, "exchange:service:", "exchange-simulator", known)
+			if ok != tt.expectedOK {
+				t.Fatalf("ok = %v, expected %v", ok, tt.expectedOK)
+			}
+			if ok && event.Type != tt.expectedType {
+				t.Errorf("event type = %s, expected %s", event.Type, tt.expectedType)
+			}
+			if len(known) != len(tt.expectedKnown) {
+				t.Errorf("known = %v, expected %v", known, tt.expectedKnown)
+			}
+			for _, id := range tt.expectedKnown {
+				if _, found := known[id]; !found {
+					t.Errorf("expected %s to be known", id)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// StreamHandler processes one event; returning an error leaves it pending for redelivery
+type StreamHandler func(ctx context.Context, event *models.OutboxEvent) error
+
+// StreamConsumerOptions configures a RedisStreamConsumer
+type StreamConsumerOptions struct {
+	// BatchSize is the maximum number of entries read or reclaimed at once
+	BatchSize int64
+
+	// Block is how long a read waits for new entries
+	Block time.Duration
+
+	// ClaimMinIdle is how long an entry must be pending on another consumer before it is reclaimed
+	ClaimMinIdle time.Duration
+
+	// StartID is where a newly created group starts reading: "$" for new entries only, "0" for all
+	StartID string
+
+	// MaxDeliveries is how many times an entry is delivered to the group before a reclaim moves
+	// it to the dead-letter stream rather than delivering it again
+	MaxDeliveries int64
+}
+
+// RedisStreamConsumer reads an event stream as a member of a consumer group. Entries are
+// acknowledged after the handler succeeds; entries left pending by failed handlers or crashed
+// consumers are reclaimed once idle for ClaimMinIdle. Entries already delivered MaxDeliveries
+// times are moved to the group's dead-letter stream, {stream}:dead:{group}, and acknowledged
+// instead. Delivery is at-least-once, so handlers should be idempotent on EventID.
+type RedisStreamConsumer struct {
+	client     *redis.Client
+	stream     string
+	deadLetter string
+	group      string
+	consumer   string
+	options    StreamConsumerOptions
+	logger     *logrus.Logger
+}
+
+func NewRedisStreamConsumer(client *redis.Client, namespace, category, group, consumer string, options StreamConsumerOptions, logger *logrus.Logger) *RedisStreamConsumer {
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+	if options.Block <= 0 {
+		options.Block = 5 * time.Second
+	}
+	if options.ClaimMinIdle <= 0 {
+		options.ClaimMinIdle = time.Minute
+	}
+	if options.StartID == "" {
+		options.StartID = "$"
+	}
+	if options.MaxDeliveries <= 0 {
+		options.MaxDeliveries = 10
+	}
+	stream := eventStreamKey(namespace, category)
+	return &RedisStreamConsumer{
+		client:     client,
+		stream:     stream,
+		deadLetter: stream + ":dead:" + group,
+		group:      group,
+		consumer:   consumer,
+		options:    options,
+		logger:     logger,
+	}
+}
+
+// DeadLetterStream returns the key of the stream holding entries that exhausted MaxDeliveries
+func (c *RedisStreamConsumer) DeadLetterStream() string {
+	return c.deadLetter
+}
+
+// EnsureGroup creates the stream and consumer group if they do not exist
+func (c *RedisStreamConsumer) EnsureGroup(ctx context.Context) error {
+	err := c.client.XGroupCreateMkStream(ctx, c.stream, c.group, c.options.StartID).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		c.logger.WithError(err).WithField("stream", c.stream).Error("Failed to create consumer group")
+		return fmt.Errorf("failed to create consumer group: %w", err)
+	}
+	return nil
+}
+
+// Read returns up to BatchSize entries not yet delivered to the group, waiting up to Block
+func (c *RedisStreamConsumer) Read(ctx context.Context) ([]redis.XMessage, error) {
+	streams, err := c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    c.group,
+		Consumer: c.consumer,
+		Streams:  []string{c.stream, ">"},
+		Count:    c.options.BatchSize,
+		Block:    c.options.Block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	var messages []redis.XMessage
+	for _, stream := range streams {
+		messages = append(messages, stream.Messages...)
+	}
+	return messages, nil
+}
+
+// Reclaim takes over entries that have been pending on any consumer for at least ClaimMinIdle.
+// Entries delivered more than MaxDeliveries times, counting this claim, are dead-lettered
+// rather than returned.
+func (c *RedisStreamConsumer) Reclaim(ctx context.Context) ([]redis.XMessage, error) {
+	var claimed []redis.XMessage
+	start := "0-0"
+	for {
+		messages, next, err := c.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   c.stream,
+			Group:    c.group,
+			Consumer: c.consumer,
+			MinIdle:  c.options.ClaimMinIdle,
+			Start:    start,
+			Count:    c.options.BatchSize,
+		}).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to reclaim pending entries: %w", err)
+		}
+		live, err := c.deadLetterExhausted(ctx, messages)
+		if err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, live...)
+		if next == "0-0" || int64(len(claimed)) >= c.options.BatchSize {
+			return claimed, nil
+		}
+		start = next
+	}
+}
+
+// deadLetterExhausted moves claimed entries delivered more than MaxDeliveries times to the
+// dead-letter stream and returns the rest
+func (c *RedisStreamConsumer) deadLetterExhausted(ctx context.Context, messages []redis.XMessage) ([]redis.XMessage, error) {
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	pipe := c.client.Pipeline()
+	pending := make([]*redis.XPendingExtCmd, len(messages))
+	for i, message := range messages {
+		pending[i] = pipe.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: c.stream,
+			Group:  c.group,
+			Start:  message.ID,
+			End:    message.ID,
+			Count:  1,
+		})
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to read delivery counts: %w", err)
+	}
+
+	live := make([]redis.XMessage, 0, len(messages))
+	for i, message := range messages {
+		entries := pending[i].Val()
+		if len(entries) == 0 || entries[0].RetryCount <= c.options.MaxDeliveries {
+			live = append(live, message)
+			continue
+		}
+		if err := c.moveToDeadLetter(ctx, message, entries[0].RetryCount); err != nil {
+			return nil, err
+		}
+	}
+	return live, nil
+}
+
+// moveToDeadLetter copies an entry to the dead-letter stream and acknowledges it atomically
+func (c *RedisStreamConsumer) moveToDeadLetter(ctx context.Context, message redis.XMessage, deliveries int64) error {
+	values := make(map[string]interface{}, len(message.Values)+2)
+	for field, value := range message.Values {
+		values[field] = value
+	}
+	values["dead_entry_id"] = message.ID
+	values["dead_deliveries"] = deliveries
+
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAdd(ctx, &redis.XAddArgs{Stream: c.deadLetter, Values: values})
+		pipe.XAck(ctx, c.stream, c.group, message.ID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to dead-letter entry %s: %w", message.ID, err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"stream":      c.stream,
+		"group":       c.group,
+		"entry_id":    message.ID,
+		"deliveries":  deliveries,
+		"dead_letter": c.deadLetter,
+	}).Error("Stream entry exhausted its deliveries, moved to dead-letter stream")
+	return nil
+}
+
+// Ack acknowledges processed entries
+func (c *RedisStreamConsumer) Ack(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := c.client.XAck(ctx, c.stream, c.group, ids...).Err(); err != nil {
+		return fmt.Errorf("failed to acknowledge entries: %w", err)
+	}
+	return nil
+}
+
+// Run creates the group if needed and processes entries until the context is cancelled.
+// Idle pending entries are reclaimed before each read.
+func (c *RedisStreamConsumer) Run(ctx context.Context, handler StreamHandler) error {
+	if err := c.EnsureGroup(ctx); err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+		claimed, err := c.Reclaim(ctx)
+		if err != nil {
+			c.logger.WithError(err).WithField("stream", c.stream).Warn("Failed to reclaim stream entries")
+		}
+		c.handle(ctx, claimed, handler)
+
+		messages, err := c.Read(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			c.logger.WithError(err).WithField("stream", c.stream).Warn("Failed to read stream entries")
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		c.handle(ctx, messages, handler)
+	}
+
+	return nil
+}
+
+func (c *RedisStreamConsumer) handle(ctx context.Context, messages []redis.XMessage, handler StreamHandler) {
+	acked := make([]string, 0, len(messages))
+	for _, message := range messages {
+		event, err := decodeStreamEvent(message.Values)
+		if err != nil {
+			// Malformed entries can never succeed, so acknowledge rather than redeliver forever
+			c.logger.WithError(err).WithField("entry_id", message.ID).Error("Discarding malformed stream entry")
+			acked = append(acked, message.ID)
+			continue
+		}
+		if err := handler(ctx, event); err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"entry_id": message.ID,
+				"event_id": event.EventID,
+			}).Warn("Stream handler failed, entry left pending")
+			continue
+		}
+		acked = append(acked, message.ID)
+	}
+
+	if err := c.Ack(ctx, acked...); err != nil {
+		c.logger.WithError(err).WithField("stream", c.stream).Warn("Failed to acknowledge stream entries")
+	}
+}
@@ -0,0 +1,71 @@
+package adapters
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// TestRedisStreamConsumerDeadLetter tests that Reclaim redelivers an entry until it has been
+// delivered MaxDeliveries times and then moves it to the dead-letter stream
+func TestRedisStreamConsumerDeadLetter(t *testing.T) {
+	client, namespace := openTestRedis(t)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx := context.Background()
+
+	options := StreamConsumerOptions{Block: 10 * time.Millisecond, ClaimMinIdle: time.Millisecond, StartID: "0", MaxDeliveries: 2}
+	first := NewRedisStreamConsumer(client, namespace, "order", "projector", "first", options, logger)
+	second := NewRedisStreamConsumer(client, namespace, "order", "projector", "second", options, logger)
+	if err := first.EnsureGroup(ctx); err != nil {
+		t.Fatalf("EnsureGroup() unexpected error: %v", err)
+	}
+	id, err := client.XAdd(ctx, &redis.XAddArgs{Stream: first.stream, Values: map[string]interface{}{"event_id": "1"}}).Result()
+	if err != nil {
+		t.Fatalf("failed to add stream entry: %v", err)
+	}
+
+	messages, err := first.Read(ctx)
+	if err != nil || len(messages) != 1 {
+		t.Fatalf("Read() = %d entries, %v; expected the entry", len(messages), err)
+	}
+
+	steps := []struct {
+		name      string
+		consumer  *RedisStreamConsumer
+		delivered int
+	}{
+		{name: "second delivery", consumer: second, delivered: 1},
+		{name: "third delivery dead-letters", consumer: first, delivered: 0},
+	}
+	for _, step := range steps {
+		time.Sleep(5 * time.Millisecond)
+		claimed, err := step.consumer.Reclaim(ctx)
+		if err != nil {
+			t.Fatalf("%s: Reclaim() unexpected error: %v", step.name, err)
+		}
+		if len(claimed) != step.delivered {
+			t.Errorf("%s: Reclaim() = %d entries, expected %d", step.name, len(claimed), step.delivered)
+		}
+	}
+
+	dead, err := client.XRange(ctx, first.DeadLetterStream(), "-", "+").Result()
+	if err != nil {
+		t.Fatalf("failed to read dead-letter stream: %v", err)
+	}
+	if len(dead) != 1 || dead[0].Values["dead_entry_id"] != id || dead[0].Values["event_id"] != "1" ||
+		dead[0].Values["dead_deliveries"] != "3" {
+		t.Errorf("dead-letter stream = %+v, expected entry %s after 3 deliveries", dead, id)
+	}
+	pending, err := client.XPending(ctx, first.stream, "projector").Result()
+	if err != nil {
+		t.Fatalf("failed to read pending entries: %v", err)
+	}
+	if pending.Count != 0 {
+		t.Errorf("pending entries = %d, expected the dead-lettered entry acknowledged", pending.Count)
+	}
+}
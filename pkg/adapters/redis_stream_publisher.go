@@ -0,0 +1,122 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// eventStreamKey returns the stream holding events of one category
+func eventStreamKey(namespace, category string) string {
+	return fmt.Sprintf("%s:stream:%s", namespace, category)
+}
+
+// RedisStreamPublisher writes outbox events to one Redis Stream per category (order, trade,
+// balance, account), e.g. {namespace}:stream:order. Streams are trimmed approximately to maxLen entries.
+type RedisStreamPublisher struct {
+	client    *redis.Client
+	namespace string
+	maxLen    int64
+	logger    *logrus.Logger
+}
+
+func NewRedisStreamPublisher(client *redis.Client, namespace string, maxLen int64, logger *logrus.Logger) interfaces.EventPublisher {
+	return &RedisStreamPublisher{
+		client:    client,
+		namespace: namespace,
+		maxLen:    maxLen,
+		logger:    logger,
+	}
+}
+
+// encodeStreamEvent flattens an outbox event into stream entry fields
+func encodeStreamEvent(event *models.OutboxEvent) map[string]interface{} {
+	return map[string]interface{}{
+		"event_id":       strconv.FormatInt(event.EventID, 10),
+		"event_type":     string(event.EventType),
+		"aggregate_type": event.AggregateType,
+		"aggregate_id":   event.AggregateID,
+		"payload":        string(event.Payload),
+		"created_at":     event.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// decodeStreamEvent rebuilds an outbox event from stream entry fields
+func decodeStreamEvent(values map[string]interface{}) (*models.OutboxEvent, error) {
+	field := func(name string) (string, error) {
+		value, ok := values[name].(string)
+		if !ok {
+			return "", fmt.Errorf("stream entry missing field %s", name)
+		}
+		return value, nil
+	}
+
+	event := &models.OutboxEvent{}
+	var err error
+	var eventID, eventType, payload, createdAt string
+	if eventID, err = field("event_id"); err != nil {
+		return nil, err
+	}
+	if event.EventID, err = strconv.ParseInt(eventID, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid event_id %q: %w", eventID, err)
+	}
+	if eventType, err = field("event_type"); err != nil {
+		return nil, err
+	}
+	event.EventType = models.EventType(eventType)
+	if event.AggregateType, err = field("aggregate_type"); err != nil {
+		return nil, err
+	}
+	if event.AggregateID, err = field("aggregate_id"); err != nil {
+		return nil, err
+	}
+	if payload, err = field("payload"); err != nil {
+		return nil, err
+	}
+	event.Payload = []byte(payload)
+	if createdAt, err = field("created_at"); err != nil {
+		return nil, err
+	}
+	if event.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return nil, fmt.Errorf("invalid created_at %q: %w", createdAt, err)
+	}
+	return event, nil
+}
+
+// eventStreamCategory picks the stream for an event. Trade events written before trades became
+// their own aggregate are keyed to their order, so they are routed by event type.
+func eventStreamCategory(event *models.OutboxEvent) string {
+	if event.EventType == models.EventTypeTradeExecuted {
+		return models.AggregateTypeTrade
+	}
+	return event.AggregateType
+}
+
+func (p *RedisStreamPublisher) Publish(ctx context.Context, event *models.OutboxEvent) error {
+	stream := eventStreamKey(p.namespace, eventStreamCategory(event))
+
+	args := &redis.XAddArgs{
+		Stream: stream,
+		Values: encodeStreamEvent(event),
+	}
+	if p.maxLen > 0 {
+		args.MaxLen = p.maxLen
+		args.Approx = true
+	}
+
+	if err := p.client.XAdd(ctx, args).Err(); err != nil {
+		p.logger.WithError(err).WithFields(logrus.Fields{
+			"stream":   stream,
+			"event_id": event.EventID,
+		}).Error("Failed to publish event to stream")
+		return fmt.Errorf("failed to publish event to stream: %w", err)
+	}
+
+	return nil
+}
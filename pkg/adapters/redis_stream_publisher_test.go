@@ -0,0 +1,44 @@
+package adapters
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+)
+
+// TestStreamEventRoundTrip tests encoding outbox events as stream entries and back
+func TestStreamEventRoundTrip(t *testing.T) {
+	event := &models.OutboxEvent{
+		EventID:       42,
+		AggregateType: models.AggregateTypeOrder,
+		AggregateID:   "order-1",
+		EventType:     models.EventTypeOrderStatusChanged,
+		Payload:       json.RawMessage(`{"order_id":"order-1","status":"FILLED"}`),
+		CreatedAt:     time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC),
+	}
+
+	if stream := eventStreamKey("exchange_okx", eventStreamCategory(event)); stream != "exchange_okx:stream:order" {
+		t.Errorf("eventStreamKey() = %q", stream)
+	}
+	trade := &models.OutboxEvent{AggregateType: models.AggregateTypeOrder, EventType: models.EventTypeTradeExecuted}
+	if category := eventStreamCategory(trade); category != models.AggregateTypeTrade {
+		t.Errorf("eventStreamCategory(TradeExecuted) = %q, expected trade", category)
+	}
+
+	decoded, err := decodeStreamEvent(encodeStreamEvent(event))
+	if err != nil {
+		t.Fatalf("decodeStreamEvent() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, event) {
+		t.Errorf("decodeStreamEvent() = %+v, expected %+v", decoded, event)
+	}
+
+	values := encodeStreamEvent(event)
+	delete(values, "payload")
+	if _, err := decodeStreamEvent(values); err == nil {
+		t.Error("decodeStreamEvent() expected error for missing payload")
+	}
+}
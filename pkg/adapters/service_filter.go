@@ -0,0 +1,174 @@
+package adapters
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+)
+
+// semanticVersion is a major.minor.patch version; pre-release and build suffixes are ignored
+type semanticVersion [3]int
+
+func parseSemanticVersion(version string) (semanticVersion, int, error) {
+	var v semanticVersion
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+
+	parts := strings.Split(version, ".")
+	if version == "" || len(parts) > 3 {
+		return v, 0, fmt.Errorf("invalid version: %q", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, 0, fmt.Errorf("invalid version: %q", version)
+		}
+		v[i] = n
+	}
+	return v, len(parts), nil
+}
+
+func (v semanticVersion) compare(other semanticVersion) int {
+	for i := range v {
+		if v[i] != other[i] {
+			if v[i] < other[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionCheck is a single comparison against a bound
+type versionCheck struct {
+	op    string
+	bound semanticVersion
+}
+
+func (c versionCheck) matches(v semanticVersion) bool {
+	cmp := v.compare(c.bound)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// parseVersionConstraint parses a comma-separated constraint list into comparisons that must all hold.
+// Caret (^) allows changes that do not modify the left-most non-zero component; tilde (~) allows
+// patch-level changes, or minor-level changes when only a major version is given.
+func parseVersionConstraint(constraint string) ([]versionCheck, error) {
+	checks := []versionCheck{}
+	for _, term := range strings.Split(constraint, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		op := ""
+		for _, candidate := range []string{">=", "<=", "!=", "==", ">", "<", "=", "^", "~"} {
+			if strings.HasPrefix(term, candidate) {
+				op = candidate
+				break
+			}
+		}
+		bound, precision, err := parseSemanticVersion(term[len(op):])
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", term, err)
+		}
+
+		switch op {
+		case "", "==":
+			checks = append(checks, versionCheck{op: "=", bound: bound})
+		case "^":
+			upper := semanticVersion{bound[0] + 1, 0, 0}
+			switch {
+			case bound[0] == 0 && bound[1] == 0 && precision > 2:
+				upper = semanticVersion{0, 0, bound[2] + 1}
+			case bound[0] == 0 && precision > 1:
+				upper = semanticVersion{0, bound[1] + 1, 0}
+			}
+			checks = append(checks, versionCheck{op: ">=", bound: bound}, versionCheck{op: "<", bound: upper})
+		case "~":
+			upper := semanticVersion{bound[0], bound[1] + 1, 0}
+			if precision == 1 {
+				upper = semanticVersion{bound[0] + 1, 0, 0}
+			}
+			checks = append(checks, versionCheck{op: ">=", bound: bound}, versionCheck{op: "<", bound: upper})
+		default:
+			checks = append(checks, versionCheck{op: op, bound: bound})
+		}
+	}
+	return checks, nil
+}
+
+// matchesServiceFilter reports whether a service instance satisfies the filter.
+// Instances with unparseable versions never match a version constraint.
+func matchesServiceFilter(info *interfaces.ServiceInfo, filter *interfaces.ServiceFilter, versionChecks []versionCheck) bool {
+	statuses := []interfaces.ServiceStatus{interfaces.ServiceStatusHealthy}
+	if filter != nil && len(filter.Statuses) > 0 {
+		statuses = filter.Statuses
+	}
+	statusMatch := false
+	for _, status := range statuses {
+		if info.Status == status {
+			statusMatch = true
+			break
+		}
+	}
+	if !statusMatch {
+		return false
+	}
+
+	if len(versionChecks) > 0 {
+		version, _, err := parseSemanticVersion(info.Version)
+		if err != nil {
+			return false
+		}
+		for _, check := range versionChecks {
+			if !check.matches(version) {
+				return false
+			}
+		}
+	}
+
+	if filter == nil {
+		return true
+	}
+
+	for key, value := range filter.Metadata {
+		if actual, ok := info.Metadata[key]; !ok || actual != value {
+			return false
+		}
+	}
+
+	for _, tag := range filter.Tags {
+		found := false
+		for _, actual := range info.Tags {
+			if actual == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
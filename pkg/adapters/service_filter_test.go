@@ -0,0 +1,104 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+)
+
+// TestParseVersionConstraint tests semantic version constraint matching
+func TestParseVersionConstraint(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint string
+		version    string
+		expected   bool
+		expectErr  bool
+	}{
+		{name: "exact match", constraint: "1.2.3", version: "1.2.3", expected: true},
+		{name: "exact mismatch", constraint: "=1.2.3", version: "1.2.4", expected: false},
+		{name: "v prefix", constraint: ">=1.2.0", version: "v1.3.0", expected: true},
+		{name: "range inside", constraint: ">=1.2.0, <2.0.0", version: "1.9.9", expected: true},
+		{name: "range outside", constraint: ">=1.2.0, <2.0.0", version: "2.0.0", expected: false},
+		{name: "not equal", constraint: "!=1.0.0", version: "1.0.0", expected: false},
+		{name: "caret same major", constraint: "^1.4", version: "1.9.0", expected: true},
+		{name: "caret next major", constraint: "^1.4", version: "2.0.0", expected: false},
+		{name: "caret below minimum", constraint: "^1.4", version: "1.3.9", expected: false},
+		{name: "caret zero major", constraint: "^0.3.1", version: "0.4.0", expected: false},
+		{name: "caret zero minor pins patch", constraint: "^0.0.3", version: "0.0.3", expected: true},
+		{name: "caret zero minor next patch", constraint: "^0.0.3", version: "0.0.4", expected: false},
+		{name: "caret zero minor without patch", constraint: "^0.0", version: "0.0.9", expected: true},
+		{name: "tilde patch", constraint: "~1.4.2", version: "1.4.9", expected: true},
+		{name: "tilde next minor", constraint: "~1.4.2", version: "1.5.0", expected: false},
+		{name: "tilde major only", constraint: "~1", version: "1.8.0", expected: true},
+		{name: "pre-release suffix ignored", constraint: ">=1.0.0", version: "1.0.0-rc1", expected: true},
+		{name: "invalid constraint", constraint: ">=one", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks, err := parseVersionConstraint(tt.constraint)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("parseVersionConstraint(%q) expected error", tt.constraint)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseVersionConstraint(%q) unexpected error: %v", tt.constraint, err)
+			}
+
+			info := &interfaces.ServiceInfo{Status: interfaces.ServiceStatusHealthy, Version: tt.version}
+			result := matchesServiceFilter(info, nil, checks)
+			if result != tt.expected {
+				t.Errorf("constraint %q with version %s = %v, expected %v", tt.constraint, tt.version, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestMatchesServiceFilter tests status, metadata and tag filtering
+func TestMatchesServiceFilter(t *testing.T) {
+	info := &interfaces.ServiceInfo{
+		Status:   interfaces.ServiceStatusHealthy,
+		Version:  "1.2.0",
+		Tags:     []string{"primary", "okx"},
+		Metadata: map[string]string{"region": "eu"},
+	}
+
+	tests := []struct {
+		name     string
+		info     *interfaces.ServiceInfo
+		filter   *interfaces.ServiceFilter
+		expected bool
+	}{
+		{name: "nil filter accepts healthy", info: info, filter: nil, expected: true},
+		{
+			name:     "nil filter rejects draining",
+			info:     &interfaces.ServiceInfo{Status: interfaces.ServiceStatusDraining},
+			filter:   nil,
+			expected: false,
+		},
+		{
+			name: "explicit statuses accept degraded",
+			info: &interfaces.ServiceInfo{Status: interfaces.ServiceStatusDegraded},
+			filter: &interfaces.ServiceFilter{
+				Statuses: []interfaces.ServiceStatus{interfaces.ServiceStatusHealthy, interfaces.ServiceStatusDegraded},
+			},
+			expected: true,
+		},
+		{name: "metadata match", info: info, filter: &interfaces.ServiceFilter{Metadata: map[string]string{"region": "eu"}}, expected: true},
+		{name: "metadata mismatch", info: info, filter: &interfaces.ServiceFilter{Metadata: map[string]string{"region": "us"}}, expected: false},
+		{name: "tags present", info: info, filter: &interfaces.ServiceFilter{Tags: []string{"okx"}}, expected: true},
+		{name: "tag missing", info: info, filter: &interfaces.ServiceFilter{Tags: []string{"okx", "backup"}}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := matchesServiceFilter(tt.info, tt.filter, nil)
+			if result != tt.expected {
+				t.Errorf("matchesServiceFilter() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package adapters
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// stubDriver is a database/sql driver that answers queries with canned rows, so that row
+// scanning, including NULL handling, goes through the real database/sql conversions. Scripted
+// databases also record every statement, including transaction boundaries, in order.
+type stubDriver struct{}
+
+// stubResponse answers the first unanswered statement containing fragment
+type stubResponse struct {
+	fragment string
+	columns  []string
+	rows     [][]driver.Value
+	err      error
+	affected int64
+	used     bool
+}
+
+// stubCall is a statement run against a scripted database
+type stubCall struct {
+	query string
+	args  []driver.Value
+}
+
+// stubScript holds the responses and recorded statements of one stub database
+type stubScript struct {
+	mu        sync.Mutex
+	fallback  *stubResponse
+	responses []*stubResponse
+	calls     []stubCall
+}
+
+var (
+	stubResultsMu sync.Mutex
+	stubResults   = map[string]*stubScript{}
+)
+
+func init() {
+	sql.Register("adapters-stub", stubDriver{})
+}
+
+func registerStub(t *testing.T, script *stubScript) *sql.DB {
+	t.Helper()
+	stubResultsMu.Lock()
+	stubResults[t.Name()] = script
+	stubResultsMu.Unlock()
+
+	db, err := sql.Open("adapters-stub", t.Name())
+	if err != nil {
+		t.Fatalf("failed to open stub database: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		stubResultsMu.Lock()
+		delete(stubResults, t.Name())
+		stubResultsMu.Unlock()
+	})
+	return db
+}
+
+// openStubDB returns a database whose queries all return the given columns and rows
+func openStubDB(t *testing.T, columns []string, rows ...[]driver.Value) *sql.DB {
+	t.Helper()
+	return registerStub(t, &stubScript{fallback: &stubResponse{columns: columns, rows: rows, affected: 1}})
+}
+
+// openScriptedDB returns a database answering statements from responses registered with on;
+// unmatched queries return no rows and unmatched statements affect one row
+func openScriptedDB(t *testing.T) (*sql.DB, *stubScript) {
+	t.Helper()
+	script := &stubScript{}
+	return registerStub(t, script), script
+}
+
+// on registers a response for the next statement containing fragment
+func (s *stubScript) on(fragment string, columns []string, rows ...[]driver.Value) *stubResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	response := &stubResponse{fragment: fragment, columns: columns, rows: rows, affected: int64(len(rows))}
+	s.responses = append(s.responses, response)
+	return response
+}
+
+// statements returns the recorded statements with whitespace collapsed
+func (s *stubScript) statements() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statements := make([]string, len(s.calls))
+	for i, call := range s.calls {
+		statements[i] = strings.Join(strings.Fields(call.query), " ")
+	}
+	return statements
+}
+
+// find returns the recorded calls whose statement contains fragment
+func (s *stubScript) find(fragment string) []stubCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var calls []stubCall
+	for _, call := range s.calls {
+		if strings.Contains(call.query, fragment) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func (s *stubScript) answer(query string, args []driver.NamedValue) *stubResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	call := stubCall{query: query}
+	for _, arg := range args {
+		call.args = append(call.args, arg.Value)
+	}
+	s.calls = append(s.calls, call)
+
+	for _, response := range s.responses {
+		if !response.used && strings.Contains(query, response.fragment) {
+			response.used = true
+			return response
+		}
+	}
+	if s.fallback != nil {
+		return s.fallback
+	}
+	return &stubResponse{affected: 1}
+}
+
+func lookupStub(name string) (*stubScript, error) {
+	stubResultsMu.Lock()
+	defer stubResultsMu.Unlock()
+	script, ok := stubResults[name]
+	if !ok {
+		return nil, errors.New("no stub result registered")
+	}
+	return script, nil
+}
+
+func (stubDriver) Open(name string) (driver.Conn, error) {
+	return &stubConn{name: name}, nil
+}
+
+type stubConn struct {
+	name string
+}
+
+func (c *stubConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("stub driver does not prepare statements")
+}
+
+func (c *stubConn) Close() error { return nil }
+
+func (c *stubConn) Begin() (driver.Tx, error) {
+	script, err := lookupStub(c.name)
+	if err != nil {
+		return nil, err
+	}
+	script.answer("BEGIN", nil)
+	return &stubTx{script: script}, nil
+}
+
+func (c *stubConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	script, err := lookupStub(c.name)
+	if err != nil {
+		return nil, err
+	}
+	response := script.answer(query, args)
+	if response.err != nil {
+		return nil, response.err
+	}
+	return &stubRows{result: response}, nil
+}
+
+func (c *stubConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	script, err := lookupStub(c.name)
+	if err != nil {
+		return nil, err
+	}
+	response := script.answer(query, args)
+	if response.err != nil {
+		return nil, response.err
+	}
+	return driver.RowsAffected(response.affected), nil
+}
+
+type stubTx struct {
+	script *stubScript
+}
+
+func (tx *stubTx) Commit() error {
+	tx.script.answer("COMMIT", nil)
+	return nil
+}
+
+func (tx *stubTx) Rollback() error {
+	tx.script.answer("ROLLBACK", nil)
+	return nil
+}
+
+type stubRows struct {
+	result *stubResponse
+	next   int
+}
+
+func (r *stubRows) Columns() []string { return r.result.columns }
+
+func (r *stubRows) Close() error { return nil }
+
+func (r *stubRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.next])
+	r.next++
+	return nil
+}
@@ -0,0 +1,351 @@
+package adapters
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// cacheInvalidation is broadcast to all replicas when a key changes
+type cacheInvalidation struct {
+	Origin  string   `json:"origin"`
+	Keys    []string `json:"keys,omitempty"`
+	Pattern string   `json:"pattern,omitempty"`
+}
+
+// CacheTierStats counts lookups served by one cache tier
+type CacheTierStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions,omitempty"`
+	Size      int    `json:"size,omitempty"`
+}
+
+// CacheStats reports hit/miss statistics for both cache tiers
+type CacheStats struct {
+	Local  CacheTierStats `json:"local"`
+	Remote CacheTierStats `json:"remote"`
+}
+
+// TieredCacheRepository puts a bounded in-process LRU in front of a remote CacheRepository.
+// Writes go to the remote tier and are broadcast over Redis pub/sub so that every replica
+// drops its local copy; the local TTL bounds staleness if an invalidation is missed.
+type TieredCacheRepository struct {
+	remote  interfaces.CacheRepository
+	local   *lruCache
+	client  *redis.Client
+	channel string
+	origin  string
+	logger  *logrus.Logger
+
+	localHits    atomic.Uint64
+	localMisses  atomic.Uint64
+	remoteHits   atomic.Uint64
+	remoteMisses atomic.Uint64
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewTieredCacheRepository wraps remote with a local LRU of at most size entries kept for at
+// most localTTL. When client is nil, invalidations are not broadcast and the local tier is
+// only coherent within this process.
+func NewTieredCacheRepository(remote interfaces.CacheRepository, client *redis.Client, namespace string, size int, localTTL time.Duration, logger *logrus.Logger) *TieredCacheRepository {
+	originBytes := make([]byte, 8)
+	_, _ = rand.Read(originBytes)
+
+	c := &TieredCacheRepository{
+		remote:  remote,
+		local:   newLRUCache(size, localTTL),
+		client:  client,
+		channel: fmt.Sprintf("%s:cache-invalidation", namespace),
+		origin:  hex.EncodeToString(originBytes),
+		logger:  logger,
+		done:    make(chan struct{}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	if client != nil {
+		go c.subscribe(ctx)
+	} else {
+		close(c.done)
+	}
+
+	return c
+}
+
+// subscribe applies invalidations published by other replicas until Close
+func (c *TieredCacheRepository) subscribe(ctx context.Context) {
+	defer close(c.done)
+
+	pubsub := c.client.Subscribe(ctx, c.channel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var invalidation cacheInvalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &invalidation); err != nil {
+				c.logger.WithError(err).Warn("Failed to unmarshal cache invalidation")
+				continue
+			}
+			if invalidation.Origin == c.origin {
+				continue
+			}
+			c.local.delete(invalidation.Keys...)
+			if invalidation.Pattern != "" {
+				c.local.deletePattern(invalidation.Pattern)
+			}
+		}
+	}
+}
+
+// invalidate drops keys locally and tells other replicas to do the same
+func (c *TieredCacheRepository) invalidate(ctx context.Context, invalidation cacheInvalidation) {
+	c.local.delete(invalidation.Keys...)
+	if invalidation.Pattern != "" {
+		c.local.deletePattern(invalidation.Pattern)
+	}
+	if c.client == nil {
+		return
+	}
+
+	invalidation.Origin = c.origin
+	data, err := json.Marshal(invalidation)
+	if err != nil {
+		c.logger.WithError(err).Warn("Failed to marshal cache invalidation")
+		return
+	}
+	if err := c.client.Publish(ctx, c.channel, data).Err(); err != nil {
+		c.logger.WithError(err).Warn("Failed to publish cache invalidation")
+	}
+}
+
+func (c *TieredCacheRepository) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if err := c.remote.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	c.invalidate(ctx, cacheInvalidation{Keys: []string{key}})
+	return nil
+}
+
+func (c *TieredCacheRepository) Get(ctx context.Context, key string) (string, error) {
+	if value, ok := c.local.get(key); ok {
+		c.localHits.Add(1)
+		return value, nil
+	}
+	c.localMisses.Add(1)
+
+	value, err := c.remote.Get(ctx, key)
+	if errors.Is(err, interfaces.ErrCacheMiss) {
+		c.remoteMisses.Add(1)
+		return "", err
+	}
+	if err != nil {
+		return "", err
+	}
+	c.remoteHits.Add(1)
+
+	c.local.set(key, value, 0)
+	return value, nil
+}
+
+func (c *TieredCacheRepository) Delete(ctx context.Context, key string) error {
+	if err := c.remote.Delete(ctx, key); err != nil {
+		return err
+	}
+	c.invalidate(ctx, cacheInvalidation{Keys: []string{key}})
+	return nil
+}
+
+func (c *TieredCacheRepository) Exists(ctx context.Context, key string) (bool, error) {
+	if _, ok := c.local.get(key); ok {
+		return true, nil
+	}
+	return c.remote.Exists(ctx, key)
+}
+
+func (c *TieredCacheRepository) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	if err := c.remote.Expire(ctx, key, ttl); err != nil {
+		return err
+	}
+	c.invalidate(ctx, cacheInvalidation{Keys: []string{key}})
+	return nil
+}
+
+func (c *TieredCacheRepository) Keys(ctx context.Context, pattern string) ([]string, error) {
+	return c.remote.Keys(ctx, pattern)
+}
+
+func (c *TieredCacheRepository) Scan(ctx context.Context, pattern string) interfaces.KeyIterator {
+	return c.remote.Scan(ctx, pattern)
+}
+
+func (c *TieredCacheRepository) DeletePattern(ctx context.Context, pattern string) error {
+	if err := c.remote.DeletePattern(ctx, pattern); err != nil {
+		return err
+	}
+	c.invalidate(ctx, cacheInvalidation{Pattern: pattern})
+	return nil
+}
+
+func (c *TieredCacheRepository) MGet(ctx context.Context, keys []string) (map[string]string, error) {
+	result := make(map[string]string, len(keys))
+	missing := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if value, ok := c.local.get(key); ok {
+			c.localHits.Add(1)
+			result[key] = value
+			continue
+		}
+		c.localMisses.Add(1)
+		missing = append(missing, key)
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	values, err := c.remote.MGet(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range missing {
+		value, ok := values[key]
+		if !ok {
+			c.remoteMisses.Add(1)
+			continue
+		}
+		c.remoteHits.Add(1)
+		c.local.set(key, value, 0)
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+func (c *TieredCacheRepository) MSet(ctx context.Context, values map[string]interface{}, ttl time.Duration) error {
+	if err := c.remote.MSet(ctx, values, ttl); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	c.invalidate(ctx, cacheInvalidation{Keys: keys})
+	return nil
+}
+
+func (c *TieredCacheRepository) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	set, err := c.remote.SetNX(ctx, key, value, ttl)
+	if err != nil {
+		return false, err
+	}
+	if set {
+		c.invalidate(ctx, cacheInvalidation{Keys: []string{key}})
+	}
+	return set, nil
+}
+
+func (c *TieredCacheRepository) GetSet(ctx context.Context, key string, value interface{}) (string, bool, error) {
+	previous, existed, err := c.remote.GetSet(ctx, key, value)
+	if err != nil {
+		return "", false, err
+	}
+	c.invalidate(ctx, cacheInvalidation{Keys: []string{key}})
+	return previous, existed, nil
+}
+
+// Counters and hashes are mutated atomically in Redis and are never held in the local tier;
+// counter writes still invalidate in case a plain Get cached the previous value.
+
+func (c *TieredCacheRepository) Incr(ctx context.Context, key string) (int64, error) {
+	return c.IncrBy(ctx, key, 1)
+}
+
+func (c *TieredCacheRepository) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	value, err := c.remote.IncrBy(ctx, key, delta)
+	if err != nil {
+		return 0, err
+	}
+	c.invalidate(ctx, cacheInvalidation{Keys: []string{key}})
+	return value, nil
+}
+
+func (c *TieredCacheRepository) IncrByFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	value, err := c.remote.IncrByFloat(ctx, key, delta)
+	if err != nil {
+		return 0, err
+	}
+	c.invalidate(ctx, cacheInvalidation{Keys: []string{key}})
+	return value, nil
+}
+
+func (c *TieredCacheRepository) HSet(ctx context.Context, key string, fields map[string]interface{}) error {
+	return c.remote.HSet(ctx, key, fields)
+}
+
+func (c *TieredCacheRepository) HGet(ctx context.Context, key, field string) (string, error) {
+	return c.remote.HGet(ctx, key, field)
+}
+
+func (c *TieredCacheRepository) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return c.remote.HGetAll(ctx, key)
+}
+
+func (c *TieredCacheRepository) HDel(ctx context.Context, key string, fields ...string) error {
+	return c.remote.HDel(ctx, key, fields...)
+}
+
+func (c *TieredCacheRepository) HIncrBy(ctx context.Context, key, field string, delta int64) (int64, error) {
+	return c.remote.HIncrBy(ctx, key, field, delta)
+}
+
+func (c *TieredCacheRepository) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return c.remote.TTL(ctx, key)
+}
+
+func (c *TieredCacheRepository) HealthCheck(ctx context.Context) error {
+	return c.remote.HealthCheck(ctx)
+}
+
+// Stats returns hit/miss counters for the local and remote tiers
+func (c *TieredCacheRepository) Stats() CacheStats {
+	return CacheStats{
+		Local: CacheTierStats{
+			Hits:      c.localHits.Load(),
+			Misses:    c.localMisses.Load(),
+			Evictions: c.local.evicted(),
+			Size:      c.local.len(),
+		},
+		Remote: CacheTierStats{
+			Hits:   c.remoteHits.Load(),
+			Misses: c.remoteMisses.Load(),
+		},
+	}
+}
+
+// Close stops listening for invalidations
+func (c *TieredCacheRepository) Close() {
+	c.once.Do(func() {
+		c.cancel()
+		<-c.done
+	})
+}
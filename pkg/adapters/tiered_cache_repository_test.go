@@ -0,0 +1,166 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// TestLRUCache tests capacity eviction, TTL expiry and pattern deletion
+func TestLRUCache(t *testing.T) {
+	now := time.Now()
+	cache := newLRUCache(2, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.set("a", "1", 0)
+	cache.set("b", "2", 0)
+	cache.get("a")
+	cache.set("c", "3", 0)
+
+	if _, ok := cache.get("b"); ok {
+		t.Error("least recently used key b should have been evicted")
+	}
+	if value, ok := cache.get("a"); !ok || value != "1" {
+		t.Errorf("get(a) = %q, %v", value, ok)
+	}
+	if cache.evicted() != 1 {
+		t.Errorf("evictions = %d, expected 1", cache.evicted())
+	}
+
+	cache.set("short", "x", time.Second)
+	cache.now = func() time.Time { return now.Add(2 * time.Second) }
+	if _, ok := cache.get("short"); ok {
+		t.Error("entry should expire after its TTL")
+	}
+
+	cache.set("balance:acc-1:USD", "10", 0)
+	cache.set("balance:acc-1:BTC", "1", 0)
+	cache.deletePattern("balance:acc-1:*")
+	if cache.len() != 0 {
+		t.Errorf("len after deletePattern = %d, expected 0", cache.len())
+	}
+}
+
+// TestMatchGlob tests that local pattern invalidation follows Redis glob semantics
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		key     string
+		matched bool
+	}{
+		{pattern: "balance:acc-1:*", key: "balance:acc-1:USD", matched: true},
+		{pattern: "balance:*", key: "balance:acc-1/sub:USD", matched: true},
+		{pattern: "order:*:open", key: "order:acc/1:open", matched: true},
+		{pattern: "order:?", key: "order:/", matched: true},
+		{pattern: "order:?", key: "order:", matched: false},
+		{pattern: "*", key: "", matched: true},
+		{pattern: "a**b", key: "axxb", matched: true},
+		{pattern: "balance:[ab]*", key: "balance:acc-1", matched: true},
+		{pattern: "balance:[^ab]*", key: "balance:acc-1", matched: false},
+		{pattern: "balance:[^ab]*", key: "balance:cash", matched: true},
+		{pattern: "level:[0-2]", key: "level:1", matched: true},
+		{pattern: "level:[2-0]", key: "level:1", matched: true},
+		{pattern: "level:[0-2]", key: "level:3", matched: false},
+		{pattern: `literal:\*`, key: "literal:*", matched: true},
+		{pattern: `literal:\*`, key: "literal:x", matched: false},
+		{pattern: `set:[\]]`, key: "set:]", matched: true},
+		{pattern: "open:[ab", key: "open:b", matched: true},
+		{pattern: "open:[ab", key: "open:c", matched: false},
+		{pattern: "account:acc-1", key: "account:acc-12", matched: false},
+	}
+
+	for _, tt := range tests {
+		if matched := matchGlob(tt.pattern, tt.key); matched != tt.matched {
+			t.Errorf("matchGlob(%q, %q) = %v, expected %v", tt.pattern, tt.key, matched, tt.matched)
+		}
+	}
+}
+
+// TestTieredCacheRepository tests local hits, write invalidation and per-tier statistics
+func TestTieredCacheRepository(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx := context.Background()
+
+	remote := &memoryCacheRepository{values: map[string]string{}}
+	cache := NewTieredCacheRepository(remote, nil, "exchange", 100, time.Minute, logger)
+	defer cache.Close()
+
+	if err := cache.Set(ctx, "price:BTC", []byte("65000"), time.Minute); err != nil {
+		t.Fatalf("Set() unexpected error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		value, err := cache.Get(ctx, "price:BTC")
+		if err != nil || value != "65000" {
+			t.Fatalf("Get() = %q, %v", value, err)
+		}
+	}
+
+	if err := cache.Set(ctx, "price:BTC", []byte("66000"), time.Minute); err != nil {
+		t.Fatalf("Set() unexpected error: %v", err)
+	}
+	if value, _ := cache.Get(ctx, "price:BTC"); value != "66000" {
+		t.Errorf("Get() after Set = %q, expected local copy to be invalidated", value)
+	}
+
+	if _, err := cache.Get(ctx, "price:ETH"); !errors.Is(err, interfaces.ErrCacheMiss) {
+		t.Errorf("Get() on missing key error = %v, expected ErrCacheMiss", err)
+	}
+
+	stats := cache.Stats()
+	expected := CacheStats{
+		Local:  CacheTierStats{Hits: 2, Misses: 3, Size: 1},
+		Remote: CacheTierStats{Hits: 2, Misses: 1},
+	}
+	if stats != expected {
+		t.Errorf("Stats() = %+v, expected %+v", stats, expected)
+	}
+}
+
+// TestTieredCacheRepositoryMGet tests that batch reads only fetch locally missing keys remotely
+func TestTieredCacheRepositoryMGet(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx := context.Background()
+
+	remote := &memoryCacheRepository{values: map[string]string{}}
+	cache := NewTieredCacheRepository(remote, nil, "exchange", 100, time.Minute, logger)
+	defer cache.Close()
+
+	if err := cache.MSet(ctx, map[string]interface{}{"a": "1", "b": "2"}, time.Minute); err != nil {
+		t.Fatalf("MSet() unexpected error: %v", err)
+	}
+	if _, err := cache.Get(ctx, "a"); err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+
+	values, err := cache.MGet(ctx, []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("MGet() unexpected error: %v", err)
+	}
+	if len(values) != 2 || values["a"] != "1" || values["b"] != "2" {
+		t.Errorf("MGet() = %v, expected a=1 b=2", values)
+	}
+
+	// a: miss then hit; b and c: miss locally; b hit remotely, c missed remotely
+	stats := cache.Stats()
+	expected := CacheStats{
+		Local:  CacheTierStats{Hits: 1, Misses: 3, Size: 2},
+		Remote: CacheTierStats{Hits: 2, Misses: 1},
+	}
+	if stats != expected {
+		t.Errorf("Stats() = %+v, expected %+v", stats, expected)
+	}
+
+	if err := cache.MSet(ctx, map[string]interface{}{"a": "10"}, time.Minute); err != nil {
+		t.Fatalf("MSet() unexpected error: %v", err)
+	}
+	if value, _ := cache.Get(ctx, "a"); value != "10" {
+		t.Errorf("Get() after MSet = %q, expected local copy to be invalidated", value)
+	}
+}
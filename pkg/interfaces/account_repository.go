@@ -2,10 +2,28 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 
 	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
 )
 
+var (
+	// ErrAccountHasBalances is returned when closing an account that still holds funds
+	ErrAccountHasBalances = errors.New("account has non-zero balances")
+
+	// ErrAccountHasOpenOrders is returned when closing an account with pending or open orders
+	ErrAccountHasOpenOrders = errors.New("account has open orders")
+
+	// ErrAccountHasSubAccounts is returned when closing an account whose sub-accounts are still open
+	ErrAccountHasSubAccounts = errors.New("account has open sub-accounts")
+
+	// ErrAccountDeleted is returned when writing balances or orders of a deleted account
+	ErrAccountDeleted = errors.New("account is deleted")
+
+	// ErrParentAccountDeleted is returned when restoring a sub-account whose parent is deleted
+	ErrParentAccountDeleted = errors.New("parent account is deleted")
+)
+
 // AccountRepository defines the interface for account data operations
 type AccountRepository interface {
 	// Create a new account
@@ -14,6 +32,9 @@ type AccountRepository interface {
 	// GetByID retrieves an account by its ID
 	GetByID(ctx context.Context, accountID string) (*models.Account, error)
 
+	// GetByIDs retrieves accounts keyed by ID and reports the IDs that were not found
+	GetByIDs(ctx context.Context, accountIDs []string) (map[string]*models.Account, []string, error)
+
 	// GetByUserID retrieves accounts for a specific user
 	GetByUserID(ctx context.Context, userID string) ([]*models.Account, error)
 
@@ -26,6 +47,17 @@ type AccountRepository interface {
 	// UpdateStatus updates the status of an account
 	UpdateStatus(ctx context.Context, accountID string, status models.AccountStatus) error
 
-	// Delete deletes an account by ID
+	// Delete closes and soft-deletes an account; it fails with ErrAccountHasBalances,
+	// ErrAccountHasOpenOrders or ErrAccountHasSubAccounts while the account still holds funds,
+	// has open orders or has open sub-accounts. Balance and order writes for a deleted account
+	// fail with ErrAccountDeleted.
 	Delete(ctx context.Context, accountID string) error
+
+	// Restore reverses Delete, leaving the account CLOSED until its status is updated; it fails
+	// with ErrParentAccountDeleted while the account's parent is deleted
+	Restore(ctx context.Context, accountID string) error
+
+	// GetSubAccounts retrieves every open account below an account in its hierarchy,
+	// parents before their sub-accounts
+	GetSubAccounts(ctx context.Context, accountID string) ([]*models.Account, error)
 }
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+)
+
+// AuditRepository reads the audit trail. Entries are written by the database on every audited
+// mutation, attributed with the actor and reason from WithActor and WithReason.
+type AuditRepository interface {
+	// Query retrieves audit entries by entity, actor, action and time range
+	Query(ctx context.Context, query *models.AuditQuery) ([]*models.AuditEntry, error)
+
+	// GetByAccount retrieves the audit trail of an account, oldest first
+	GetByAccount(ctx context.Context, accountID string) ([]*models.AuditEntry, error)
+}
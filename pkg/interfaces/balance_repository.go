@@ -2,11 +2,24 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 
 	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
 	"github.com/shopspring/decimal"
 )
 
+var (
+	// ErrInvalidTransfer is returned for transfers without a positive amount, symbol or
+	// distinct accounts
+	ErrInvalidTransfer = errors.New("invalid transfer")
+
+	// ErrInsufficientFunds is returned when the source account's available balance is too low
+	ErrInsufficientFunds = errors.New("insufficient funds")
+
+	// ErrTransferOutsideHierarchy is returned when the accounts do not share a master account
+	ErrTransferOutsideHierarchy = errors.New("accounts are not in the same hierarchy")
+)
+
 // BalanceRepository defines the interface for balance data operations
 type BalanceRepository interface {
 	// Upsert creates or updates a balance record
@@ -15,9 +28,15 @@ type BalanceRepository interface {
 	// GetByID retrieves a balance by its ID
 	GetByID(ctx context.Context, balanceID string) (*models.Balance, error)
 
+	// GetByIDs retrieves balances keyed by ID and reports the IDs that were not found
+	GetByIDs(ctx context.Context, balanceIDs []string) (map[string]*models.Balance, []string, error)
+
 	// GetByAccountAndSymbol retrieves balance for a specific account and symbol
 	GetByAccountAndSymbol(ctx context.Context, accountID, symbol string) (*models.Balance, error)
 
+	// GetMany retrieves balances for an account keyed by symbol and reports the symbols that were not found
+	GetMany(ctx context.Context, accountID string, symbols []string) (map[string]*models.Balance, []string, error)
+
 	// Query retrieves balances based on query parameters
 	Query(ctx context.Context, query *models.BalanceQuery) ([]*models.Balance, error)
 
@@ -27,6 +46,21 @@ type BalanceRepository interface {
 	// GetByAccount retrieves all balances for a specific account
 	GetByAccount(ctx context.Context, accountID string) ([]*models.Balance, error)
 
-	// AtomicUpdate performs an atomic update on balance (for concurrent operations)
+	// AtomicUpdate performs an atomic update on balance (for concurrent operations). Credits
+	// create a missing balance; debits fail with ErrInsufficientFunds rather than leaving a
+	// negative available or locked balance. Updates to a deleted account fail with ErrAccountDeleted.
 	AtomicUpdate(ctx context.Context, accountID, symbol string, availableDelta, lockedDelta decimal.Decimal) error
+
+	// Transfer atomically moves available funds between two open accounts of the same hierarchy
+	// and records the transfer with a ledger entry per account
+	Transfer(ctx context.Context, transfer *models.Transfer) error
+
+	// GetTransfer retrieves a transfer by its ID
+	GetTransfer(ctx context.Context, transferID string) (*models.Transfer, error)
+
+	// GetLedger retrieves an account's ledger entries for a symbol, newest first
+	GetLedger(ctx context.Context, accountID, symbol string, limit int) ([]*models.LedgerEntry, error)
+
+	// GetHierarchyBalances sums balances per symbol across an account and all its open sub-accounts
+	GetHierarchyBalances(ctx context.Context, accountID string) ([]*models.AggregatedBalance, error)
 }
@@ -2,9 +2,25 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrCacheMiss is returned by Get, HGet and TTL when the key or field does not exist
+var ErrCacheMiss = errors.New("key not found")
+
+// KeyIterator streams keys matching a pattern without blocking Redis
+type KeyIterator interface {
+	// Next advances to the next key, fetching further SCAN pages as needed
+	Next(ctx context.Context) bool
+
+	// Key returns the current key without namespace prefix
+	Key() string
+
+	// Err returns the first error encountered during iteration
+	Err() error
+}
+
 type CacheRepository interface {
 	// Set a value with optional TTL
 	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
@@ -24,9 +40,51 @@ type CacheRepository interface {
 	// Get keys matching pattern
 	Keys(ctx context.Context, pattern string) ([]string, error)
 
+	// Iterate keys matching pattern incrementally
+	Scan(ctx context.Context, pattern string) KeyIterator
+
 	// Delete keys matching pattern
 	DeletePattern(ctx context.Context, pattern string) error
 
+	// Get several values in one round trip; missing keys are omitted from the result
+	MGet(ctx context.Context, keys []string) (map[string]string, error)
+
+	// Set several values with a shared optional TTL in one round trip
+	MSet(ctx context.Context, values map[string]interface{}, ttl time.Duration) error
+
+	// Set a value only if the key does not exist, reporting whether it was set
+	SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+
+	// Set a value and return the previous one; existed is false if there was none
+	GetSet(ctx context.Context, key string, value interface{}) (previous string, existed bool, err error)
+
+	// Atomically increment an integer counter by one
+	Incr(ctx context.Context, key string) (int64, error)
+
+	// Atomically increment an integer counter by delta
+	IncrBy(ctx context.Context, key string, delta int64) (int64, error)
+
+	// Atomically increment a floating point counter by delta
+	IncrByFloat(ctx context.Context, key string, delta float64) (float64, error)
+
+	// Set one or more hash fields
+	HSet(ctx context.Context, key string, fields map[string]interface{}) error
+
+	// Get a hash field
+	HGet(ctx context.Context, key, field string) (string, error)
+
+	// Get all fields of a hash
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+
+	// Delete hash fields
+	HDel(ctx context.Context, key string, fields ...string) error
+
+	// Atomically increment an integer hash field by delta
+	HIncrBy(ctx context.Context, key, field string, delta int64) (int64, error)
+
+	// Get the remaining time to live; negative if the key has no expiry
+	TTL(ctx context.Context, key string) (time.Duration, error)
+
 	// Health check
 	HealthCheck(ctx context.Context) error
 }
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+)
+
+// CandleRepository defines the interface for OHLCV candle operations
+type CandleRepository interface {
+	// GetCandles aggregates trades into candles for the query interval and time range
+	GetCandles(ctx context.Context, query *models.CandleQuery) ([]*models.Candle, error)
+
+	// Materialize computes candles for a symbol and interval over a time range and stores them
+	Materialize(ctx context.Context, symbol string, interval time.Duration, from, to time.Time) error
+
+	// Refresh incrementally updates materialized candles from the last stored bucket onwards
+	Refresh(ctx context.Context, symbol string, interval time.Duration) error
+}
@@ -0,0 +1,46 @@
+package interfaces
+
+import "context"
+
+type contextKey string
+
+const (
+	causationIDKey contextKey = "causation_id"
+	actorKey       contextKey = "actor"
+	reasonKey      contextKey = "reason"
+)
+
+// WithCausationID tags writes made with the returned context with the ID of what caused them,
+// such as a match ID or request ID; the ID is recorded in the order history
+func WithCausationID(ctx context.Context, causationID string) context.Context {
+	return context.WithValue(ctx, causationIDKey, causationID)
+}
+
+// CausationIDFromContext returns the causation ID set by WithCausationID, or ""
+func CausationIDFromContext(ctx context.Context) string {
+	causationID, _ := ctx.Value(causationIDKey).(string)
+	return causationID
+}
+
+// WithActor attributes writes made with the returned context to an actor, such as an operator
+// or service identity; the actor is recorded in the audit trail
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor, or ""
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey).(string)
+	return actor
+}
+
+// WithReason records why writes made with the returned context are being made, for the audit trail
+func WithReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, reasonKey, reason)
+}
+
+// ReasonFromContext returns the reason set by WithReason, or ""
+func ReasonFromContext(ctx context.Context) string {
+	reason, _ := ctx.Value(reasonKey).(string)
+	return reason
+}
@@ -0,0 +1,39 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrLockNotAcquired is returned when a lock is currently held by another owner
+	ErrLockNotAcquired = errors.New("lock not acquired")
+
+	// ErrLockNotHeld is returned when renewing or releasing a lock that expired or was taken over
+	ErrLockNotHeld = errors.New("lock not held")
+)
+
+// Lock is a held distributed lease
+type Lock struct {
+	Name  string
+	Owner string
+
+	// FencingToken increases monotonically with every acquisition of the same lock name.
+	// Writers guarded by the lock should reject tokens lower than the last one they saw.
+	FencingToken int64
+
+	// ExpiresAt is the latest the lease can run out, timed from before the request was sent
+	ExpiresAt time.Time
+}
+
+type LockRepository interface {
+	// Acquire a lock with TTL, returning ErrLockNotAcquired if it is held by another owner
+	Acquire(ctx context.Context, name string, ttl time.Duration) (*Lock, error)
+
+	// Renew extends a held lock's TTL, returning ErrLockNotHeld if it was lost
+	Renew(ctx context.Context, lock *Lock, ttl time.Duration) error
+
+	// Release a held lock, returning ErrLockNotHeld if it was lost
+	Release(ctx context.Context, lock *Lock) error
+}
@@ -0,0 +1,38 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+)
+
+// ErrOrderBookNotFound is returned when no snapshot has been stored for a symbol
+var ErrOrderBookNotFound = errors.New("order book not found")
+
+// ErrOrderBookSequenceGap is returned when a delta does not directly follow the stored sequence
+var ErrOrderBookSequenceGap = errors.New("order book delta out of sequence")
+
+// OrderBookRepository defines the interface for live order book persistence
+type OrderBookRepository interface {
+	// SaveSnapshot atomically replaces the stored book for the snapshot's symbol
+	SaveSnapshot(ctx context.Context, snapshot *models.OrderBookSnapshot) error
+
+	// GetSnapshot retrieves the full book for a symbol
+	GetSnapshot(ctx context.Context, symbol string) (*models.OrderBookSnapshot, error)
+
+	// GetDepth retrieves the best levels on each side of the book
+	GetDepth(ctx context.Context, symbol string, levels int) (*models.OrderBookSnapshot, error)
+
+	// ApplyDelta atomically applies level changes if delta.Sequence is the stored sequence plus one
+	ApplyDelta(ctx context.Context, delta *models.OrderBookDelta) error
+
+	// GetSequence retrieves the stored sequence number for a symbol
+	GetSequence(ctx context.Context, symbol string) (int64, error)
+
+	// Rebuild recomputes the book from open LIMIT orders and stores it with the next sequence
+	Rebuild(ctx context.Context, symbol string) (*models.OrderBookSnapshot, error)
+
+	// Delete removes the stored book for a symbol
+	Delete(ctx context.Context, symbol string) error
+}
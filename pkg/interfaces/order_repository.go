@@ -2,19 +2,31 @@ package interfaces
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
 	"github.com/shopspring/decimal"
 )
 
+// ErrOrderHistoryUnavailable is returned by GetAsOf for instants before an order's history was
+// recorded, which is the case for orders that existed before the order_events migration
+var ErrOrderHistoryUnavailable = errors.New("order history unavailable")
+
 // OrderRepository defines the interface for order data operations
 type OrderRepository interface {
 	// Create creates a new order
 	Create(ctx context.Context, order *models.Order) error
 
+	// CreateBatch bulk inserts orders using COPY
+	CreateBatch(ctx context.Context, orders []*models.Order, opts *models.BatchOptions) (*models.BatchResult, error)
+
 	// GetByID retrieves an order by its ID
 	GetByID(ctx context.Context, orderID string) (*models.Order, error)
 
+	// GetByIDs retrieves orders keyed by ID and reports the IDs that were not found
+	GetByIDs(ctx context.Context, orderIDs []string) (map[string]*models.Order, []string, error)
+
 	// Query retrieves orders based on query parameters
 	Query(ctx context.Context, query *models.OrderQuery) ([]*models.Order, error)
 
@@ -32,4 +44,12 @@ type OrderRepository interface {
 
 	// GetByAccountAndSymbol retrieves orders for a specific account and symbol
 	GetByAccountAndSymbol(ctx context.Context, accountID, symbol string) ([]*models.Order, error)
+
+	// GetHistory retrieves every recorded change to an order, oldest first
+	GetHistory(ctx context.Context, orderID string) ([]*models.OrderEvent, error)
+
+	// GetAsOf reconstructs an order as it was at the given instant from its history. Orders that
+	// predate the history have a BACKFILLED snapshot from their last update onwards, and earlier
+	// instants return ErrOrderHistoryUnavailable.
+	GetAsOf(ctx context.Context, orderID string, at time.Time) (*models.Order, error)
 }
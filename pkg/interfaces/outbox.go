@@ -0,0 +1,44 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+)
+
+// OutboxRepository reads and acknowledges events written by repository mutations
+type OutboxRepository interface {
+	// FetchUnpublished retrieves up to limit unpublished events in event ID order. An event
+	// that has failed maxAttempts times is dead-lettered: it and the later events of its
+	// aggregate are left out until its attempts are reset. maxAttempts <= 0 disables the cap.
+	FetchUnpublished(ctx context.Context, limit, maxAttempts int) ([]*models.OutboxEvent, error)
+
+	// FetchDeadLettered retrieves up to limit unpublished events that have failed maxAttempts
+	// times, in event ID order
+	FetchDeadLettered(ctx context.Context, limit, maxAttempts int) ([]*models.OutboxEvent, error)
+
+	// ResetAttempts returns dead-lettered events to the relay by clearing their attempt counts
+	ResetAttempts(ctx context.Context, eventIDs []int64) error
+
+	// MarkPublished records successful publication of events
+	MarkPublished(ctx context.Context, eventIDs []int64) error
+
+	// MarkFailed records a failed publication attempt
+	MarkFailed(ctx context.Context, eventID int64, reason string) error
+
+	// DeletePublished removes events published before the given time, returning the count
+	DeletePublished(ctx context.Context, before time.Time) (int64, error)
+}
+
+// EventPublisher delivers outbox events to downstream consumers
+type EventPublisher interface {
+	Publish(ctx context.Context, event *models.OutboxEvent) error
+}
+
+// EventPublisherFunc adapts a function to EventPublisher
+type EventPublisherFunc func(ctx context.Context, event *models.OutboxEvent) error
+
+func (f EventPublisherFunc) Publish(ctx context.Context, event *models.OutboxEvent) error {
+	return f(ctx, event)
+}
@@ -5,17 +5,64 @@ import (
 	"time"
 )
 
+// ServiceStatus represents the health and readiness of a service instance
+type ServiceStatus string
+
+const (
+	ServiceStatusStarting ServiceStatus = "STARTING"
+	ServiceStatusHealthy  ServiceStatus = "HEALTHY"
+	ServiceStatusDegraded ServiceStatus = "DEGRADED"
+	ServiceStatusDraining ServiceStatus = "DRAINING"
+)
+
 type ServiceInfo struct {
 	ServiceName string
 	ServiceID   string
 	Address     string
 	Port        int
 	Version     string
+	Status      ServiceStatus
+	Tags        []string
 	Metadata    map[string]string
 	RegisteredAt time.Time
 	LastHeartbeat time.Time
 }
 
+// ServiceFilter narrows discovery results. Empty fields do not filter.
+type ServiceFilter struct {
+	// Statuses accepted; defaults to HEALTHY only when empty
+	Statuses []ServiceStatus
+
+	// VersionConstraint is a comma-separated list of semantic version constraints,
+	// e.g. ">=1.2.0, <2.0.0", "^1.4" or "~1.4.2"
+	VersionConstraint string
+
+	// Metadata entries that must all match exactly
+	Metadata map[string]string
+
+	// Tags that must all be present
+	Tags []string
+}
+
+// ServiceEventType represents a change in a service instance's registration
+type ServiceEventType string
+
+const (
+	ServiceEventRegistered   ServiceEventType = "REGISTERED"
+	ServiceEventDeregistered ServiceEventType = "DEREGISTERED"
+	ServiceEventExpired      ServiceEventType = "EXPIRED"
+)
+
+// ServiceEvent is delivered to watchers when a service instance comes or goes.
+// Info is nil for EXPIRED events since the registration no longer exists.
+type ServiceEvent struct {
+	Type        ServiceEventType `json:"type"`
+	ServiceName string           `json:"service_name"`
+	ServiceID   string           `json:"service_id"`
+	Info        *ServiceInfo     `json:"info,omitempty"`
+	Timestamp   time.Time        `json:"timestamp"`
+}
+
 type ServiceDiscoveryRepository interface {
 	// Register a service instance
 	Register(ctx context.Context, info *ServiceInfo) error
@@ -26,15 +73,31 @@ type ServiceDiscoveryRepository interface {
 	// Update heartbeat for a service
 	Heartbeat(ctx context.Context, serviceID string) error
 
+	// Register a service and heartbeat it at the configured interval until the returned
+	// stop function is called or ctx is cancelled, then deregister it
+	StartHeartbeat(ctx context.Context, info *ServiceInfo) (func(), error)
+
 	// Discover service instances by name
 	Discover(ctx context.Context, serviceName string) ([]*ServiceInfo, error)
 
+	// Watch streams registration changes for a service name until ctx is cancelled
+	Watch(ctx context.Context, serviceName string) (<-chan ServiceEvent, error)
+
+	// DiscoverHealthy discovers service instances by name matching the filter
+	DiscoverHealthy(ctx context.Context, serviceName string, filter *ServiceFilter) ([]*ServiceInfo, error)
+
+	// UpdateStatus changes the status of a registered instance, e.g. DRAINING before deregistering
+	UpdateStatus(ctx context.Context, serviceID string, status ServiceStatus) error
+
 	// Get service info by ID
 	GetServiceInfo(ctx context.Context, serviceID string) (*ServiceInfo, error)
 
 	// List all registered services
 	ListServices(ctx context.Context) ([]*ServiceInfo, error)
 
+	// Reconcile per-name service indexes with registered services, returning the number of entries fixed
+	RepairIndex(ctx context.Context) (int, error)
+
 	// Health check
 	HealthCheck(ctx context.Context) error
 }
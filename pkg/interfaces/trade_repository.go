@@ -11,9 +11,15 @@ type TradeRepository interface {
 	// Create creates a new trade record
 	Create(ctx context.Context, trade *models.Trade) error
 
+	// CreateBatch bulk inserts trades using COPY
+	CreateBatch(ctx context.Context, trades []*models.Trade, opts *models.BatchOptions) (*models.BatchResult, error)
+
 	// GetByID retrieves a trade by its ID
 	GetByID(ctx context.Context, tradeID string) (*models.Trade, error)
 
+	// GetByIDs retrieves trades keyed by ID and reports the IDs that were not found
+	GetByIDs(ctx context.Context, tradeIDs []string) (map[string]*models.Trade, []string, error)
+
 	// GetByOrderID retrieves all trades for a specific order
 	GetByOrderID(ctx context.Context, orderID string) ([]*models.Trade, error)
 
@@ -0,0 +1,11 @@
+package interfaces
+
+import "context"
+
+// Transactor groups repository writes into one atomic unit
+type Transactor interface {
+	// InTransaction calls fn with a context in which writes made through the adapter's
+	// repositories join a single transaction, committed if fn returns nil and rolled back
+	// otherwise. Nested calls join the outer transaction.
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,235 @@
+package matching
+
+import (
+	"sort"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+)
+
+// restingOrder is an order on the book with its unfilled quantity and the balance still
+// locked for it
+type restingOrder struct {
+	order     *models.Order
+	remaining decimal.Decimal
+	reserved  decimal.Decimal
+}
+
+// priceLevel holds resting orders at one price in arrival order
+type priceLevel struct {
+	price  decimal.Decimal
+	orders []*restingOrder
+}
+
+// bookSide keeps price levels sorted best first: highest bid, lowest ask
+type bookSide struct {
+	side   models.OrderSide
+	levels []*priceLevel
+}
+
+// better reports whether price a has priority over price b on this side
+func (s *bookSide) better(a, b decimal.Decimal) bool {
+	if s.side == models.OrderSideBuy {
+		return a.GreaterThan(b)
+	}
+	return a.LessThan(b)
+}
+
+// search returns the index of the first level not better than price
+func (s *bookSide) search(price decimal.Decimal) int {
+	return sort.Search(len(s.levels), func(i int) bool {
+		return !s.better(s.levels[i].price, price)
+	})
+}
+
+func (s *bookSide) add(resting *restingOrder) {
+	price := *resting.order.Price
+	i := s.search(price)
+	if i < len(s.levels) && s.levels[i].price.Equal(price) {
+		s.levels[i].orders = append(s.levels[i].orders, resting)
+		return
+	}
+	level := &priceLevel{price: price, orders: []*restingOrder{resting}}
+	s.levels = append(s.levels, nil)
+	copy(s.levels[i+1:], s.levels[i:])
+	s.levels[i] = level
+}
+
+func (s *bookSide) remove(resting *restingOrder) bool {
+	price := *resting.order.Price
+	i := s.search(price)
+	if i >= len(s.levels) || !s.levels[i].price.Equal(price) {
+		return false
+	}
+	level := s.levels[i]
+	for j, candidate := range level.orders {
+		if candidate == resting {
+			level.orders = append(level.orders[:j], level.orders[j+1:]...)
+			if len(level.orders) == 0 {
+				s.levels = append(s.levels[:i], s.levels[i+1:]...)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// fill is one execution between a resting maker and an incoming taker
+type fill struct {
+	maker    *restingOrder
+	price    decimal.Decimal
+	quantity decimal.Decimal
+}
+
+// book is the price-time priority order book for one symbol
+type book struct {
+	symbol   string
+	bids     *bookSide
+	asks     *bookSide
+	sequence int64
+}
+
+func newBook(symbol string) *book {
+	return &book{
+		symbol: symbol,
+		bids:   &bookSide{side: models.OrderSideBuy},
+		asks:   &bookSide{side: models.OrderSideSell},
+	}
+}
+
+func (b *book) sideOf(side models.OrderSide) *bookSide {
+	if side == models.OrderSideBuy {
+		return b.bids
+	}
+	return b.asks
+}
+
+// opposite returns the side an incoming order matches against
+func (b *book) opposite(side models.OrderSide) *bookSide {
+	if side == models.OrderSideBuy {
+		return b.asks
+	}
+	return b.bids
+}
+
+// crosses reports whether a taker may execute at the given resting price
+func crosses(taker *models.Order, price decimal.Decimal) bool {
+	if taker.OrderType == models.OrderTypeMarket {
+		return true
+	}
+	if taker.Side == models.OrderSideBuy {
+		return price.LessThanOrEqual(*taker.Price)
+	}
+	return price.GreaterThanOrEqual(*taker.Price)
+}
+
+// wouldCross reports whether the taker would execute immediately against the book
+func (b *book) wouldCross(taker *models.Order) bool {
+	levels := b.opposite(taker.Side).levels
+	return len(levels) > 0 && crosses(taker, levels[0].price)
+}
+
+// fillable returns how much of quantity could execute immediately, capped at quantity
+func (b *book) fillable(taker *models.Order, quantity decimal.Decimal) decimal.Decimal {
+	total := decimal.Zero
+	for _, level := range b.opposite(taker.Side).levels {
+		if !crosses(taker, level.price) {
+			break
+		}
+		for _, resting := range level.orders {
+			total = total.Add(resting.remaining)
+			if total.GreaterThanOrEqual(quantity) {
+				return quantity
+			}
+		}
+	}
+	return total
+}
+
+// cost returns the quote notional of executing up to quantity immediately, walking the
+// makers in the order match would
+func (b *book) cost(taker *models.Order, quantity decimal.Decimal) decimal.Decimal {
+	total := decimal.Zero
+	for _, level := range b.opposite(taker.Side).levels {
+		if !crosses(taker, level.price) {
+			break
+		}
+		for _, resting := range level.orders {
+			executed := decimal.Min(quantity, resting.remaining)
+			total = total.Add(executed.Mul(level.price))
+			quantity = quantity.Sub(executed)
+			if !quantity.IsPositive() {
+				return total
+			}
+		}
+	}
+	return total
+}
+
+// match executes the taker against the opposite side at maker prices in price-time
+// priority, removing exhausted makers, and returns the fills in execution order
+func (b *book) match(taker *models.Order, quantity decimal.Decimal) []fill {
+	side := b.opposite(taker.Side)
+	var fills []fill
+	for quantity.IsPositive() && len(side.levels) > 0 {
+		level := side.levels[0]
+		if !crosses(taker, level.price) {
+			break
+		}
+		for quantity.IsPositive() && len(level.orders) > 0 {
+			maker := level.orders[0]
+			executed := decimal.Min(quantity, maker.remaining)
+			maker.remaining = maker.remaining.Sub(executed)
+			quantity = quantity.Sub(executed)
+			fills = append(fills, fill{maker: maker, price: level.price, quantity: executed})
+			if !maker.remaining.IsPositive() {
+				level.orders = level.orders[1:]
+			}
+		}
+		if len(level.orders) == 0 {
+			side.levels = side.levels[1:]
+		}
+	}
+	if len(fills) > 0 {
+		b.sequence++
+	}
+	return fills
+}
+
+func (b *book) rest(resting *restingOrder) {
+	b.sideOf(resting.order.Side).add(resting)
+	b.sequence++
+}
+
+func (b *book) cancel(resting *restingOrder) bool {
+	if !b.sideOf(resting.order.Side).remove(resting) {
+		return false
+	}
+	b.sequence++
+	return true
+}
+
+// snapshot aggregates up to depth levels per side; depth <= 0 returns all levels
+func (b *book) snapshot(depth int) *models.OrderBookSnapshot {
+	aggregate := func(side *bookSide) []models.PriceLevel {
+		levels := side.levels
+		if depth > 0 && len(levels) > depth {
+			levels = levels[:depth]
+		}
+		result := make([]models.PriceLevel, 0, len(levels))
+		for _, level := range levels {
+			aggregated := models.PriceLevel{Price: level.price, OrderCount: len(level.orders)}
+			for _, resting := range level.orders {
+				aggregated.Quantity = aggregated.Quantity.Add(resting.remaining)
+			}
+			result = append(result, aggregated)
+		}
+		return result
+	}
+	return &models.OrderBookSnapshot{
+		Symbol:   b.symbol,
+		Sequence: b.sequence,
+		Bids:     aggregate(b.bids),
+		Asks:     aggregate(b.asks),
+	}
+}
@@ -0,0 +1,644 @@
+package matching
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrInvalidOrder is returned when an order cannot be accepted for matching
+	ErrInvalidOrder = errors.New("invalid order")
+
+	// ErrOrderNotFound is returned when cancelling an order that is not resting on a book
+	ErrOrderNotFound = errors.New("order not resting on book")
+)
+
+// Liquidity values recorded in trade metadata
+const (
+	LiquidityMaker = "MAKER"
+	LiquidityTaker = "TAKER"
+)
+
+// loadPageSize is the number of open orders read per query during Load
+const loadPageSize = 1000
+
+// amountScale is the number of decimal places of the DECIMAL(20,8) balance and fee columns
+const amountScale = 8
+
+// roundAmount rounds a fee or notional to the stored scale
+func roundAmount(amount decimal.Decimal) decimal.Decimal {
+	return amount.Round(amountScale)
+}
+
+// roundReservation rounds a reservation up to the stored scale, so that it always covers
+// the fills it is held for
+func roundReservation(amount decimal.Decimal) decimal.Decimal {
+	return amount.RoundCeil(amountScale)
+}
+
+// Options configures an Engine
+type Options struct {
+	// MakerFeeRate and TakerFeeRate are charged on notional in the quote currency
+	MakerFeeRate decimal.Decimal
+	TakerFeeRate decimal.Decimal
+
+	// NewID generates order, trade and match IDs; defaults to random hex
+	NewID func() (string, error)
+
+	// Now is the engine clock; defaults to time.Now
+	Now func() time.Time
+
+	// Transactor, when set, writes each fill's trades, settlement and order updates atomically
+	Transactor interfaces.Transactor
+}
+
+// Result is the outcome of submitting an order
+type Result struct {
+	// Order is the submitted order in its final state after matching
+	Order *models.Order
+
+	// Trades holds a maker and a taker trade per execution, in execution order
+	Trades []*models.Trade
+
+	// Makers are the resting orders that traded, in their updated state
+	Makers []*models.Order
+
+	// RejectReason explains why the order was rejected or expired without trading
+	RejectReason string
+}
+
+// tradeMetadata links the maker and taker rows of one execution
+type tradeMetadata struct {
+	MatchID        string `json:"match_id"`
+	Liquidity      string `json:"liquidity"`
+	CounterOrderID string `json:"counter_order_id"`
+}
+
+// Engine is an in-process, price-time priority matching engine. Each execution is written as a
+// maker and a taker trade, order fills are written through the OrderRepository and, when a
+// BalanceRepository is configured, funds are reserved on entry and settled per execution.
+//
+// Symbols are BASE-QUOTE (e.g. BTC-USD). The in-memory books are authoritative while the
+// engine runs. With a Transactor configured a failed fill leaves no partial writes behind, so
+// if a repository write fails after matching, Load resynchronises the book from the fills
+// that were committed.
+type Engine struct {
+	orders   interfaces.OrderRepository
+	trades   interfaces.TradeRepository
+	balances interfaces.BalanceRepository
+	options  Options
+	logger   *logrus.Logger
+
+	mu      sync.Mutex
+	books   map[string]*book
+	resting map[string]*restingOrder
+}
+
+// NewEngine creates a matching engine; balances may be nil to skip fund reservation and settlement
+func NewEngine(orders interfaces.OrderRepository, trades interfaces.TradeRepository, balances interfaces.BalanceRepository, options Options, logger *logrus.Logger) *Engine {
+	if options.NewID == nil {
+		options.NewID = newRandomID
+	}
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+	return &Engine{
+		orders:   orders,
+		trades:   trades,
+		balances: balances,
+		options:  options,
+		logger:   logger,
+		books:    make(map[string]*book),
+		resting:  make(map[string]*restingOrder),
+	}
+}
+
+func newRandomID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate ID: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// splitSymbol returns the base and quote currencies of a BASE-QUOTE or BASE/QUOTE symbol
+func splitSymbol(symbol string) (string, string, error) {
+	base, quote, ok := strings.Cut(symbol, "-")
+	if !ok {
+		base, quote, ok = strings.Cut(symbol, "/")
+	}
+	if !ok || base == "" || quote == "" {
+		return "", "", fmt.Errorf("%w: symbol %q is not BASE-QUOTE", ErrInvalidOrder, symbol)
+	}
+	return base, quote, nil
+}
+
+func validateOrder(order *models.Order) error {
+	if order.AccountID == "" {
+		return fmt.Errorf("%w: account ID is required", ErrInvalidOrder)
+	}
+	if _, _, err := splitSymbol(order.Symbol); err != nil {
+		return err
+	}
+	if order.Side != models.OrderSideBuy && order.Side != models.OrderSideSell {
+		return fmt.Errorf("%w: unknown side %q", ErrInvalidOrder, order.Side)
+	}
+	if !order.Quantity.IsPositive() {
+		return fmt.Errorf("%w: quantity must be positive", ErrInvalidOrder)
+	}
+
+	switch order.OrderType {
+	case models.OrderTypeLimit:
+		if order.Price == nil || !order.Price.IsPositive() {
+			return fmt.Errorf("%w: limit order requires a positive price", ErrInvalidOrder)
+		}
+	case models.OrderTypeMarket:
+		if order.TimeInForce == models.TimeInForcePostOnly {
+			return fmt.Errorf("%w: market order cannot be post-only", ErrInvalidOrder)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported order type %q", ErrInvalidOrder, order.OrderType)
+	}
+
+	switch order.TimeInForce {
+	case "", models.TimeInForceGTC, models.TimeInForceIOC, models.TimeInForceFOK, models.TimeInForcePostOnly:
+	default:
+		return fmt.Errorf("%w: unsupported time in force %q", ErrInvalidOrder, order.TimeInForce)
+	}
+
+	return nil
+}
+
+func (e *Engine) bookFor(symbol string) *book {
+	b, ok := e.books[symbol]
+	if !ok {
+		b = newBook(symbol)
+		e.books[symbol] = b
+	}
+	return b
+}
+
+// Submit validates, matches and persists an order. The caller's order is not modified;
+// the returned Result carries the engine's copy with its assigned ID and final status.
+func (e *Engine) Submit(ctx context.Context, order *models.Order) (*Result, error) {
+	if err := validateOrder(order); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.options.Now()
+	taker := *order
+	if taker.OrderID == "" {
+		id, err := e.options.NewID()
+		if err != nil {
+			return nil, err
+		}
+		taker.OrderID = id
+	}
+	if taker.TimeInForce == "" {
+		taker.TimeInForce = models.TimeInForceGTC
+	}
+	taker.FilledQuantity = decimal.Zero
+	taker.AveragePrice = nil
+	taker.Status = models.OrderStatusOpen
+	taker.CreatedAt = now
+	taker.UpdatedAt = now
+	result := &Result{Order: &taker}
+
+	b := e.bookFor(taker.Symbol)
+
+	switch {
+	case taker.TimeInForce == models.TimeInForcePostOnly && b.wouldCross(&taker):
+		return e.refuse(ctx, result, models.OrderStatusRejected, "post-only order would take liquidity")
+	case taker.TimeInForce == models.TimeInForceFOK && b.fillable(&taker, taker.Quantity).LessThan(taker.Quantity):
+		return e.refuse(ctx, result, models.OrderStatusExpired, "fill-or-kill order cannot be filled completely")
+	}
+
+	currency, amount := e.reservation(&taker, taker.Quantity)
+	if taker.OrderType == models.OrderTypeMarket && taker.Side == models.OrderSideBuy {
+		// Market buys have no price bound, so reserve what sweeping the book would cost
+		amount = roundReservation(b.cost(&taker, taker.Quantity).Mul(decimal.NewFromInt(1).Add(e.options.TakerFeeRate)))
+	}
+	reserved, err := e.reserve(ctx, &taker, currency, amount)
+	if err != nil {
+		return nil, err
+	}
+	if !reserved {
+		return e.refuse(ctx, result, models.OrderStatusRejected, "insufficient balance")
+	}
+
+	if err := e.orders.Create(ctx, &taker); err != nil {
+		if releaseErr := e.unlock(ctx, &taker, currency, amount); releaseErr != nil {
+			e.logger.WithError(releaseErr).WithField("order_id", taker.OrderID).Error("Failed to release reservation")
+		}
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+
+	var lastMatchID string
+	held := &restingOrder{order: &taker, reserved: amount}
+	fills := b.match(&taker, taker.Quantity)
+	for _, f := range fills {
+		matchID, err := e.execute(ctx, result, held, f, now)
+		if err != nil {
+			return nil, err
+		}
+		lastMatchID = matchID
+	}
+
+	held.remaining = taker.Quantity.Sub(taker.FilledQuantity)
+	switch {
+	case !held.remaining.IsPositive():
+		taker.Status = models.OrderStatusFilled
+	case taker.OrderType == models.OrderTypeLimit &&
+		(taker.TimeInForce == models.TimeInForceGTC || taker.TimeInForce == models.TimeInForcePostOnly):
+		if taker.FilledQuantity.IsPositive() {
+			taker.Status = models.OrderStatusPartial
+		}
+		b.rest(held)
+		e.resting[taker.OrderID] = held
+	default:
+		taker.Status = models.OrderStatusExpired
+	}
+	if taker.Status != models.OrderStatusOpen && taker.Status != models.OrderStatusPartial {
+		if err := e.release(ctx, held); err != nil {
+			return nil, err
+		}
+	}
+
+	// The taker's fills were written per execution; its final status follows the last match
+	statusCtx := ctx
+	if lastMatchID != "" {
+		statusCtx = interfaces.WithCausationID(ctx, lastMatchID)
+	}
+	if err := e.persistStatus(statusCtx, &taker); err != nil {
+		return nil, err
+	}
+
+	// Hand back a copy so the caller cannot mutate an order that is resting on the book
+	final := taker
+	result.Order = &final
+	return result, nil
+}
+
+// refuse persists an order that will not trade with a terminal status
+func (e *Engine) refuse(ctx context.Context, result *Result, status models.OrderStatus, reason string) (*Result, error) {
+	result.Order.Status = status
+	result.RejectReason = reason
+	if err := e.orders.Create(ctx, result.Order); err != nil {
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+	return result, nil
+}
+
+// execute records one fill: both orders' fill state, the maker and taker trades and settlement,
+// written atomically when a Transactor is configured. It returns the match ID, which is
+// recorded as the cause of both orders' history entries.
+func (e *Engine) execute(ctx context.Context, result *Result, held *restingOrder, f fill, now time.Time) (string, error) {
+	taker := result.Order
+	maker := f.maker.order
+	matchID, err := e.options.NewID()
+	if err != nil {
+		return "", err
+	}
+
+	for _, order := range []*models.Order{maker, taker} {
+		applyFill(order, f.price, f.quantity, now)
+	}
+	if maker.FilledQuantity.GreaterThanOrEqual(maker.Quantity) {
+		maker.Status = models.OrderStatusFilled
+		delete(e.resting, maker.OrderID)
+	} else {
+		maker.Status = models.OrderStatusPartial
+	}
+
+	makerTrade, err := e.newTrade(maker, taker.OrderID, matchID, LiquidityMaker, f, e.options.MakerFeeRate, now)
+	if err != nil {
+		return "", err
+	}
+	takerTrade, err := e.newTrade(taker, maker.OrderID, matchID, LiquidityTaker, f, e.options.TakerFeeRate, now)
+	if err != nil {
+		return "", err
+	}
+
+	err = e.atomically(ctx, func(ctx context.Context) error {
+		for _, trade := range []*models.Trade{makerTrade, takerTrade} {
+			if err := e.trades.Create(ctx, trade); err != nil {
+				return fmt.Errorf("failed to create trade: %w", err)
+			}
+		}
+		if err := e.settle(ctx, f.maker, makerTrade); err != nil {
+			return err
+		}
+		if err := e.settle(ctx, held, takerTrade); err != nil {
+			return err
+		}
+		// Both orders' history records the match that filled them; the taker is still OPEN
+		// here, so only its fill is written
+		matchCtx := interfaces.WithCausationID(ctx, matchID)
+		if err := e.persistFill(matchCtx, maker); err != nil {
+			return err
+		}
+		return e.persistFill(matchCtx, taker)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	makerCopy := *maker
+	result.Makers = append(result.Makers, &makerCopy)
+	result.Trades = append(result.Trades, makerTrade, takerTrade)
+	return matchID, nil
+}
+
+// atomically runs a group of writes in one transaction when a Transactor is configured
+func (e *Engine) atomically(ctx context.Context, writes func(ctx context.Context) error) error {
+	if e.options.Transactor == nil {
+		return writes(ctx)
+	}
+	return e.options.Transactor.InTransaction(ctx, writes)
+}
+
+// applyFill updates filled quantity and volume-weighted average price
+func applyFill(order *models.Order, price, quantity decimal.Decimal, now time.Time) {
+	notional := price.Mul(quantity)
+	if order.AveragePrice != nil {
+		notional = notional.Add(order.AveragePrice.Mul(order.FilledQuantity))
+	}
+	order.FilledQuantity = order.FilledQuantity.Add(quantity)
+	average := notional.Div(order.FilledQuantity)
+	order.AveragePrice = &average
+	order.UpdatedAt = now
+	if order.FilledQuantity.GreaterThanOrEqual(order.Quantity) {
+		filledAt := now
+		order.FilledAt = &filledAt
+	}
+}
+
+func (e *Engine) newTrade(order *models.Order, counterOrderID, matchID, liquidity string, f fill, feeRate decimal.Decimal, now time.Time) (*models.Trade, error) {
+	_, quote, err := splitSymbol(order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := json.Marshal(tradeMetadata{
+		MatchID:        matchID,
+		Liquidity:      liquidity,
+		CounterOrderID: counterOrderID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal trade metadata: %w", err)
+	}
+	tradeID, err := e.options.NewID()
+	if err != nil {
+		return nil, err
+	}
+	return &models.Trade{
+		TradeID:     tradeID,
+		OrderID:     order.OrderID,
+		AccountID:   order.AccountID,
+		Symbol:      order.Symbol,
+		Side:        order.Side,
+		Quantity:    f.quantity,
+		Price:       f.price,
+		Fee:         roundAmount(f.price.Mul(f.quantity).Mul(feeRate)),
+		FeeCurrency: quote,
+		ExecutedAt:  now,
+		Metadata:    metadata,
+	}, nil
+}
+
+// persistFill writes an order's fill progress and status
+func (e *Engine) persistFill(ctx context.Context, order *models.Order) error {
+	if order.FilledQuantity.IsPositive() {
+		if err := e.orders.UpdateFilled(ctx, order.OrderID, order.FilledQuantity, *order.AveragePrice); err != nil {
+			return fmt.Errorf("failed to update order fill: %w", err)
+		}
+	}
+	return e.persistStatus(ctx, order)
+}
+
+// persistStatus writes an order's status once it has left OPEN
+func (e *Engine) persistStatus(ctx context.Context, order *models.Order) error {
+	if order.Status != models.OrderStatusOpen {
+		if err := e.orders.UpdateStatus(ctx, order.OrderID, order.Status); err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+	}
+	return nil
+}
+
+// feeReserveRate is the fee rate reserved on top of a limit buy's notional. A resting buy may
+// pay either rate depending on whether it takes or makes liquidity, so the higher is held.
+func (e *Engine) feeReserveRate() decimal.Decimal {
+	return decimal.Max(e.options.MakerFeeRate, e.options.TakerFeeRate)
+}
+
+// reservation returns the currency and amount locked while quantity of the order is open.
+// Limit buys lock notional at the limit price plus fees; market buys have no price bound and
+// reserve a quote budget computed from the book on entry instead.
+func (e *Engine) reservation(order *models.Order, quantity decimal.Decimal) (string, decimal.Decimal) {
+	base, quote, _ := splitSymbol(order.Symbol)
+	if order.Side == models.OrderSideSell {
+		return base, roundReservation(quantity)
+	}
+	if order.Price == nil {
+		return quote, decimal.Zero
+	}
+	return quote, roundReservation(quantity.Mul(*order.Price).Mul(decimal.NewFromInt(1).Add(e.feeReserveRate())))
+}
+
+// reserve locks amount of currency in a single conditional update, reporting false if
+// available balance is short
+func (e *Engine) reserve(ctx context.Context, order *models.Order, currency string, amount decimal.Decimal) (bool, error) {
+	if e.balances == nil || !amount.IsPositive() {
+		return true, nil
+	}
+	err := e.balances.AtomicUpdate(ctx, order.AccountID, currency, amount.Neg(), amount)
+	if errors.Is(err, interfaces.ErrInsufficientFunds) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve balance: %w", err)
+	}
+	return true, nil
+}
+
+// release returns everything still reserved for an order that has finished to available balance
+func (e *Engine) release(ctx context.Context, held *restingOrder) error {
+	currency, _ := e.reservation(held.order, held.remaining)
+	if err := e.unlock(ctx, held.order, currency, held.reserved); err != nil {
+		return err
+	}
+	held.reserved = decimal.Zero
+	return nil
+}
+
+// unlock returns amount of currency from locked to available balance
+func (e *Engine) unlock(ctx context.Context, order *models.Order, currency string, amount decimal.Decimal) error {
+	if e.balances == nil || !amount.IsPositive() {
+		return nil
+	}
+	if err := e.balances.AtomicUpdate(ctx, order.AccountID, currency, amount, amount.Neg()); err != nil {
+		return fmt.Errorf("failed to release balance: %w", err)
+	}
+	return nil
+}
+
+// settle moves funds for one side of an execution. Buyers receive base and pay quote and fees
+// from their reservation, with any price or fee improvement returned to available; sellers
+// deliver reserved base and receive quote. Fees are deducted in the quote currency. Each fill
+// releases its share of the reservation, and the fill that completes an order releases
+// exactly what remains, so no rounding residue stays locked.
+func (e *Engine) settle(ctx context.Context, held *restingOrder, trade *models.Trade) error {
+	if e.balances == nil {
+		return nil
+	}
+	order := held.order
+	base, quote, _ := splitSymbol(order.Symbol)
+	notional := roundAmount(trade.Price.Mul(trade.Quantity))
+
+	paid, cost, received, proceeds := quote, notional.Add(trade.Fee), base, trade.Quantity
+	if order.Side == models.OrderSideSell {
+		paid, cost, received, proceeds = base, trade.Quantity, quote, notional.Sub(trade.Fee)
+	}
+	share := cost
+	if order.Side == models.OrderSideBuy && order.Price != nil {
+		_, reserved := e.reservation(order, trade.Quantity)
+		share = decimal.Max(cost, reserved)
+	}
+	if order.FilledQuantity.GreaterThanOrEqual(order.Quantity) || share.GreaterThan(held.reserved) {
+		share = held.reserved
+	}
+	held.reserved = held.reserved.Sub(share)
+
+	type delta struct {
+		currency          string
+		available, locked decimal.Decimal
+	}
+	deltas := []delta{
+		{received, proceeds, decimal.Zero},
+		{paid, share.Sub(cost), share.Neg()},
+	}
+	for _, d := range deltas {
+		if err := e.balances.AtomicUpdate(ctx, order.AccountID, d.currency, d.available, d.locked); err != nil {
+			return fmt.Errorf("failed to settle balance: %w", err)
+		}
+	}
+	return nil
+}
+
+// Cancel removes a resting order from its book, releases its reservation and marks it cancelled
+func (e *Engine) Cancel(ctx context.Context, orderID string) (*models.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	resting, ok := e.resting[orderID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, orderID)
+	}
+	order := resting.order
+	e.bookFor(order.Symbol).cancel(resting)
+	delete(e.resting, orderID)
+
+	if err := e.release(ctx, resting); err != nil {
+		return nil, err
+	}
+	if err := e.orders.Cancel(ctx, orderID); err != nil {
+		return nil, fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	now := e.options.Now()
+	order.Status = models.OrderStatusCancelled
+	order.UpdatedAt = now
+	order.CancelledAt = &now
+	cancelled := *order
+	return &cancelled, nil
+}
+
+// Load replaces the in-memory book for a symbol with the open and partially filled LIMIT
+// orders from the OrderRepository, in creation order. The reservation for each order's
+// remaining quantity, including fees at the configured rates, is assumed to be held.
+func (e *Engine) Load(ctx context.Context, symbol string) (int, error) {
+	orderType := models.OrderTypeLimit
+	var open []*models.Order
+	for _, status := range []models.OrderStatus{models.OrderStatusOpen, models.OrderStatusPartial} {
+		for offset := 0; ; offset += loadPageSize {
+			page, err := e.orders.Query(ctx, &models.OrderQuery{
+				Symbol:    &symbol,
+				OrderType: &orderType,
+				Status:    &status,
+				Limit:     loadPageSize,
+				Offset:    offset,
+				SortBy:    "created_at",
+				SortOrder: "ASC",
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to query open orders: %w", err)
+			}
+			open = append(open, page...)
+			if len(page) < loadPageSize {
+				break
+			}
+		}
+	}
+	sort.SliceStable(open, func(i, j int) bool { return open[i].CreatedAt.Before(open[j].CreatedAt) })
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for id, resting := range e.resting {
+		if resting.order.Symbol == symbol {
+			delete(e.resting, id)
+		}
+	}
+	b := newBook(symbol)
+	if previous, ok := e.books[symbol]; ok {
+		b.sequence = previous.sequence
+	}
+	e.books[symbol] = b
+
+	loaded := 0
+	for _, order := range open {
+		remaining := order.Quantity.Sub(order.FilledQuantity)
+		if order.Price == nil || !remaining.IsPositive() {
+			continue
+		}
+		_, reserved := e.reservation(order, remaining)
+		resting := &restingOrder{order: order, remaining: remaining, reserved: reserved}
+		b.rest(resting)
+		e.resting[order.OrderID] = resting
+		loaded++
+	}
+
+	e.logger.WithFields(logrus.Fields{
+		"symbol": symbol,
+		"orders": loaded,
+	}).Info("Matching book loaded from open orders")
+
+	return loaded, nil
+}
+
+// Snapshot returns the price-level aggregated book for a symbol, limited to depth levels per
+// side (all levels if depth <= 0), suitable for OrderBookRepository.SaveSnapshot
+func (e *Engine) Snapshot(symbol string, depth int) *models.OrderBookSnapshot {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	snapshot := e.bookFor(symbol).snapshot(depth)
+	snapshot.UpdatedAt = e.options.Now()
+	return snapshot
+}
@@ -0,0 +1,621 @@
+package matching
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// memoryOrders records orders written by the engine, and the causation ID of each update
+type memoryOrders struct {
+	interfaces.OrderRepository
+	orders map[string]*models.Order
+	causes map[string][]string
+}
+
+func (m *memoryOrders) recordCause(ctx context.Context, orderID string) {
+	if m.causes == nil {
+		m.causes = make(map[string][]string)
+	}
+	m.causes[orderID] = append(m.causes[orderID], interfaces.CausationIDFromContext(ctx))
+}
+
+func (m *memoryOrders) Create(ctx context.Context, order *models.Order) error {
+	copied := *order
+	m.orders[order.OrderID] = &copied
+	return nil
+}
+
+func (m *memoryOrders) UpdateFilled(ctx context.Context, orderID string, filledQuantity, averagePrice decimal.Decimal) error {
+	m.orders[orderID].FilledQuantity = filledQuantity
+	m.orders[orderID].AveragePrice = &averagePrice
+	m.recordCause(ctx, orderID)
+	return nil
+}
+
+func (m *memoryOrders) UpdateStatus(ctx context.Context, orderID string, status models.OrderStatus) error {
+	m.orders[orderID].Status = status
+	m.recordCause(ctx, orderID)
+	return nil
+}
+
+func (m *memoryOrders) Cancel(ctx context.Context, orderID string) error {
+	m.orders[orderID].Status = models.OrderStatusCancelled
+	return nil
+}
+
+// memoryTrades records trades written by the engine, refusing those fail returns an error for
+type memoryTrades struct {
+	interfaces.TradeRepository
+	trades []*models.Trade
+	fail   func(trade *models.Trade) error
+}
+
+func (m *memoryTrades) Create(ctx context.Context, trade *models.Trade) error {
+	if m.fail != nil {
+		if err := m.fail(trade); err != nil {
+			return err
+		}
+	}
+	m.trades = append(m.trades, trade)
+	return nil
+}
+
+// memoryBalances holds available/locked balances keyed by account and currency
+type memoryBalances struct {
+	interfaces.BalanceRepository
+	balances map[string]*models.Balance
+}
+
+// AtomicUpdate mirrors the repository: credits create a missing balance, and debits fail
+// with ErrInsufficientFunds rather than leaving a negative or missing balance. Deltas finer
+// than the column scale, which the database would round silently, are refused.
+func (m *memoryBalances) AtomicUpdate(ctx context.Context, accountID, symbol string, availableDelta, lockedDelta decimal.Decimal) error {
+	key := accountID + "/" + symbol
+	if !availableDelta.Equal(roundAmount(availableDelta)) || !lockedDelta.Equal(roundAmount(lockedDelta)) {
+		return fmt.Errorf("delta %s/%s for %s exceeds the balance scale", availableDelta, lockedDelta, key)
+	}
+	balance, ok := m.balances[key]
+	if !ok {
+		if availableDelta.IsNegative() || lockedDelta.IsNegative() {
+			return fmt.Errorf("%w: %s", interfaces.ErrInsufficientFunds, key)
+		}
+		balance = &models.Balance{AccountID: accountID, Symbol: symbol}
+		m.balances[key] = balance
+	}
+	available := balance.AvailableBalance.Add(availableDelta)
+	locked := balance.LockedBalance.Add(lockedDelta)
+	if available.IsNegative() || locked.IsNegative() {
+		return fmt.Errorf("%w: %s", interfaces.ErrInsufficientFunds, key)
+	}
+	balance.AvailableBalance = available
+	balance.LockedBalance = locked
+	return nil
+}
+
+// memoryTransactor rolls the in-memory repositories back to their state before a failed transaction
+type memoryTransactor struct {
+	engine *testEngine
+}
+
+func (m *memoryTransactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	te := m.engine
+	orders := make(map[string]models.Order, len(te.orders.orders))
+	for id, order := range te.orders.orders {
+		orders[id] = *order
+	}
+	balances := make(map[string]models.Balance, len(te.balances.balances))
+	for key, balance := range te.balances.balances {
+		balances[key] = *balance
+	}
+	trades := len(te.trades.trades)
+
+	err := fn(ctx)
+	if err != nil {
+		te.trades.trades = te.trades.trades[:trades]
+		te.orders.orders = make(map[string]*models.Order, len(orders))
+		for id, order := range orders {
+			te.orders.orders[id] = &order
+		}
+		te.balances.balances = make(map[string]*models.Balance, len(balances))
+		for key, balance := range balances {
+			te.balances.balances[key] = &balance
+		}
+	}
+	return err
+}
+
+type testEngine struct {
+	*Engine
+	orders   *memoryOrders
+	trades   *memoryTrades
+	balances *memoryBalances
+}
+
+// newTestEngine builds an engine with sequential IDs and a clock advancing one millisecond per call
+func newTestEngine(withBalances bool) *testEngine {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	next := 0
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	options := Options{
+		MakerFeeRate: decimal.RequireFromString("0.001"),
+		TakerFeeRate: decimal.RequireFromString("0.002"),
+		NewID: func() (string, error) {
+			next++
+			return fmt.Sprintf("id-%03d", next), nil
+		},
+		Now: func() time.Time {
+			clock = clock.Add(time.Millisecond)
+			return clock
+		},
+	}
+
+	te := &testEngine{
+		orders: &memoryOrders{orders: map[string]*models.Order{}},
+		trades: &memoryTrades{},
+	}
+	var balances interfaces.BalanceRepository
+	if withBalances {
+		te.balances = &memoryBalances{balances: map[string]*models.Balance{}}
+		balances = te.balances
+	}
+	te.Engine = NewEngine(te.orders, te.trades, balances, options, logger)
+	return te
+}
+
+func limitOrder(account string, side models.OrderSide, quantity, price, tif string) *models.Order {
+	p := decimal.RequireFromString(price)
+	return &models.Order{
+		AccountID:   account,
+		Symbol:      "BTC-USD",
+		OrderType:   models.OrderTypeLimit,
+		Side:        side,
+		Quantity:    decimal.RequireFromString(quantity),
+		Price:       &p,
+		TimeInForce: tif,
+	}
+}
+
+func marketOrder(account string, side models.OrderSide, quantity string) *models.Order {
+	return &models.Order{
+		AccountID: account,
+		Symbol:    "BTC-USD",
+		OrderType: models.OrderTypeMarket,
+		Side:      side,
+		Quantity:  decimal.RequireFromString(quantity),
+	}
+}
+
+func mustSubmit(t *testing.T, e *testEngine, order *models.Order) *Result {
+	t.Helper()
+	result, err := e.Submit(context.Background(), order)
+	if err != nil {
+		t.Fatalf("Submit() unexpected error: %v", err)
+	}
+	return result
+}
+
+// TestEnginePriceTimePriority tests that better prices fill first and equal prices fill in arrival order
+func TestEnginePriceTimePriority(t *testing.T) {
+	e := newTestEngine(false)
+
+	first := mustSubmit(t, e, limitOrder("maker-1", models.OrderSideSell, "1", "101", ""))
+	second := mustSubmit(t, e, limitOrder("maker-2", models.OrderSideSell, "1", "101", ""))
+	best := mustSubmit(t, e, limitOrder("maker-3", models.OrderSideSell, "1", "100", ""))
+
+	result := mustSubmit(t, e, limitOrder("taker", models.OrderSideBuy, "2.5", "101", ""))
+
+	var makers []string
+	for _, maker := range result.Makers {
+		makers = append(makers, maker.OrderID)
+	}
+	expected := []string{best.Order.OrderID, first.Order.OrderID, second.Order.OrderID}
+	if !reflect.DeepEqual(makers, expected) {
+		t.Errorf("fill order = %v, expected %v", makers, expected)
+	}
+	if result.Order.Status != models.OrderStatusFilled {
+		t.Errorf("taker status = %s, expected FILLED", result.Order.Status)
+	}
+	if avg := result.Order.AveragePrice; avg == nil || !avg.Equal(decimal.RequireFromString("100.6")) {
+		t.Errorf("taker average price = %v, expected 100.6", avg)
+	}
+	if result.Makers[2].Status != models.OrderStatusPartial {
+		t.Errorf("last maker status = %s, expected PARTIALLY_FILLED", result.Makers[2].Status)
+	}
+
+	snapshot := e.Snapshot("BTC-USD", 0)
+	if len(snapshot.Asks) != 1 || !snapshot.Asks[0].Quantity.Equal(decimal.RequireFromString("0.5")) {
+		t.Errorf("remaining asks = %+v, expected 0.5 @ 101", snapshot.Asks)
+	}
+}
+
+// TestEngineTimeInForce tests IOC, FOK and post-only handling
+func TestEngineTimeInForce(t *testing.T) {
+	tests := []struct {
+		name           string
+		order          *models.Order
+		expectedStatus models.OrderStatus
+		expectedFilled string
+		expectedTrades int
+		expectedBids   int
+	}{
+		{
+			name:           "IOC partial fill expires remainder",
+			order:          limitOrder("taker", models.OrderSideBuy, "3", "100", models.TimeInForceIOC),
+			expectedStatus: models.OrderStatusExpired,
+			expectedFilled: "2",
+			expectedTrades: 4,
+		},
+		{
+			name:           "FOK without enough liquidity expires untouched",
+			order:          limitOrder("taker", models.OrderSideBuy, "3", "100", models.TimeInForceFOK),
+			expectedStatus: models.OrderStatusExpired,
+			expectedFilled: "0",
+		},
+		{
+			name:           "FOK with enough liquidity fills",
+			order:          limitOrder("taker", models.OrderSideBuy, "2", "100", models.TimeInForceFOK),
+			expectedStatus: models.OrderStatusFilled,
+			expectedFilled: "2",
+			expectedTrades: 4,
+		},
+		{
+			name:           "post-only crossing is rejected",
+			order:          limitOrder("taker", models.OrderSideBuy, "1", "100", models.TimeInForcePostOnly),
+			expectedStatus: models.OrderStatusRejected,
+			expectedFilled: "0",
+		},
+		{
+			name:           "post-only passive rests",
+			order:          limitOrder("taker", models.OrderSideBuy, "1", "99", models.TimeInForcePostOnly),
+			expectedStatus: models.OrderStatusOpen,
+			expectedFilled: "0",
+			expectedBids:   1,
+		},
+		{
+			name:           "GTC remainder rests",
+			order:          limitOrder("taker", models.OrderSideBuy, "3", "100", models.TimeInForceGTC),
+			expectedStatus: models.OrderStatusPartial,
+			expectedFilled: "2",
+			expectedTrades: 4,
+			expectedBids:   1,
+		},
+		{
+			name:           "market remainder expires",
+			order:          marketOrder("taker", models.OrderSideBuy, "5"),
+			expectedStatus: models.OrderStatusExpired,
+			expectedFilled: "2",
+			expectedTrades: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEngine(false)
+			mustSubmit(t, e, limitOrder("maker-1", models.OrderSideSell, "1", "100", ""))
+			mustSubmit(t, e, limitOrder("maker-2", models.OrderSideSell, "1", "100", ""))
+
+			result := mustSubmit(t, e, tt.order)
+
+			if result.Order.Status != tt.expectedStatus {
+				t.Errorf("status = %s, expected %s (%s)", result.Order.Status, tt.expectedStatus, result.RejectReason)
+			}
+			if !result.Order.FilledQuantity.Equal(decimal.RequireFromString(tt.expectedFilled)) {
+				t.Errorf("filled = %s, expected %s", result.Order.FilledQuantity, tt.expectedFilled)
+			}
+			if len(result.Trades) != tt.expectedTrades {
+				t.Errorf("trades = %d, expected %d", len(result.Trades), tt.expectedTrades)
+			}
+			if bids := e.Snapshot("BTC-USD", 0).Bids; len(bids) != tt.expectedBids {
+				t.Errorf("resting bids = %d, expected %d", len(bids), tt.expectedBids)
+			}
+			if stored := e.orders.orders[result.Order.OrderID]; stored == nil || stored.Status != tt.expectedStatus {
+				t.Errorf("persisted order = %+v, expected status %s", stored, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+// TestEngineSettlement tests reservation, settlement with fees and release on cancel
+func TestEngineSettlement(t *testing.T) {
+	e := newTestEngine(true)
+	ctx := context.Background()
+	_ = e.balances.AtomicUpdate(ctx, "seller", "BTC", decimal.NewFromInt(2), decimal.Zero)
+	_ = e.balances.AtomicUpdate(ctx, "buyer", "USD", decimal.NewFromInt(1000), decimal.Zero)
+
+	if result := mustSubmit(t, e, limitOrder("buyer", models.OrderSideBuy, "20", "100", "")); result.Order.Status != models.OrderStatusRejected {
+		t.Fatalf("oversized buy status = %s, expected REJECTED", result.Order.Status)
+	}
+
+	mustSubmit(t, e, limitOrder("seller", models.OrderSideSell, "2", "100", ""))
+	bid := mustSubmit(t, e, limitOrder("buyer", models.OrderSideBuy, "3", "105", ""))
+	if bid.Order.Status != models.OrderStatusPartial {
+		t.Fatalf("buy status = %s, expected PARTIALLY_FILLED", bid.Order.Status)
+	}
+	if _, err := e.Cancel(ctx, bid.Order.OrderID); err != nil {
+		t.Fatalf("Cancel() unexpected error: %v", err)
+	}
+	if _, err := e.Cancel(ctx, bid.Order.OrderID); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("second Cancel() error = %v, expected ErrOrderNotFound", err)
+	}
+
+	// Maker seller pays 0.1% of 200, taker buyer pays 0.2% of 200 and gets 5/unit price improvement
+	expected := map[string][2]string{
+		"seller/BTC": {"0", "0"},
+		"seller/USD": {"199.8", "0"},
+		"buyer/BTC":  {"2", "0"},
+		"buyer/USD":  {"799.6", "0"},
+	}
+	for key, amounts := range expected {
+		balance := e.balances.balances[key]
+		if balance == nil {
+			t.Errorf("%s balance missing", key)
+			continue
+		}
+		if !balance.AvailableBalance.Equal(decimal.RequireFromString(amounts[0])) || !balance.LockedBalance.Equal(decimal.RequireFromString(amounts[1])) {
+			t.Errorf("%s = available %s locked %s, expected %s/%s", key, balance.AvailableBalance, balance.LockedBalance, amounts[0], amounts[1])
+		}
+	}
+}
+
+// TestEngineReservation tests that buys reserve fees, market buys reserve a budget swept from the
+// book and finished orders release their whole reservation at the stored scale
+func TestEngineReservation(t *testing.T) {
+	tests := []struct {
+		name              string
+		asks              [][2]string
+		funds             string
+		order             *models.Order
+		expectedStatus    models.OrderStatus
+		expectedAvailable string
+	}{
+		{
+			name:              "limit buy short of fees",
+			asks:              [][2]string{{"2", "100"}},
+			funds:             "200",
+			order:             limitOrder("buyer", models.OrderSideBuy, "2", "100", ""),
+			expectedStatus:    models.OrderStatusRejected,
+			expectedAvailable: "200",
+		},
+		{
+			name:              "limit buy covering fees",
+			asks:              [][2]string{{"2", "100"}},
+			funds:             "200.4",
+			order:             limitOrder("buyer", models.OrderSideBuy, "2", "100", ""),
+			expectedStatus:    models.OrderStatusFilled,
+			expectedAvailable: "0",
+		},
+		{
+			name:              "fractional limit buy across makers",
+			asks:              [][2]string{{"0.1111", "0.03"}, {"0.1111", "0.03"}, {"0.1111", "0.03"}},
+			funds:             "1",
+			order:             limitOrder("buyer", models.OrderSideBuy, "0.3333", "0.03", ""),
+			expectedStatus:    models.OrderStatusFilled,
+			expectedAvailable: "0.98998099",
+		},
+		{
+			name:              "fractional limit buy expiring",
+			asks:              [][2]string{{"0.1111", "0.03"}},
+			funds:             "1",
+			order:             limitOrder("buyer", models.OrderSideBuy, "0.3333", "0.03", models.TimeInForceIOC),
+			expectedStatus:    models.OrderStatusExpired,
+			expectedAvailable: "0.99666033",
+		},
+		{
+			name:              "market buy within budget",
+			asks:              [][2]string{{"1", "100"}, {"1", "110"}},
+			funds:             "300",
+			order:             marketOrder("buyer", models.OrderSideBuy, "2"),
+			expectedStatus:    models.OrderStatusFilled,
+			expectedAvailable: "89.58",
+		},
+		{
+			name:              "market buy over budget",
+			asks:              [][2]string{{"1", "100"}, {"1", "110"}},
+			funds:             "210",
+			order:             marketOrder("buyer", models.OrderSideBuy, "2"),
+			expectedStatus:    models.OrderStatusRejected,
+			expectedAvailable: "210",
+		},
+		{
+			name:              "market buy beyond book depth",
+			asks:              [][2]string{{"1", "100"}},
+			funds:             "300",
+			order:             marketOrder("buyer", models.OrderSideBuy, "2"),
+			expectedStatus:    models.OrderStatusExpired,
+			expectedAvailable: "199.8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEngine(true)
+			ctx := context.Background()
+			for _, ask := range tt.asks {
+				_ = e.balances.AtomicUpdate(ctx, "seller", "BTC", decimal.RequireFromString(ask[0]), decimal.Zero)
+				mustSubmit(t, e, limitOrder("seller", models.OrderSideSell, ask[0], ask[1], ""))
+			}
+			_ = e.balances.AtomicUpdate(ctx, "buyer", "USD", decimal.RequireFromString(tt.funds), decimal.Zero)
+
+			result := mustSubmit(t, e, tt.order)
+			if result.Order.Status != tt.expectedStatus {
+				t.Errorf("status = %s, expected %s", result.Order.Status, tt.expectedStatus)
+			}
+			balance := e.balances.balances["buyer/USD"]
+			if !balance.AvailableBalance.Equal(decimal.RequireFromString(tt.expectedAvailable)) || !balance.LockedBalance.IsZero() {
+				t.Errorf("buyer/USD = available %s locked %s, expected %s/0", balance.AvailableBalance, balance.LockedBalance, tt.expectedAvailable)
+			}
+		})
+	}
+}
+
+// TestEngineCausation tests that maker and taker history entries carry the match that caused them
+func TestEngineCausation(t *testing.T) {
+	e := newTestEngine(false)
+	mustSubmit(t, e, limitOrder("a", models.OrderSideSell, "1", "100", ""))
+	mustSubmit(t, e, limitOrder("b", models.OrderSideSell, "1", "101", ""))
+	result := mustSubmit(t, e, limitOrder("c", models.OrderSideBuy, "2", "101", ""))
+
+	var matchIDs []string
+	for _, trade := range result.Trades {
+		if trade.OrderID != result.Order.OrderID {
+			continue
+		}
+		var metadata tradeMetadata
+		if err := json.Unmarshal(trade.Metadata, &metadata); err != nil {
+			t.Fatalf("failed to decode trade metadata: %v", err)
+		}
+		matchIDs = append(matchIDs, metadata.MatchID)
+	}
+	if len(matchIDs) != 2 {
+		t.Fatalf("taker trades = %d, expected 2", len(matchIDs))
+	}
+
+	// One fill per match, then the FILLED status caused by the last match
+	expected := []string{matchIDs[0], matchIDs[1], matchIDs[1]}
+	if got := e.orders.causes[result.Order.OrderID]; !reflect.DeepEqual(got, expected) {
+		t.Errorf("taker causes = %v, expected %v", got, expected)
+	}
+	for i, maker := range result.Makers {
+		for _, cause := range e.orders.causes[maker.OrderID] {
+			if cause != matchIDs[i] {
+				t.Errorf("maker %s cause = %q, expected %q", maker.OrderID, cause, matchIDs[i])
+			}
+		}
+	}
+}
+
+// TestEngineAtomicFill tests that a failed fill leaves none of its writes behind, while earlier
+// fills of the same order stay committed
+func TestEngineAtomicFill(t *testing.T) {
+	e := newTestEngine(true)
+	e.options.Transactor = &memoryTransactor{engine: e}
+	ctx := context.Background()
+	_ = e.balances.AtomicUpdate(ctx, "a", "BTC", decimal.NewFromInt(1), decimal.Zero)
+	_ = e.balances.AtomicUpdate(ctx, "b", "BTC", decimal.NewFromInt(1), decimal.Zero)
+	_ = e.balances.AtomicUpdate(ctx, "c", "USD", decimal.NewFromInt(1000), decimal.Zero)
+
+	mustSubmit(t, e, limitOrder("a", models.OrderSideSell, "1", "100", ""))
+	second := mustSubmit(t, e, limitOrder("b", models.OrderSideSell, "1", "101", ""))
+
+	failure := errors.New("trade write failed")
+	e.trades.fail = func(trade *models.Trade) error {
+		if trade.Price.Equal(decimal.NewFromInt(101)) && trade.AccountID == "c" {
+			return failure
+		}
+		return nil
+	}
+	if _, err := e.Submit(ctx, limitOrder("c", models.OrderSideBuy, "2", "101", "")); !errors.Is(err, failure) {
+		t.Fatalf("Submit() error = %v, expected %v", err, failure)
+	}
+
+	if len(e.trades.trades) != 2 {
+		t.Errorf("trades = %d, expected the 2 of the first fill", len(e.trades.trades))
+	}
+	if status := e.orders.orders[second.Order.OrderID].Status; status != models.OrderStatusOpen {
+		t.Errorf("second maker status = %s, expected OPEN", status)
+	}
+	expected := map[string][2]string{
+		"a/BTC": {"0", "0"},
+		"b/BTC": {"0", "1"},
+		"c/BTC": {"1", "0"},
+	}
+	for key, amounts := range expected {
+		balance := e.balances.balances[key]
+		if balance == nil {
+			t.Errorf("%s balance missing", key)
+			continue
+		}
+		if !balance.AvailableBalance.Equal(decimal.RequireFromString(amounts[0])) || !balance.LockedBalance.Equal(decimal.RequireFromString(amounts[1])) {
+			t.Errorf("%s = available %s locked %s, expected %s/%s", key, balance.AvailableBalance, balance.LockedBalance, amounts[0], amounts[1])
+		}
+	}
+}
+
+// replayScript is a fixed order flow exercising resting, partial fills, sweeps and cancels
+func replayScript() []*models.Order {
+	return []*models.Order{
+		limitOrder("a", models.OrderSideSell, "1.5", "101", ""),
+		limitOrder("b", models.OrderSideSell, "2", "102", ""),
+		limitOrder("c", models.OrderSideBuy, "1", "99", ""),
+		limitOrder("d", models.OrderSideBuy, "0.5", "100", models.TimeInForcePostOnly),
+		limitOrder("e", models.OrderSideBuy, "2", "101.5", ""),
+		marketOrder("f", models.OrderSideSell, "1"),
+		limitOrder("g", models.OrderSideSell, "1", "98", models.TimeInForceIOC),
+		limitOrder("h", models.OrderSideBuy, "3", "102", models.TimeInForceFOK),
+		limitOrder("i", models.OrderSideBuy, "1", "102", models.TimeInForceFOK),
+	}
+}
+
+// replay runs the script through a fresh engine and returns the trades and final book as JSON
+func replay(t *testing.T) ([]byte, *models.OrderBookSnapshot) {
+	e := newTestEngine(false)
+	for _, order := range replayScript() {
+		mustSubmit(t, e, order)
+	}
+	data, err := json.Marshal(e.trades.trades)
+	if err != nil {
+		t.Fatalf("failed to marshal trades: %v", err)
+	}
+	return data, e.Snapshot("BTC-USD", 0)
+}
+
+// TestEngineDeterministicReplay tests that replaying the same order flow yields identical results
+func TestEngineDeterministicReplay(t *testing.T) {
+	firstTrades, firstBook := replay(t)
+	secondTrades, secondBook := replay(t)
+
+	if string(firstTrades) != string(secondTrades) {
+		t.Fatalf("replayed trades differ:\n%s\n%s", firstTrades, secondTrades)
+	}
+	if !reflect.DeepEqual(firstBook, secondBook) {
+		t.Fatalf("replayed books differ:\n%+v\n%+v", firstBook, secondBook)
+	}
+
+	var trades []*models.Trade
+	if err := json.Unmarshal(firstTrades, &trades); err != nil {
+		t.Fatalf("failed to unmarshal trades: %v", err)
+	}
+
+	// e lifts a@101 (1.5) and rests 0.5@101.5; f sells into e (0.5@101.5) then d (0.5@100);
+	// g sells 1@98 into c@99; h cannot fill 3 and expires; i lifts 1 of b@102
+	type execution struct {
+		account  string
+		side     models.OrderSide
+		quantity string
+		price    string
+	}
+	expected := []execution{
+		{"a", models.OrderSideSell, "1.5", "101"}, {"e", models.OrderSideBuy, "1.5", "101"},
+		{"e", models.OrderSideBuy, "0.5", "101.5"}, {"f", models.OrderSideSell, "0.5", "101.5"},
+		{"d", models.OrderSideBuy, "0.5", "100"}, {"f", models.OrderSideSell, "0.5", "100"},
+		{"c", models.OrderSideBuy, "1", "99"}, {"g", models.OrderSideSell, "1", "99"},
+		{"b", models.OrderSideSell, "1", "102"}, {"i", models.OrderSideBuy, "1", "102"},
+	}
+	if len(trades) != len(expected) {
+		t.Fatalf("trades = %d, expected %d", len(trades), len(expected))
+	}
+	for i, trade := range trades {
+		want := expected[i]
+		if trade.AccountID != want.account || trade.Side != want.side ||
+			!trade.Quantity.Equal(decimal.RequireFromString(want.quantity)) || !trade.Price.Equal(decimal.RequireFromString(want.price)) {
+			t.Errorf("trade %d = %s %s %s@%s, expected %+v", i, trade.AccountID, trade.Side, trade.Quantity, trade.Price, want)
+		}
+	}
+
+	if len(firstBook.Bids) != 0 || len(firstBook.Asks) != 1 || !firstBook.Asks[0].Quantity.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("final book = %+v, expected only 1 @ 102 ask", firstBook)
+	}
+}
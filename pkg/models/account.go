@@ -41,16 +41,21 @@ type Account struct {
 	KYCStatus   KYCStatus     `json:"kyc_status" db:"kyc_status"`
 	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time     `json:"updated_at" db:"updated_at"`
+	DeletedAt   *time.Time    `json:"deleted_at,omitempty" db:"deleted_at"` // Set when the account is closed
+	ParentAccountID *string   `json:"parent_account_id,omitempty" db:"parent_account_id"` // Master account of a sub-account
 	Metadata    json.RawMessage `json:"metadata,omitempty" db:"metadata"`
 }
 
 // AccountQuery defines query parameters for account lookups
 type AccountQuery struct {
 	UserID       *string
+	ParentAccountID *string // Direct sub-accounts of this account
 	AccountType  *AccountType
 	Status       *AccountStatus
 	KYCStatus    *KYCStatus
 	CreatedAfter *time.Time
+	Metadata     *MetadataFilter
+	IncludeDeleted bool // Include closed (soft-deleted) accounts
 	Limit        int
 	Offset       int
 	SortBy       string
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditAction is the kind of mutation an audit entry records
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "CREATE"
+	AuditActionUpdate AuditAction = "UPDATE"
+	AuditActionDelete AuditAction = "DELETE"
+)
+
+// Audited entity types
+const (
+	AuditEntityAccount = "account"
+)
+
+// AuditEntry is an immutable record of one mutation. For updates Before and After hold only
+// the changed fields; creates have no Before and deletes no After.
+type AuditEntry struct {
+	AuditID    int64           `json:"audit_id" db:"audit_id"`
+	EntityType string          `json:"entity_type" db:"entity_type"`
+	EntityID   string          `json:"entity_id" db:"entity_id"`
+	Action     AuditAction     `json:"action" db:"action"`
+	Actor      string          `json:"actor" db:"actor"`
+	Reason     *string         `json:"reason,omitempty" db:"reason"`
+	Before     json.RawMessage `json:"before,omitempty" db:"before"`
+	After      json.RawMessage `json:"after,omitempty" db:"after"`
+	OccurredAt time.Time       `json:"occurred_at" db:"occurred_at"`
+}
+
+// AuditQuery defines query parameters for audit trail lookups
+type AuditQuery struct {
+	EntityType *string
+	EntityID   *string
+	Actor      *string
+	Action     *AuditAction
+	From       *time.Time // Inclusive
+	To         *time.Time // Exclusive
+	Limit      int
+	Offset     int
+	SortOrder  string // ASC (default) or DESC by occurrence
+}
@@ -25,6 +25,7 @@ type BalanceQuery struct {
 	Symbol       *string
 	MinBalance   *decimal.Decimal
 	UpdatedAfter *time.Time
+	Metadata     *MetadataFilter
 	Limit        int
 	Offset       int
 	SortBy       string
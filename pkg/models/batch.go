@@ -0,0 +1,36 @@
+package models
+
+// DefaultBatchChunkSize is the number of rows sent per COPY when no chunk size is given
+const DefaultBatchChunkSize = 5000
+
+// BatchOptions controls how bulk inserts are chunked and committed
+type BatchOptions struct {
+	// ChunkSize is the number of rows per COPY statement
+	ChunkSize int
+
+	// Transactional commits all chunks atomically; otherwise each chunk commits independently
+	Transactional bool
+}
+
+// BatchChunkError reports a failed chunk by its position in the input slice
+type BatchChunkError struct {
+	Chunk int   `json:"chunk"`
+	Start int   `json:"start"`
+	End   int   `json:"end"`
+	Err   error `json:"-"`
+}
+
+func (e *BatchChunkError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *BatchChunkError) Unwrap() error {
+	return e.Err
+}
+
+// BatchResult summarizes a bulk insert
+type BatchResult struct {
+	Inserted int                `json:"inserted"`
+	Failed   int                `json:"failed"`
+	Errors   []*BatchChunkError `json:"errors,omitempty"`
+}
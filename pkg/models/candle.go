@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Supported candle interval bounds
+const (
+	MinCandleInterval = time.Second
+	MaxCandleInterval = 24 * time.Hour
+)
+
+// Candle represents an OHLCV bar aggregated from executed trades
+type Candle struct {
+	Symbol     string          `json:"symbol" db:"symbol"`
+	Interval   time.Duration   `json:"interval" db:"interval_seconds"`
+	OpenTime   time.Time       `json:"open_time" db:"open_time"`
+	CloseTime  time.Time       `json:"close_time" db:"close_time"`
+	Open       decimal.Decimal `json:"open" db:"open"`
+	High       decimal.Decimal `json:"high" db:"high"`
+	Low        decimal.Decimal `json:"low" db:"low"`
+	Close      decimal.Decimal `json:"close" db:"close"`
+	Volume     decimal.Decimal `json:"volume" db:"volume"`
+	TradeCount int64           `json:"trade_count" db:"trade_count"`
+}
+
+// CandleQuery defines query parameters for candle lookups
+type CandleQuery struct {
+	Symbol   string
+	Interval time.Duration
+	From     time.Time
+	To       time.Time
+	Limit    int
+
+	// Materialized reads from the candles table instead of aggregating trades
+	Materialized bool
+}
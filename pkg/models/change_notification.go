@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// ChangeTable identifies a table published on the LISTEN/NOTIFY change feed
+type ChangeTable string
+
+const (
+	ChangeTableOrders   ChangeTable = "orders"
+	ChangeTableTrades   ChangeTable = "trades"
+	ChangeTableBalances ChangeTable = "balances"
+)
+
+// ChangeOperation is the kind of change that produced a notification
+type ChangeOperation string
+
+const (
+	ChangeOperationInsert ChangeOperation = "INSERT"
+	ChangeOperationUpdate ChangeOperation = "UPDATE"
+
+	// ChangeOperationCatchUp marks rows found by the catch-up query after a reconnect,
+	// where the original operation is unknown
+	ChangeOperationCatchUp ChangeOperation = "CATCH_UP"
+)
+
+// ChangeNotification is a row change pushed by the change feed triggers. It carries keys and
+// status only; consumers read the row through the repositories if they need more.
+type ChangeNotification struct {
+	Table          ChangeTable     `json:"table"`
+	Operation      ChangeOperation `json:"operation"`
+	ID             string          `json:"id"`
+	AccountID      string          `json:"account_id,omitempty"`
+	Symbol         string          `json:"symbol,omitempty"`
+	OrderID        string          `json:"order_id,omitempty"`
+	Status         string          `json:"status,omitempty"`
+	PreviousStatus string          `json:"previous_status,omitempty"`
+	ChangedAt      time.Time       `json:"changed_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "encoding/json"
+
+// MetadataFilter defines containment predicates on the JSONB metadata column.
+// All populated predicates must match.
+type MetadataFilter struct {
+	// Equals matches rows whose metadata has each key set to the given value
+	Equals map[string]interface{}
+
+	// HasKeys matches rows whose metadata contains every listed top-level key
+	HasKeys []string
+
+	// Contains matches rows whose metadata contains the given JSON document
+	Contains json.RawMessage
+}
+
+// IsEmpty reports whether the filter has no predicates
+func (f *MetadataFilter) IsEmpty() bool {
+	return f == nil || (len(f.Equals) == 0 && len(f.HasKeys) == 0 && len(f.Contains) == 0)
+}
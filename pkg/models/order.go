@@ -37,6 +37,14 @@ const (
 	OrderStatusExpired   OrderStatus = "EXPIRED"
 )
 
+// Time in force values for Order.TimeInForce
+const (
+	TimeInForceGTC      = "GTC"       // Good till cancelled
+	TimeInForceIOC      = "IOC"       // Immediate or cancel: unfilled remainder expires
+	TimeInForceFOK      = "FOK"       // Fill or kill: fills completely or not at all
+	TimeInForcePostOnly = "POST_ONLY" // Rests as maker only; rejected if it would cross
+)
+
 // Order represents a trading order
 type Order struct {
 	OrderID        string          `json:"order_id" db:"order_id"`
@@ -49,7 +57,7 @@ type Order struct {
 	FilledQuantity decimal.Decimal `json:"filled_quantity" db:"filled_quantity"`
 	AveragePrice   *decimal.Decimal `json:"average_price,omitempty" db:"average_price"`
 	Status         OrderStatus     `json:"status" db:"status"`
-	TimeInForce    string          `json:"time_in_force" db:"time_in_force"` // GTC, IOC, FOK
+	TimeInForce    string          `json:"time_in_force" db:"time_in_force"` // GTC, IOC, FOK, POST_ONLY
 	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
 	FilledAt       *time.Time      `json:"filled_at,omitempty" db:"filled_at"`
@@ -66,6 +74,7 @@ type OrderQuery struct {
 	Status       *OrderStatus
 	CreatedAfter *time.Time
 	CreatedBefore *time.Time
+	Metadata     *MetadataFilter
 	Limit        int
 	Offset       int
 	SortBy       string
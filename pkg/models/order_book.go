@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PriceLevel aggregates the resting quantity of all orders at one price
+type PriceLevel struct {
+	Price      decimal.Decimal `json:"price"`
+	Quantity   decimal.Decimal `json:"quantity"`
+	OrderCount int             `json:"order_count"`
+}
+
+// OrderBookSnapshot is the price-level aggregated book for a symbol.
+// Bids are ordered best (highest) first and asks best (lowest) first.
+type OrderBookSnapshot struct {
+	Symbol    string       `json:"symbol"`
+	Sequence  int64        `json:"sequence"`
+	Bids      []PriceLevel `json:"bids"`
+	Asks      []PriceLevel `json:"asks"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// PriceLevelChange replaces the aggregate at one price; a zero quantity removes the level
+type PriceLevelChange struct {
+	Side       OrderSide       `json:"side"`
+	Price      decimal.Decimal `json:"price"`
+	Quantity   decimal.Decimal `json:"quantity"`
+	OrderCount int             `json:"order_count"`
+}
+
+// OrderBookDelta is an incremental update that must directly follow the stored sequence
+type OrderBookDelta struct {
+	Symbol    string             `json:"symbol"`
+	Sequence  int64              `json:"sequence"`
+	Changes   []PriceLevelChange `json:"changes"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
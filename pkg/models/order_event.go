@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// OrderEventType classifies an entry in an order's history
+type OrderEventType string
+
+const (
+	OrderEventCreated       OrderEventType = "CREATED"
+	OrderEventBackfilled    OrderEventType = "BACKFILLED"
+	OrderEventStatusChanged OrderEventType = "STATUS_CHANGED"
+	OrderEventFilled        OrderEventType = "FILLED"
+	OrderEventCancelled     OrderEventType = "CANCELLED"
+	OrderEventUpdated       OrderEventType = "UPDATED"
+)
+
+// OrderEvent is one recorded change to an order. Changes holds the full order for CREATED
+// and BACKFILLED, and only the changed fields, in the Order JSON encoding, for later events.
+// BACKFILLED starts the history of an order created before history was recorded.
+type OrderEvent struct {
+	EventID     int64           `json:"event_id" db:"event_id"`
+	OrderID     string          `json:"order_id" db:"order_id"`
+	EventType   OrderEventType  `json:"event_type" db:"event_type"`
+	Changes     json.RawMessage `json:"changes" db:"changes"`
+	CausationID *string         `json:"causation_id,omitempty" db:"causation_id"`
+	OccurredAt  time.Time       `json:"occurred_at" db:"occurred_at"`
+}
+
+// ReplayOrderEvents reconstructs an order by applying its events in order. The first event
+// must be the CREATED or BACKFILLED event.
+func ReplayOrderEvents(events []*OrderEvent) (*Order, error) {
+	if len(events) == 0 {
+		return nil, fmt.Errorf("no order events to replay")
+	}
+	if events[0].EventType != OrderEventCreated && events[0].EventType != OrderEventBackfilled {
+		return nil, fmt.Errorf("order history for %s does not start with %s", events[0].OrderID, OrderEventCreated)
+	}
+
+	order := &Order{}
+	for _, event := range events {
+		if err := json.Unmarshal(event.Changes, order); err != nil {
+			return nil, fmt.Errorf("failed to apply order event %d: %w", event.EventID, err)
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EventType identifies the kind of domain event stored in the outbox
+type EventType string
+
+const (
+	EventTypeOrderCreated         EventType = "OrderCreated"
+	EventTypeOrderStatusChanged   EventType = "OrderStatusChanged"
+	EventTypeTradeExecuted        EventType = "TradeExecuted"
+	EventTypeBalanceChanged       EventType = "BalanceChanged"
+	EventTypeAccountStatusChanged EventType = "AccountStatusChanged"
+)
+
+// Aggregate types that events are ordered by
+const (
+	AggregateTypeOrder   = "order"
+	AggregateTypeTrade   = "trade"
+	AggregateTypeBalance = "balance"
+	AggregateTypeAccount = "account"
+)
+
+// DomainEvent is a typed event emitted by a repository mutation
+type DomainEvent interface {
+	EventType() EventType
+	AggregateType() string
+	AggregateID() string
+}
+
+// OrderCreated is emitted when an order is inserted
+type OrderCreated struct {
+	Order Order `json:"order"`
+}
+
+func (e *OrderCreated) EventType() EventType  { return EventTypeOrderCreated }
+func (e *OrderCreated) AggregateType() string { return AggregateTypeOrder }
+func (e *OrderCreated) AggregateID() string   { return e.Order.OrderID }
+
+// OrderStatusChanged is emitted when an order's status is updated or it is cancelled
+type OrderStatusChanged struct {
+	OrderID   string      `json:"order_id"`
+	Status    OrderStatus `json:"status"`
+	ChangedAt time.Time   `json:"changed_at"`
+}
+
+func (e *OrderStatusChanged) EventType() EventType  { return EventTypeOrderStatusChanged }
+func (e *OrderStatusChanged) AggregateType() string { return AggregateTypeOrder }
+func (e *OrderStatusChanged) AggregateID() string   { return e.OrderID }
+
+// TradeExecuted is emitted when a trade is inserted. Trades are their own aggregate: inserting a
+// trade does not lock its order row, so its event cannot be ordered with the order's events.
+type TradeExecuted struct {
+	Trade Trade `json:"trade"`
+}
+
+func (e *TradeExecuted) EventType() EventType  { return EventTypeTradeExecuted }
+func (e *TradeExecuted) AggregateType() string { return AggregateTypeTrade }
+func (e *TradeExecuted) AggregateID() string   { return e.Trade.TradeID }
+
+// BalanceChanged carries the balance after a mutation; deltas are zero for absolute updates
+type BalanceChanged struct {
+	BalanceID        string          `json:"balance_id"`
+	AccountID        string          `json:"account_id"`
+	Symbol           string          `json:"symbol"`
+	AvailableBalance decimal.Decimal `json:"available_balance"`
+	LockedBalance    decimal.Decimal `json:"locked_balance"`
+	TotalBalance     decimal.Decimal `json:"total_balance"`
+	AvailableDelta   decimal.Decimal `json:"available_delta"`
+	LockedDelta      decimal.Decimal `json:"locked_delta"`
+	ChangedAt        time.Time       `json:"changed_at"`
+}
+
+func (e *BalanceChanged) EventType() EventType  { return EventTypeBalanceChanged }
+func (e *BalanceChanged) AggregateType() string { return AggregateTypeBalance }
+func (e *BalanceChanged) AggregateID() string   { return e.AccountID + ":" + e.Symbol }
+
+// AccountStatusChanged is emitted when an account's status changes
+type AccountStatusChanged struct {
+	AccountID      string        `json:"account_id"`
+	PreviousStatus AccountStatus `json:"previous_status,omitempty"`
+	Status         AccountStatus `json:"status"`
+	ChangedAt      time.Time     `json:"changed_at"`
+}
+
+func (e *AccountStatusChanged) EventType() EventType  { return EventTypeAccountStatusChanged }
+func (e *AccountStatusChanged) AggregateType() string { return AggregateTypeAccount }
+func (e *AccountStatusChanged) AggregateID() string   { return e.AccountID }
+
+// OutboxEvent is a domain event row awaiting or after publication
+type OutboxEvent struct {
+	EventID       int64           `json:"event_id" db:"event_id"`
+	AggregateType string          `json:"aggregate_type" db:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id" db:"aggregate_id"`
+	EventType     EventType       `json:"event_type" db:"event_type"`
+	Payload       json.RawMessage `json:"payload" db:"payload"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	PublishedAt   *time.Time      `json:"published_at,omitempty" db:"published_at"`
+	Attempts      int             `json:"attempts" db:"attempts"`
+	LastError     *string         `json:"last_error,omitempty" db:"last_error"`
+}
+
+// NewOutboxEvent serializes a domain event into an outbox row
+func NewOutboxEvent(event DomainEvent) (*OutboxEvent, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s event: %w", event.EventType(), err)
+	}
+	return &OutboxEvent{
+		AggregateType: event.AggregateType(),
+		AggregateID:   event.AggregateID(),
+		EventType:     event.EventType(),
+		Payload:       payload,
+	}, nil
+}
+
+// Decode deserializes the payload into its typed domain event
+func (e *OutboxEvent) Decode() (DomainEvent, error) {
+	var event DomainEvent
+	switch e.EventType {
+	case EventTypeOrderCreated:
+		event = &OrderCreated{}
+	case EventTypeOrderStatusChanged:
+		event = &OrderStatusChanged{}
+	case EventTypeTradeExecuted:
+		event = &TradeExecuted{}
+	case EventTypeBalanceChanged:
+		event = &BalanceChanged{}
+	case EventTypeAccountStatusChanged:
+		event = &AccountStatusChanged{}
+	default:
+		return nil, fmt.Errorf("unknown event type %q", e.EventType)
+	}
+	if err := json.Unmarshal(e.Payload, event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s event: %w", e.EventType, err)
+	}
+	return event, nil
+}
@@ -30,6 +30,7 @@ type TradeQuery struct {
 	Side          *OrderSide
 	ExecutedAfter *time.Time
 	ExecutedBefore *time.Time
+	Metadata      *MetadataFilter
 	Limit         int
 	Offset        int
 	SortBy        string
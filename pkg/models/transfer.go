@@ -0,0 +1,40 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Transfer moves available funds between two accounts of the same hierarchy
+type Transfer struct {
+	TransferID    string          `json:"transfer_id" db:"transfer_id"` // Caller-supplied; retries with the same ID are rejected
+	FromAccountID string          `json:"from_account_id" db:"from_account_id"`
+	ToAccountID   string          `json:"to_account_id" db:"to_account_id"`
+	Symbol        string          `json:"symbol" db:"symbol"`
+	Amount        decimal.Decimal `json:"amount" db:"amount"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	Metadata      json.RawMessage `json:"metadata,omitempty" db:"metadata"`
+}
+
+// LedgerEntry is one side of a transfer. Amount is signed: negative for the debited account,
+// positive for the credited one.
+type LedgerEntry struct {
+	EntryID      int64           `json:"entry_id" db:"entry_id"`
+	TransferID   string          `json:"transfer_id" db:"transfer_id"`
+	AccountID    string          `json:"account_id" db:"account_id"`
+	Symbol       string          `json:"symbol" db:"symbol"`
+	Amount       decimal.Decimal `json:"amount" db:"amount"`
+	BalanceAfter decimal.Decimal `json:"balance_after" db:"balance_after"` // Available balance after the entry
+	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AggregatedBalance sums one symbol's balances across an account and its sub-accounts
+type AggregatedBalance struct {
+	Symbol           string          `json:"symbol"`
+	AvailableBalance decimal.Decimal `json:"available_balance"`
+	LockedBalance    decimal.Decimal `json:"locked_balance"`
+	TotalBalance     decimal.Decimal `json:"total_balance"`
+	AccountCount     int             `json:"account_count"`
+}
@@ -0,0 +1,196 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrCostExceedsLimit is returned when a single request costs more than the limit can ever admit
+var ErrCostExceedsLimit = errors.New("request cost exceeds rate limit")
+
+// Algorithm selects how requests are counted
+type Algorithm string
+
+const (
+	// AlgorithmSlidingWindow admits at most Limit requests in any rolling Window
+	AlgorithmSlidingWindow Algorithm = "SLIDING_WINDOW"
+
+	// AlgorithmTokenBucket refills Limit tokens per Window up to Burst, allowing short bursts
+	AlgorithmTokenBucket Algorithm = "TOKEN_BUCKET"
+)
+
+// Options configures a Limiter
+type Options struct {
+	// Algorithm defaults to sliding window
+	Algorithm Algorithm
+
+	// Limit is the number of requests admitted per Window
+	Limit int
+
+	// Window is the period over which Limit applies
+	Window time.Duration
+
+	// Burst is the token bucket capacity; defaults to Limit and is ignored by sliding window
+	Burst int
+}
+
+// Result describes the outcome of a rate limit check
+type Result struct {
+	Allowed bool `json:"allowed"`
+
+	// Remaining is how many more unit-cost requests would currently be admitted
+	Remaining int `json:"remaining"`
+
+	// RetryAfter is how long to wait before the same request would be admitted; zero if allowed
+	RetryAfter time.Duration `json:"retry_after"`
+}
+
+// Limiter enforces a rate limit shared by all replicas through Redis.
+// Keys are scoped as {namespace}:ratelimit:{name}:{key}.
+type Limiter struct {
+	client    *redis.Client
+	namespace string
+	name      string
+	options   Options
+	logger    *logrus.Logger
+}
+
+func NewLimiter(client *redis.Client, namespace, name string, options Options, logger *logrus.Logger) (*Limiter, error) {
+	if options.Algorithm == "" {
+		options.Algorithm = AlgorithmSlidingWindow
+	}
+	if options.Algorithm != AlgorithmSlidingWindow && options.Algorithm != AlgorithmTokenBucket {
+		return nil, fmt.Errorf("unknown rate limit algorithm %q", options.Algorithm)
+	}
+	if options.Limit <= 0 {
+		return nil, fmt.Errorf("rate limit must be positive, got %d", options.Limit)
+	}
+	if options.Window < time.Millisecond {
+		return nil, fmt.Errorf("rate limit window must be at least 1ms, got %s", options.Window)
+	}
+	if options.Burst <= 0 {
+		options.Burst = options.Limit
+	}
+
+	return &Limiter{
+		client:    client,
+		namespace: namespace,
+		name:      name,
+		options:   options,
+		logger:    logger,
+	}, nil
+}
+
+// AccountKey scopes a limit to a trading account
+func AccountKey(accountID string) string {
+	return "account:" + accountID
+}
+
+// APIKey scopes a limit to an API key
+func APIKey(apiKey string) string {
+	return "api-key:" + apiKey
+}
+
+func (l *Limiter) redisKey(key string) string {
+	return fmt.Sprintf("%s:ratelimit:%s:%s", l.namespace, l.name, key)
+}
+
+// capacity is the largest cost a single request may have
+func (l *Limiter) capacity() int {
+	if l.options.Algorithm == AlgorithmTokenBucket {
+		return l.options.Burst
+	}
+	return l.options.Limit
+}
+
+// Allow checks and consumes one unit of quota for key
+func (l *Limiter) Allow(ctx context.Context, key string) (*Result, error) {
+	return l.AllowN(ctx, key, 1)
+}
+
+// AllowN checks and, if admitted, consumes cost units of quota for key atomically
+func (l *Limiter) AllowN(ctx context.Context, key string, cost int) (*Result, error) {
+	if cost <= 0 {
+		return nil, fmt.Errorf("rate limit cost must be positive, got %d", cost)
+	}
+	if cost > l.capacity() {
+		return nil, fmt.Errorf("%w: cost %d, capacity %d", ErrCostExceedsLimit, cost, l.capacity())
+	}
+
+	var (
+		reply []interface{}
+		err   error
+	)
+	switch l.options.Algorithm {
+	case AlgorithmTokenBucket:
+		rate := float64(l.options.Limit) / float64(l.options.Window.Microseconds())
+		reply, err = tokenBucketScript.Run(ctx, l.client, []string{l.redisKey(key)},
+			l.options.Burst, strconv.FormatFloat(rate, 'g', -1, 64), cost).Slice()
+	default:
+		member, idErr := newRequestID()
+		if idErr != nil {
+			return nil, fmt.Errorf("failed to generate request id: %w", idErr)
+		}
+		reply, err = slidingWindowScript.Run(ctx, l.client, []string{l.redisKey(key)},
+			l.options.Window.Microseconds(), l.options.Limit, cost, member).Slice()
+	}
+	if err != nil {
+		l.logger.WithError(err).WithFields(logrus.Fields{
+			"limiter": l.name,
+			"key":     key,
+		}).Error("Failed to evaluate rate limit")
+		return nil, fmt.Errorf("failed to evaluate rate limit: %w", err)
+	}
+
+	return parseResult(reply)
+}
+
+// Reset clears the recorded usage for key
+func (l *Limiter) Reset(ctx context.Context, key string) error {
+	if err := l.client.Del(ctx, l.redisKey(key)).Err(); err != nil {
+		l.logger.WithError(err).WithField("limiter", l.name).Error("Failed to reset rate limit")
+		return fmt.Errorf("failed to reset rate limit: %w", err)
+	}
+	return nil
+}
+
+// parseResult decodes the {allowed, remaining, retry_after_us} script reply
+func parseResult(reply []interface{}) (*Result, error) {
+	if len(reply) != 3 {
+		return nil, fmt.Errorf("unexpected rate limit reply length %d", len(reply))
+	}
+	values := make([]int64, len(reply))
+	for i, v := range reply {
+		n, ok := v.(int64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected rate limit reply element %T", v)
+		}
+		values[i] = n
+	}
+
+	remaining := int(values[1])
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &Result{
+		Allowed:    values[0] == 1,
+		Remaining:  remaining,
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+	}, nil
+}
+
+func newRequestID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
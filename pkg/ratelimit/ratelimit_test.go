@@ -0,0 +1,196 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+func testLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// TestNewLimiter tests option validation and defaults
+func TestNewLimiter(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   Options
+		expectErr bool
+	}{
+		{name: "sliding window", options: Options{Limit: 10, Window: time.Second}},
+		{name: "token bucket", options: Options{Algorithm: AlgorithmTokenBucket, Limit: 10, Window: time.Second, Burst: 20}},
+		{name: "unknown algorithm", options: Options{Algorithm: "LEAKY", Limit: 10, Window: time.Second}, expectErr: true},
+		{name: "zero limit", options: Options{Window: time.Second}, expectErr: true},
+		{name: "sub-millisecond window", options: Options{Limit: 10, Window: time.Microsecond}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, err := NewLimiter(nil, "exchange", "orders", tt.options, testLogger())
+			if tt.expectErr {
+				if err == nil {
+					t.Error("NewLimiter() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewLimiter() unexpected error: %v", err)
+			}
+			if limiter.options.Algorithm == "" || limiter.options.Burst == 0 {
+				t.Errorf("NewLimiter() did not apply defaults: %+v", limiter.options)
+			}
+		})
+	}
+}
+
+// TestLimiterKeys tests namespacing and capacity checks that do not reach Redis
+func TestLimiterKeys(t *testing.T) {
+	limiter, err := NewLimiter(nil, "exchange_okx", "orders", Options{Limit: 5, Window: time.Second}, testLogger())
+	if err != nil {
+		t.Fatalf("NewLimiter() unexpected error: %v", err)
+	}
+
+	if key := limiter.redisKey(AccountKey("acc-1")); key != "exchange_okx:ratelimit:orders:account:acc-1" {
+		t.Errorf("redisKey() = %q", key)
+	}
+
+	if _, err := limiter.AllowN(context.Background(), AccountKey("acc-1"), 6); !errors.Is(err, ErrCostExceedsLimit) {
+		t.Errorf("AllowN() over capacity error = %v, expected ErrCostExceedsLimit", err)
+	}
+}
+
+// TestParseResult tests decoding of script replies
+func TestParseResult(t *testing.T) {
+	result, err := parseResult([]interface{}{int64(0), int64(-1), int64(250000)})
+	if err != nil {
+		t.Fatalf("parseResult() unexpected error: %v", err)
+	}
+	expected := Result{Allowed: false, Remaining: 0, RetryAfter: 250 * time.Millisecond}
+	if *result != expected {
+		t.Errorf("parseResult() = %+v, expected %+v", *result, expected)
+	}
+
+	if _, err := parseResult([]interface{}{int64(1)}); err == nil {
+		t.Error("parseResult() expected error for short reply")
+	}
+}
+
+// openTestRedis connects to TEST_REDIS_URL and returns a namespace unique to the test, whose
+// keys are deleted afterwards. The test is skipped under -short or when Redis is unreachable.
+func openTestRedis(t *testing.T) (*redis.Client, string) {
+	t.Helper()
+	url := os.Getenv("TEST_REDIS_URL")
+	if testing.Short() || url == "" {
+		t.Skip("set TEST_REDIS_URL and drop -short to run Redis integration tests")
+	}
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatalf("failed to parse TEST_REDIS_URL: %v", err)
+	}
+	client := redis.NewClient(options)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		t.Skipf("Redis at TEST_REDIS_URL unreachable: %v", err)
+	}
+
+	id, err := newRequestID()
+	if err != nil {
+		t.Fatalf("failed to generate namespace: %v", err)
+	}
+	namespace := "test:" + id
+	t.Cleanup(func() {
+		ctx := context.Background()
+		if keys, err := client.Keys(ctx, namespace+":*").Result(); err == nil && len(keys) > 0 {
+			client.Del(ctx, keys...)
+		}
+		client.Close()
+	})
+	return client, namespace
+}
+
+// TestLimiterScripts tests admission, denial, retry-after and refill of both scripts against Redis
+func TestLimiterScripts(t *testing.T) {
+	type step struct {
+		wait              time.Duration
+		cost              int
+		expectedAllowed   bool
+		expectedRemaining int
+		maxRetryAfter     time.Duration
+	}
+	tests := []struct {
+		name    string
+		options Options
+		steps   []step
+	}{
+		{
+			name:    "sliding window",
+			options: Options{Limit: 3, Window: 300 * time.Millisecond},
+			steps: []step{
+				{cost: 1, expectedAllowed: true, expectedRemaining: 2},
+				{cost: 2, expectedAllowed: true, expectedRemaining: 0},
+				{cost: 1, expectedAllowed: false, expectedRemaining: 0, maxRetryAfter: 300 * time.Millisecond},
+				// The window slides past the first requests and admits again
+				{wait: 350 * time.Millisecond, cost: 3, expectedAllowed: true, expectedRemaining: 0},
+				{cost: 2, expectedAllowed: false, expectedRemaining: 0, maxRetryAfter: 300 * time.Millisecond},
+			},
+		},
+		{
+			name:    "token bucket",
+			options: Options{Algorithm: AlgorithmTokenBucket, Limit: 5, Window: 500 * time.Millisecond, Burst: 4},
+			steps: []step{
+				{cost: 4, expectedAllowed: true, expectedRemaining: 0},
+				// One token refills every 100ms
+				{cost: 2, expectedAllowed: false, expectedRemaining: 0, maxRetryAfter: 200 * time.Millisecond},
+				{wait: 220 * time.Millisecond, cost: 2, expectedAllowed: true, expectedRemaining: 0},
+				// Refill stops at the burst capacity
+				{wait: time.Second, cost: 1, expectedAllowed: true, expectedRemaining: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, namespace := openTestRedis(t)
+			limiter, err := NewLimiter(client, namespace, "orders", tt.options, testLogger())
+			if err != nil {
+				t.Fatalf("NewLimiter() unexpected error: %v", err)
+			}
+			ctx := context.Background()
+			key := AccountKey("acc-1")
+
+			for i, s := range tt.steps {
+				time.Sleep(s.wait)
+				result, err := limiter.AllowN(ctx, key, s.cost)
+				if err != nil {
+					t.Fatalf("step %d AllowN() unexpected error: %v", i, err)
+				}
+				if result.Allowed != s.expectedAllowed || result.Remaining != s.expectedRemaining {
+					t.Errorf("step %d = %+v, expected allowed %v with %d remaining", i, *result, s.expectedAllowed, s.expectedRemaining)
+				}
+				if s.expectedAllowed && result.RetryAfter != 0 {
+					t.Errorf("step %d RetryAfter = %v for an admitted request", i, result.RetryAfter)
+				}
+				if !s.expectedAllowed && (result.RetryAfter <= 0 || result.RetryAfter > s.maxRetryAfter) {
+					t.Errorf("step %d RetryAfter = %v, expected within (0, %v]", i, result.RetryAfter, s.maxRetryAfter)
+				}
+			}
+
+			if err := limiter.Reset(ctx, key); err != nil {
+				t.Fatalf("Reset() unexpected error: %v", err)
+			}
+			if result, err := limiter.Allow(ctx, key); err != nil || !result.Allowed {
+				t.Errorf("Allow() after Reset() = %+v, %v; expected admitted", result, err)
+			}
+		})
+	}
+}
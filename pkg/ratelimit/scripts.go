@@ -0,0 +1,64 @@
+package ratelimit
+
+import "github.com/redis/go-redis/v9"
+
+// Both scripts read the clock with TIME so that every replica sees the same time source,
+// and return {allowed, remaining, retry_after_us}.
+
+// slidingWindowScript keeps a sorted set of admitted requests scored by microsecond timestamp.
+// KEYS[1] window key; ARGV[1] window in microseconds, ARGV[2] limit, ARGV[3] cost, ARGV[4] member prefix.
+var slidingWindowScript = redis.NewScript(`
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+local clock = redis.call("TIME")
+local now = tonumber(clock[1]) * 1000000 + tonumber(clock[2])
+
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local count = redis.call("ZCARD", KEYS[1])
+
+if count + cost <= limit then
+	for i = 1, cost do
+		redis.call("ZADD", KEYS[1], now, ARGV[4] .. ":" .. i)
+	end
+	redis.call("PEXPIRE", KEYS[1], math.ceil(window / 1000))
+	return {1, limit - count - cost, 0}
+end
+
+-- The request fits once the (count + cost - limit) oldest entries have left the window
+local index = count + cost - limit - 1
+local oldest = redis.call("ZRANGE", KEYS[1], index, index, "WITHSCORES")
+local retry = tonumber(oldest[2]) + window - now
+if retry < 1 then
+	retry = 1
+end
+return {0, limit - count, retry}
+`)
+
+// tokenBucketScript stores the token count and last refill time in a hash.
+// KEYS[1] bucket key; ARGV[1] capacity, ARGV[2] refill rate in tokens per microsecond, ARGV[3] cost.
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+local clock = redis.call("TIME")
+local now = tonumber(clock[1]) * 1000000 + tonumber(clock[2])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= cost then
+	tokens = tokens - cost
+	allowed = 1
+else
+	retry = math.ceil((cost - tokens) / rate)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(now))
+redis.call("PEXPIRE", KEYS[1], math.ceil(capacity / rate / 1000) + 1000)
+return {allowed, math.floor(tokens), retry}
+`)
@@ -0,0 +1,252 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrNoInstances is returned when no usable instance is available for a service
+var ErrNoInstances = errors.New("no service instances available")
+
+// Strategy selects one instance among the available ones
+type Strategy string
+
+const (
+	StrategyRoundRobin        Strategy = "ROUND_ROBIN"
+	StrategyRandom            Strategy = "RANDOM"
+	StrategyLeastRecentlyUsed Strategy = "LEAST_RECENTLY_USED"
+	StrategyConsistentHash    Strategy = "CONSISTENT_HASH"
+)
+
+const (
+	defaultRefreshInterval = 10 * time.Second
+	defaultStaleAfter      = 90 * time.Second
+)
+
+// Options configures a Resolver
+type Options struct {
+	// Strategy used by Pick; defaults to round-robin
+	Strategy Strategy
+
+	// RefreshInterval is how long discovery results are cached
+	RefreshInterval time.Duration
+
+	// StaleAfter excludes instances whose LastHeartbeat is older than this
+	StaleAfter time.Duration
+
+	// Filter applied to discovery; nil selects HEALTHY instances
+	Filter *interfaces.ServiceFilter
+}
+
+// Resolver caches discovery results for one service name and picks instances client-side.
+// Discovery runs outside the lock, one fetch at a time shared by every caller waiting on it,
+// so picks from the cache are never held up behind a slow refresh.
+type Resolver struct {
+	repo        interfaces.ServiceDiscoveryRepository
+	serviceName string
+	options     Options
+	logger      *logrus.Logger
+
+	mu         sync.Mutex
+	instances  []*interfaces.ServiceInfo
+	fetchedAt  time.Time
+	refreshing *refreshCall
+	next       uint64
+	lastUsed   map[string]time.Time
+	now        func() time.Time
+}
+
+// refreshCall is a discovery fetch in flight; err is set before done is closed
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+func NewResolver(repo interfaces.ServiceDiscoveryRepository, serviceName string, options Options, logger *logrus.Logger) *Resolver {
+	if options.Strategy == "" {
+		options.Strategy = StrategyRoundRobin
+	}
+	if options.RefreshInterval <= 0 {
+		options.RefreshInterval = defaultRefreshInterval
+	}
+	if options.StaleAfter <= 0 {
+		options.StaleAfter = defaultStaleAfter
+	}
+	return &Resolver{
+		repo:        repo,
+		serviceName: serviceName,
+		options:     options,
+		logger:      logger,
+		lastUsed:    make(map[string]time.Time),
+		now:         time.Now,
+	}
+}
+
+// Refresh reloads instances from service discovery regardless of cache age, joining a
+// refresh already in flight
+func (r *Resolver) Refresh(ctx context.Context) error {
+	r.mu.Lock()
+	call := r.refreshing
+	if call == nil {
+		call = &refreshCall{done: make(chan struct{})}
+		r.refreshing = call
+		go r.fetch(call)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fetch runs one discovery call and swaps its result in. It is detached from the caller's
+// context, so a caller giving up does not fail the others waiting on the same call.
+func (r *Resolver) fetch(call *refreshCall) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.options.RefreshInterval)
+	defer cancel()
+	instances, err := r.repo.DiscoverHealthy(ctx, r.serviceName, r.options.Filter)
+	if err != nil {
+		r.logger.WithError(err).WithField("service_name", r.serviceName).Warn("Failed to refresh service instances")
+		call.err = fmt.Errorf("failed to refresh service instances: %w", err)
+	}
+
+	r.mu.Lock()
+	if err == nil {
+		r.swapLocked(instances)
+	}
+	r.refreshing = nil
+	r.mu.Unlock()
+	close(call.done)
+}
+
+// swapLocked replaces the cached instances
+func (r *Resolver) swapLocked(instances []*interfaces.ServiceInfo) {
+	r.instances = instances
+	r.fetchedAt = r.now()
+
+	// Forget usage of instances that have gone, so churning service IDs do not accumulate
+	current := make(map[string]bool, len(instances))
+	for _, info := range instances {
+		current[info.ServiceID] = true
+	}
+	for serviceID := range r.lastUsed {
+		if !current[serviceID] {
+			delete(r.lastUsed, serviceID)
+		}
+	}
+}
+
+// ensureFresh refreshes the cache when it has expired. A failed refresh is only an error when
+// nothing has ever been fetched; otherwise the previous results stay in use.
+func (r *Resolver) ensureFresh(ctx context.Context) error {
+	r.mu.Lock()
+	expired := r.fetchedAt.IsZero() || r.now().Sub(r.fetchedAt) >= r.options.RefreshInterval
+	r.mu.Unlock()
+	if !expired {
+		return nil
+	}
+
+	err := r.Refresh(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil && r.fetchedAt.IsZero() {
+		return err
+	}
+	return nil
+}
+
+// Instances returns the cached, non-stale instances, refreshing the cache when it has expired.
+// If a refresh fails, the previous results are used until they are also stale.
+func (r *Resolver) Instances(ctx context.Context) ([]*interfaces.ServiceInfo, error) {
+	if err := r.ensureFresh(ctx); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.liveLocked(), nil
+}
+
+// liveLocked returns the cached instances whose heartbeat is not stale
+func (r *Resolver) liveLocked() []*interfaces.ServiceInfo {
+	now := r.now()
+	live := make([]*interfaces.ServiceInfo, 0, len(r.instances))
+	for _, info := range r.instances {
+		if now.Sub(info.LastHeartbeat) <= r.options.StaleAfter {
+			live = append(live, info)
+		}
+	}
+	return live
+}
+
+// Pick selects an instance using the configured strategy. The key is only used by
+// the consistent-hash strategy, typically with an account ID, so that requests for
+// the same key keep landing on the same instance while the instance set is stable.
+func (r *Resolver) Pick(ctx context.Context, key string) (*interfaces.ServiceInfo, error) {
+	if err := r.ensureFresh(ctx); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	instances := r.liveLocked()
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoInstances, r.serviceName)
+	}
+
+	var picked *interfaces.ServiceInfo
+	switch r.options.Strategy {
+	case StrategyRandom:
+		picked = instances[rand.IntN(len(instances))]
+	case StrategyLeastRecentlyUsed:
+		picked = r.leastRecentlyUsed(instances)
+	case StrategyConsistentHash:
+		picked = rendezvous(instances, key)
+	default:
+		picked = instances[r.next%uint64(len(instances))]
+		r.next++
+	}
+
+	r.lastUsed[picked.ServiceID] = r.now()
+	return picked, nil
+}
+
+// leastRecentlyUsed returns the instance picked longest ago, preferring never-used instances
+func (r *Resolver) leastRecentlyUsed(instances []*interfaces.ServiceInfo) *interfaces.ServiceInfo {
+	picked := instances[0]
+	pickedAt, pickedUsed := r.lastUsed[picked.ServiceID]
+	for _, info := range instances[1:] {
+		usedAt, used := r.lastUsed[info.ServiceID]
+		if !used && pickedUsed || used && pickedUsed && usedAt.Before(pickedAt) {
+			picked, pickedAt, pickedUsed = info, usedAt, used
+		}
+	}
+	return picked
+}
+
+// rendezvous selects the instance with the highest hash of key and service ID, so that only
+// keys owned by a removed instance move when the instance set changes
+func rendezvous(instances []*interfaces.ServiceInfo, key string) *interfaces.ServiceInfo {
+	var picked *interfaces.ServiceInfo
+	var best uint64
+	for _, info := range instances {
+		h := fnv.New64a()
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write([]byte(info.ServiceID))
+		if score := h.Sum64(); picked == nil || score > best {
+			picked, best = info, score
+		}
+	}
+	return picked
+}
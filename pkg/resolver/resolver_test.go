@@ -0,0 +1,222 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// fakeDiscovery serves a fixed instance list and counts discovery calls
+type fakeDiscovery struct {
+	interfaces.ServiceDiscoveryRepository
+	instances []*interfaces.ServiceInfo
+	err       error
+	calls     int
+}
+
+func (f *fakeDiscovery) DiscoverHealthy(ctx context.Context, serviceName string, filter *interfaces.ServiceFilter) ([]*interfaces.ServiceInfo, error) {
+	f.calls++
+	return f.instances, f.err
+}
+
+func newTestResolver(repo *fakeDiscovery, strategy Strategy, now time.Time) *Resolver {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	r := NewResolver(repo, "exchange-simulator", Options{Strategy: strategy, RefreshInterval: time.Minute, StaleAfter: time.Minute}, logger)
+	r.now = func() time.Time { return now }
+	return r
+}
+
+func testInstances(now time.Time, ids ...string) []*interfaces.ServiceInfo {
+	instances := []*interfaces.ServiceInfo{}
+	for _, id := range ids {
+		instances = append(instances, &interfaces.ServiceInfo{ServiceID: id, LastHeartbeat: now})
+	}
+	return instances
+}
+
+// TestResolverStrategies tests instance selection per strategy
+func TestResolverStrategies(t *testing.T) {
+	now := time.Now()
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+		keys     []string
+		expected []string
+	}{
+		{name: "round robin cycles", strategy: StrategyRoundRobin, keys: []string{"", "", "", ""}, expected: []string{"a", "b", "c", "a"}},
+		{name: "least recently used cycles", strategy: StrategyLeastRecentlyUsed, keys: []string{"", "", ""}, expected: []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeDiscovery{instances: testInstances(now, "a", "b", "c")}
+			r := newTestResolver(repo, tt.strategy, now)
+			for i, key := range tt.keys {
+				tick := now.Add(time.Duration(i) * time.Millisecond)
+				r.now = func() time.Time { return tick }
+				picked, err := r.Pick(ctx, key)
+				if err != nil {
+					t.Fatalf("Pick() unexpected error: %v", err)
+				}
+				if picked.ServiceID != tt.expected[i] {
+					t.Errorf("pick %d = %s, expected %s", i, picked.ServiceID, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+// TestResolverConsistentHash tests that keys stick to instances and only move when their instance leaves
+func TestResolverConsistentHash(t *testing.T) {
+	now := time.Now()
+	ctx := context.Background()
+	repo := &fakeDiscovery{instances: testInstances(now, "a", "b", "c")}
+	r := newTestResolver(repo, StrategyConsistentHash, now)
+
+	owners := map[string]string{}
+	for _, account := range []string{"acc-1", "acc-2", "acc-3", "acc-4", "acc-5", "acc-6"} {
+		first, _ := r.Pick(ctx, account)
+		second, _ := r.Pick(ctx, account)
+		if first.ServiceID != second.ServiceID {
+			t.Fatalf("account %s moved from %s to %s", account, first.ServiceID, second.ServiceID)
+		}
+		owners[account] = first.ServiceID
+	}
+
+	repo.instances = testInstances(now, "a", "c")
+	if err := r.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh() unexpected error: %v", err)
+	}
+	for account, owner := range owners {
+		picked, _ := r.Pick(ctx, account)
+		if owner != "b" && picked.ServiceID != owner {
+			t.Errorf("account %s moved from %s to %s although its instance remained", account, owner, picked.ServiceID)
+		}
+	}
+}
+
+// TestResolverCachingAndStaleness tests cache refresh and stale heartbeat exclusion
+func TestResolverCachingAndStaleness(t *testing.T) {
+	now := time.Now()
+	ctx := context.Background()
+	repo := &fakeDiscovery{instances: []*interfaces.ServiceInfo{
+		{ServiceID: "fresh", LastHeartbeat: now},
+		{ServiceID: "stale", LastHeartbeat: now.Add(-2 * time.Minute)},
+	}}
+	r := newTestResolver(repo, StrategyRoundRobin, now)
+
+	for i := 0; i < 3; i++ {
+		picked, err := r.Pick(ctx, "")
+		if err != nil {
+			t.Fatalf("Pick() unexpected error: %v", err)
+		}
+		if picked.ServiceID != "fresh" {
+			t.Errorf("picked stale instance %s", picked.ServiceID)
+		}
+	}
+	if repo.calls != 1 {
+		t.Errorf("discovery calls = %d, expected 1 while cache is fresh", repo.calls)
+	}
+
+	// After the refresh interval a failed refresh keeps serving the cached instances
+	repo.err = errors.New("redis unavailable")
+	r.now = func() time.Time { return now.Add(30 * time.Second).Add(time.Minute) }
+	if _, err := r.Pick(ctx, ""); !errors.Is(err, ErrNoInstances) {
+		t.Errorf("Pick() error = %v, expected ErrNoInstances once all cached heartbeats are stale", err)
+	}
+	if repo.calls != 2 {
+		t.Errorf("discovery calls = %d, expected 2 after cache expiry", repo.calls)
+	}
+}
+
+// TestResolverPrunesLastUsed tests that usage of instances gone from discovery is forgotten
+func TestResolverPrunesLastUsed(t *testing.T) {
+	now := time.Now()
+	ctx := context.Background()
+	repo := &fakeDiscovery{instances: testInstances(now, "a", "b")}
+	r := newTestResolver(repo, StrategyLeastRecentlyUsed, now)
+
+	for i := 0; i < 2; i++ {
+		if _, err := r.Pick(ctx, ""); err != nil {
+			t.Fatalf("Pick() unexpected error: %v", err)
+		}
+	}
+
+	repo.instances = testInstances(now, "b", "c")
+	if err := r.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh() unexpected error: %v", err)
+	}
+	if _, ok := r.lastUsed["a"]; ok || len(r.lastUsed) != 1 {
+		t.Errorf("lastUsed = %v, expected only b", r.lastUsed)
+	}
+}
+
+// blockingDiscovery holds each discovery call until released
+type blockingDiscovery struct {
+	interfaces.ServiceDiscoveryRepository
+	instances []*interfaces.ServiceInfo
+	release   chan struct{}
+	calls     atomic.Int32
+}
+
+func (b *blockingDiscovery) DiscoverHealthy(ctx context.Context, serviceName string, filter *interfaces.ServiceFilter) ([]*interfaces.ServiceInfo, error) {
+	b.calls.Add(1)
+	<-b.release
+	return b.instances, nil
+}
+
+// TestResolverSharedRefresh tests that concurrent callers share one discovery call made outside
+// the lock, and that a caller giving up on it falls back to the cached instances
+func TestResolverSharedRefresh(t *testing.T) {
+	now := time.Now()
+	repo := &blockingDiscovery{instances: testInstances(now, "a", "b"), release: make(chan struct{})}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	r := NewResolver(repo, "exchange-simulator", Options{RefreshInterval: time.Minute, StaleAfter: time.Hour}, logger)
+	var clock atomic.Int64
+	clock.Store(now.UnixNano())
+	r.now = func() time.Time { return time.Unix(0, clock.Load()) }
+
+	close(repo.release)
+	if _, err := r.Pick(context.Background(), ""); err != nil {
+		t.Fatalf("Pick() unexpected error: %v", err)
+	}
+
+	// Expire the cache and hold the next discovery call
+	repo.release = make(chan struct{})
+	clock.Store(now.Add(2 * time.Minute).UnixNano())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := r.Pick(context.Background(), ""); err != nil {
+				t.Errorf("Pick() unexpected error: %v", err)
+			}
+		}()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	picked, err := r.Pick(ctx, "")
+	if err != nil || picked == nil {
+		t.Errorf("Pick() = %v, %v; expected a cached instance once the caller gave up", picked, err)
+	}
+
+	close(repo.release)
+	wg.Wait()
+	if calls := repo.calls.Load(); calls != 2 {
+		t.Errorf("discovery calls = %d, expected 2 with the refresh shared", calls)
+	}
+}
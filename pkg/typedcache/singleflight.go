@@ -0,0 +1,50 @@
+package typedcache
+
+import "sync"
+
+// call is an in-flight or completed load shared by concurrent callers
+type call[T any] struct {
+	wg    sync.WaitGroup
+	value T
+	err   error
+}
+
+// flightGroup deduplicates concurrent loads of the same key
+type flightGroup[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*call[T]
+}
+
+// do runs fn once per key at a time; concurrent callers for the same key wait for and share its result
+func (g *flightGroup[T]) do(key string, fn func() (T, error)) (T, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call[T])
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.value, c.err
+	}
+	c := &call[T]{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	// Release waiters and forget the call even if fn panics, so later loads of the key are
+	// not blocked forever; waiters get ErrLoaderPanicked while the panic unwinds this caller
+	returned := false
+	defer func() {
+		if !returned {
+			c.err = ErrLoaderPanicked
+		}
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+
+	c.value, c.err = fn()
+	returned = true
+	return c.value, c.err
+}
@@ -0,0 +1,159 @@
+package typedcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrNotFound is returned by GetOrLoad when the value does not exist, either because
+// the loader reported it or because a negative cache entry was found
+var ErrNotFound = errors.New("not found")
+
+// ErrLoaderPanicked is returned by GetOrLoad to callers that were waiting on another
+// caller's load when its loader panicked; the panic itself propagates to that caller
+var ErrLoaderPanicked = errors.New("loader panicked")
+
+// Stored values carry a one-byte marker so negative entries can share the key space
+const (
+	valueMarker    byte = 'v'
+	notFoundMarker byte = 'n'
+)
+
+// Options configures a TypedCache
+type Options struct {
+	// Codec for values; defaults to JSON
+	Codec Codec
+
+	// NegativeTTL caches not-found results for this long; zero disables negative caching
+	NegativeTTL time.Duration
+
+	// IsNotFound classifies loader errors as not-found; defaults to errors.Is(err, ErrNotFound)
+	IsNotFound func(err error) bool
+}
+
+// TypedCache wraps a CacheRepository with typed values, read-through loading,
+// negative caching and deduplication of concurrent loads
+type TypedCache[T any] struct {
+	cache   interfaces.CacheRepository
+	options Options
+	logger  *logrus.Logger
+	flights flightGroup[T]
+}
+
+func New[T any](cache interfaces.CacheRepository, options Options, logger *logrus.Logger) *TypedCache[T] {
+	if options.Codec == nil {
+		options.Codec = JSONCodec{}
+	}
+	if options.IsNotFound == nil {
+		options.IsNotFound = func(err error) bool { return errors.Is(err, ErrNotFound) }
+	}
+	return &TypedCache[T]{
+		cache:   cache,
+		options: options,
+		logger:  logger,
+	}
+}
+
+// Get returns the cached value and whether a positive entry was found.
+// Negative entries are reported as not found.
+func (c *TypedCache[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	raw, err := c.cache.Get(ctx, key)
+	if errors.Is(err, interfaces.ErrCacheMiss) {
+		return zero, false, nil
+	}
+	if err != nil {
+		return zero, false, err
+	}
+
+	value, negative, err := c.decode([]byte(raw))
+	if err != nil {
+		return zero, false, err
+	}
+	if negative {
+		return zero, false, nil
+	}
+	return value, true, nil
+}
+
+// Set stores a value with TTL
+func (c *TypedCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	data, err := c.options.Codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache value: %w", err)
+	}
+	return c.cache.Set(ctx, key, append([]byte{valueMarker}, data...), ttl)
+}
+
+// GetOrLoad returns the cached value or calls loader on a miss and caches its result.
+// Concurrent misses for the same key share a single loader call, which runs with the
+// context of the first caller. Cache failures are logged and fall through to the loader.
+func (c *TypedCache[T]) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+
+	raw, err := c.cache.Get(ctx, key)
+	switch {
+	case err == nil:
+		value, negative, decodeErr := c.decode([]byte(raw))
+		if decodeErr == nil {
+			if negative {
+				return zero, ErrNotFound
+			}
+			return value, nil
+		}
+		c.logger.WithError(decodeErr).WithField("key", key).Warn("Failed to decode cached value, reloading")
+	case !errors.Is(err, interfaces.ErrCacheMiss):
+		c.logger.WithError(err).WithField("key", key).Warn("Cache read failed, loading from source")
+	}
+
+	return c.flights.do(key, func() (T, error) {
+		value, err := loader(ctx)
+		if err != nil {
+			if c.options.IsNotFound(err) {
+				if c.options.NegativeTTL > 0 {
+					if setErr := c.cache.Set(ctx, key, []byte{notFoundMarker}, c.options.NegativeTTL); setErr != nil {
+						c.logger.WithError(setErr).WithField("key", key).Warn("Failed to cache not-found result")
+					}
+				}
+				if errors.Is(err, ErrNotFound) {
+					return zero, err
+				}
+				return zero, fmt.Errorf("%w: %w", ErrNotFound, err)
+			}
+			return zero, err
+		}
+
+		if err := c.Set(ctx, key, value, ttl); err != nil {
+			c.logger.WithError(err).WithField("key", key).Warn("Failed to cache loaded value")
+		}
+		return value, nil
+	})
+}
+
+// Delete removes a cached value or negative entry
+func (c *TypedCache[T]) Delete(ctx context.Context, key string) error {
+	return c.cache.Delete(ctx, key)
+}
+
+func (c *TypedCache[T]) decode(data []byte) (T, bool, error) {
+	var value T
+	if len(data) == 0 {
+		return value, false, fmt.Errorf("empty cache value")
+	}
+	switch data[0] {
+	case notFoundMarker:
+		return value, true, nil
+	case valueMarker:
+		if err := c.options.Codec.Unmarshal(data[1:], &value); err != nil {
+			return value, false, fmt.Errorf("failed to decode cache value: %w", err)
+		}
+		return value, false, nil
+	}
+	return value, false, fmt.Errorf("unrecognized cache value marker: %q", data[0])
+}
@@ -0,0 +1,229 @@
+package typedcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/interfaces"
+	"github.com/quantfidential/trading-ecosystem/exchange-data-adapter-go/pkg/models"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+// memoryCache is an in-process CacheRepository implementing the methods TypedCache uses
+type memoryCache struct {
+	interfaces.CacheRepository
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{values: map[string]string{}}
+}
+
+func (m *memoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = string(value.([]byte))
+	return nil
+}
+
+func (m *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.values[key]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", interfaces.ErrCacheMiss, key)
+	}
+	return value, nil
+}
+
+func (m *memoryCache) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.values, key)
+	return nil
+}
+
+func newTestLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// TestTypedCacheCodecs tests round trips through each codec
+func TestTypedCacheCodecs(t *testing.T) {
+	codecs := map[string]Codec{"json": JSONCodec{}, "binary": BinaryCodec{}}
+	balance := models.Balance{
+		BalanceID:        "bal-1",
+		AccountID:        "acc-1",
+		Symbol:           "BTC",
+		AvailableBalance: decimal.RequireFromString("1.25"),
+		LockedBalance:    decimal.RequireFromString("0.5"),
+	}
+
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			cache := New[models.Balance](newMemoryCache(), Options{Codec: codec}, newTestLogger())
+			ctx := context.Background()
+
+			if err := cache.Set(ctx, "balance:acc-1:BTC", balance, time.Minute); err != nil {
+				t.Fatalf("Set() unexpected error: %v", err)
+			}
+			result, found, err := cache.Get(ctx, "balance:acc-1:BTC")
+			if err != nil || !found {
+				t.Fatalf("Get() = found %v, err %v", found, err)
+			}
+			if result.BalanceID != balance.BalanceID || !result.AvailableBalance.Equal(balance.AvailableBalance) {
+				t.Errorf("Get() = %+v, expected %+v", result, balance)
+			}
+
+			if _, found, err := cache.Get(ctx, "balance:missing"); err != nil || found {
+				t.Errorf("Get() on miss = found %v, err %v", found, err)
+			}
+		})
+	}
+}
+
+// TestTypedCacheGetOrLoad tests read-through loading and negative caching
+func TestTypedCacheGetOrLoad(t *testing.T) {
+	ctx := context.Background()
+	cache := New[string](newMemoryCache(), Options{NegativeTTL: time.Minute}, newTestLogger())
+
+	loads := 0
+	loader := func(ctx context.Context) (string, error) {
+		loads++
+		return "ACTIVE", nil
+	}
+	for i := 0; i < 3; i++ {
+		value, err := cache.GetOrLoad(ctx, "account:acc-1", time.Minute, loader)
+		if err != nil || value != "ACTIVE" {
+			t.Fatalf("GetOrLoad() = %q, %v", value, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("loader called %d times, expected 1", loads)
+	}
+
+	missingLoads := 0
+	missing := func(ctx context.Context) (string, error) {
+		missingLoads++
+		return "", fmt.Errorf("account not found: acc-2")
+	}
+	cache.options.IsNotFound = func(err error) bool { return strings.Contains(err.Error(), "not found") }
+	for i := 0; i < 3; i++ {
+		if _, err := cache.GetOrLoad(ctx, "account:acc-2", time.Minute, missing); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("GetOrLoad() error = %v, expected ErrNotFound", err)
+		}
+	}
+	if missingLoads != 1 {
+		t.Errorf("not-found loader called %d times, expected 1 with negative caching", missingLoads)
+	}
+
+	failing := func(ctx context.Context) (string, error) {
+		return "", errors.New("connection refused")
+	}
+	if _, err := cache.GetOrLoad(ctx, "account:acc-3", time.Minute, failing); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("GetOrLoad() error = %v, expected loader error", err)
+	}
+	if _, found, _ := cache.Get(ctx, "account:acc-3"); found {
+		t.Error("loader failure should not be cached")
+	}
+}
+
+// TestTypedCacheSingleflight tests that concurrent misses share one loader call
+func TestTypedCacheSingleflight(t *testing.T) {
+	ctx := context.Background()
+	cache := New[int](newMemoryCache(), Options{}, newTestLogger())
+
+	var loads int32
+	release := make(chan struct{})
+	loader := func(ctx context.Context) (int, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan int, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, _ := cache.GetOrLoad(ctx, "hot-key", time.Minute, loader)
+			results <- value
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	for value := range results {
+		if value != 42 {
+			t.Errorf("GetOrLoad() = %d, expected 42", value)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("loader called %d times, expected 1", loads)
+	}
+}
+
+// TestTypedCacheSingleflightPanic tests that a panicking loader releases waiters and the key
+func TestTypedCacheSingleflightPanic(t *testing.T) {
+	ctx := context.Background()
+	cache := New[int](newMemoryCache(), Options{}, newTestLogger())
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	panicking := func(ctx context.Context) (int, error) {
+		close(started)
+		<-release
+		panic("loader failed")
+	}
+
+	recovered := make(chan interface{}, 1)
+	go func() {
+		defer func() { recovered <- recover() }()
+		_, _ = cache.GetOrLoad(ctx, "hot-key", time.Minute, panicking)
+	}()
+	<-started
+
+	waiterErr := make(chan error, 1)
+	go func() {
+		_, err := cache.GetOrLoad(ctx, "hot-key", time.Minute, func(ctx context.Context) (int, error) {
+			return 0, errors.New("waiter should share the in-flight load")
+		})
+		waiterErr <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	if r := <-recovered; r != "loader failed" {
+		t.Errorf("recovered %v, expected the loader panic", r)
+	}
+	select {
+	case err := <-waiterErr:
+		if !errors.Is(err, ErrLoaderPanicked) {
+			t.Errorf("waiter error = %v, expected ErrLoaderPanicked", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiter blocked after loader panic")
+	}
+
+	value, err := cache.GetOrLoad(ctx, "hot-key", time.Minute, func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+	if err != nil || value != 42 {
+		t.Errorf("GetOrLoad() after panic = %d, %v, expected 42", value, err)
+	}
+}
@@ -0,0 +1,20 @@
+-- Materialized OHLCV candles aggregated from exchange.trades
+-- Populated by CandleRepository.Materialize / Refresh for hot symbols
+CREATE TABLE IF NOT EXISTS exchange.candles (
+    symbol VARCHAR(50) NOT NULL,
+    interval_seconds INTEGER NOT NULL CHECK (interval_seconds BETWEEN 1 AND 86400),
+    open_time TIMESTAMPTZ NOT NULL,
+    open DECIMAL(20,8) NOT NULL,
+    high DECIMAL(20,8) NOT NULL,
+    low DECIMAL(20,8) NOT NULL,
+    close DECIMAL(20,8) NOT NULL,
+    volume DECIMAL(28,8) NOT NULL,
+    trade_count BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (symbol, interval_seconds, open_time)
+);
+
+-- Range scans for on-the-fly aggregation
+CREATE INDEX IF NOT EXISTS idx_trades_symbol_executed_at ON exchange.trades (symbol, executed_at);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON exchange.candles TO exchange_adapter;
@@ -0,0 +1,7 @@
+-- GIN indexes backing MetadataFilter predicates (@> containment and ?& key existence)
+-- The default jsonb_ops operator class is required for key existence; jsonb_path_ops
+-- would be smaller but only supports containment.
+CREATE INDEX IF NOT EXISTS idx_accounts_metadata ON exchange.accounts USING GIN (metadata);
+CREATE INDEX IF NOT EXISTS idx_orders_metadata ON exchange.orders USING GIN (metadata);
+CREATE INDEX IF NOT EXISTS idx_trades_metadata ON exchange.trades USING GIN (metadata);
+CREATE INDEX IF NOT EXISTS idx_balances_metadata ON exchange.balances USING GIN (metadata);
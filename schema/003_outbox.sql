@@ -0,0 +1,26 @@
+-- Transactional outbox of domain events written with each repository mutation
+-- Enabled by OUTBOX_ENABLED; drained by OutboxRelay
+CREATE TABLE IF NOT EXISTS exchange.outbox (
+    event_id BIGSERIAL PRIMARY KEY,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMPTZ,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+
+-- Relay polling scans only unpublished events
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON exchange.outbox (event_id) WHERE published_at IS NULL;
+
+-- Dead-lettered events hold back the rest of their aggregate until their attempts are reset
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished_aggregate ON exchange.outbox (aggregate_type, aggregate_id, event_id)
+    WHERE published_at IS NULL;
+
+-- Retention cleanup of published events
+CREATE INDEX IF NOT EXISTS idx_outbox_published_at ON exchange.outbox (published_at) WHERE published_at IS NOT NULL;
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON exchange.outbox TO exchange_adapter;
+GRANT USAGE, SELECT ON SEQUENCE exchange.outbox_event_id_seq TO exchange_adapter;
@@ -0,0 +1,90 @@
+-- LISTEN/NOTIFY change feed for orders, trades and balances
+-- Channels are <schema>_<table>_changes (e.g. exchange_orders_changes); payloads carry keys
+-- and status only, staying well below the 8000 byte NOTIFY limit
+
+-- changed_at is stamped by the database on every insert and update and carried in the
+-- notification, so listeners catching up after a reconnect compare database timestamps with
+-- database timestamps rather than with the times the application writes
+ALTER TABLE exchange.orders ADD COLUMN IF NOT EXISTS changed_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE exchange.trades ADD COLUMN IF NOT EXISTS changed_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE exchange.balances ADD COLUMN IF NOT EXISTS changed_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- changed_xid is the writing transaction's ID. Listeners catch up by transaction rather than
+-- by time: every transaction below a snapshot's xmin has finished, so rows with changed_xid at
+-- or above an earlier xmin include every change committed since, however long its transaction
+-- ran. Rows written before this column existed keep 0 and are never caught up.
+ALTER TABLE exchange.orders ADD COLUMN IF NOT EXISTS changed_xid xid8 NOT NULL DEFAULT '0';
+ALTER TABLE exchange.trades ADD COLUMN IF NOT EXISTS changed_xid xid8 NOT NULL DEFAULT '0';
+ALTER TABLE exchange.balances ADD COLUMN IF NOT EXISTS changed_xid xid8 NOT NULL DEFAULT '0';
+
+CREATE OR REPLACE FUNCTION exchange.stamp_change() RETURNS trigger AS $$
+BEGIN
+    NEW.changed_at := now();
+    NEW.changed_xid := pg_current_xact_id();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS orders_stamp_change ON exchange.orders;
+CREATE TRIGGER orders_stamp_change BEFORE INSERT OR UPDATE ON exchange.orders
+    FOR EACH ROW EXECUTE FUNCTION exchange.stamp_change();
+
+DROP TRIGGER IF EXISTS trades_stamp_change ON exchange.trades;
+CREATE TRIGGER trades_stamp_change BEFORE INSERT OR UPDATE ON exchange.trades
+    FOR EACH ROW EXECUTE FUNCTION exchange.stamp_change();
+
+DROP TRIGGER IF EXISTS balances_stamp_change ON exchange.balances;
+CREATE TRIGGER balances_stamp_change BEFORE INSERT OR UPDATE ON exchange.balances
+    FOR EACH ROW EXECUTE FUNCTION exchange.stamp_change();
+
+CREATE OR REPLACE FUNCTION exchange.notify_change() RETURNS trigger AS $$
+DECLARE
+    new_row JSONB := to_jsonb(NEW);
+    previous_status TEXT;
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        previous_status := to_jsonb(OLD) ->> 'status';
+    END IF;
+
+    PERFORM pg_notify(
+        TG_TABLE_SCHEMA || '_' || TG_TABLE_NAME || '_changes',
+        jsonb_strip_nulls(jsonb_build_object(
+            'table', TG_TABLE_NAME,
+            'operation', TG_OP,
+            'id', new_row ->> TG_ARGV[0],
+            'account_id', new_row ->> 'account_id',
+            'symbol', new_row ->> 'symbol',
+            'order_id', new_row ->> 'order_id',
+            'status', new_row ->> 'status',
+            'previous_status', previous_status,
+            'changed_at', new_row -> 'changed_at'
+        ))::text
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS orders_notify_insert ON exchange.orders;
+CREATE TRIGGER orders_notify_insert AFTER INSERT ON exchange.orders
+    FOR EACH ROW EXECUTE FUNCTION exchange.notify_change('order_id');
+
+DROP TRIGGER IF EXISTS orders_notify_status ON exchange.orders;
+CREATE TRIGGER orders_notify_status AFTER UPDATE OF status ON exchange.orders
+    FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION exchange.notify_change('order_id');
+
+DROP TRIGGER IF EXISTS trades_notify_insert ON exchange.trades;
+CREATE TRIGGER trades_notify_insert AFTER INSERT ON exchange.trades
+    FOR EACH ROW EXECUTE FUNCTION exchange.notify_change('trade_id');
+
+DROP TRIGGER IF EXISTS balances_notify_change ON exchange.balances;
+CREATE TRIGGER balances_notify_change AFTER INSERT OR UPDATE OF available_balance, locked_balance ON exchange.balances
+    FOR EACH ROW EXECUTE FUNCTION exchange.notify_change('balance_id');
+
+-- Catch-up scans after a listener reconnects
+CREATE INDEX IF NOT EXISTS idx_orders_changed_at ON exchange.orders (changed_at);
+CREATE INDEX IF NOT EXISTS idx_trades_changed_at ON exchange.trades (changed_at);
+CREATE INDEX IF NOT EXISTS idx_balances_changed_at ON exchange.balances (changed_at);
+CREATE INDEX IF NOT EXISTS idx_orders_changed_xid ON exchange.orders (changed_xid);
+CREATE INDEX IF NOT EXISTS idx_trades_changed_xid ON exchange.trades (changed_xid);
+CREATE INDEX IF NOT EXISTS idx_balances_changed_xid ON exchange.balances (changed_xid);
@@ -0,0 +1,66 @@
+-- Append-only history of order state changes (OrderRepository.GetHistory / GetAsOf)
+-- Recorded by trigger so every write path, including COPY, is captured. CREATED events carry
+-- the full row; later events carry only the columns that changed, keyed as in the orders table.
+CREATE TABLE IF NOT EXISTS exchange.order_events (
+    event_id BIGSERIAL PRIMARY KEY,
+    order_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    changes JSONB NOT NULL,
+    causation_id VARCHAR(255),
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_events_order ON exchange.order_events (order_id, event_id);
+
+-- The causation ID is set per transaction by the adapter with
+-- set_config('exchange.causation_id', ..., true)
+CREATE OR REPLACE FUNCTION exchange.record_order_event() RETURNS trigger AS $$
+DECLARE
+    changed JSONB;
+    kind TEXT;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        changed := to_jsonb(NEW);
+        kind := 'CREATED';
+    ELSE
+        SELECT jsonb_object_agg(n.key, n.value) INTO changed
+        FROM jsonb_each(to_jsonb(NEW)) n
+        JOIN jsonb_each(to_jsonb(OLD)) o USING (key)
+        -- changed_at and changed_xid are restamped on every update (004_change_notify.sql) and
+        -- are not history
+        WHERE n.value IS DISTINCT FROM o.value AND n.key NOT IN ('changed_at', 'changed_xid');
+
+        IF changed IS NULL THEN
+            RETURN NULL;
+        END IF;
+
+        kind := CASE
+            WHEN NEW.status IS DISTINCT FROM OLD.status AND NEW.status = 'CANCELLED' THEN 'CANCELLED'
+            WHEN NEW.status IS DISTINCT FROM OLD.status THEN 'STATUS_CHANGED'
+            WHEN NEW.filled_quantity IS DISTINCT FROM OLD.filled_quantity THEN 'FILLED'
+            ELSE 'UPDATED'
+        END;
+    END IF;
+
+    INSERT INTO exchange.order_events (order_id, event_type, changes, causation_id)
+    VALUES (NEW.order_id, kind, changed,
+            NULLIF(current_setting('exchange.causation_id', true), ''));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS orders_record_event ON exchange.orders;
+CREATE TRIGGER orders_record_event AFTER INSERT OR UPDATE ON exchange.orders
+    FOR EACH ROW EXECUTE FUNCTION exchange.record_order_event();
+
+-- Orders that predate the history start from a snapshot of their current row, valid from their
+-- last update; earlier states were never recorded. Run after the trigger exists so no order is
+-- missed, and skip orders that already have history so the migration can be re-run.
+INSERT INTO exchange.order_events (order_id, event_type, changes, occurred_at)
+SELECT o.order_id, 'BACKFILLED', to_jsonb(o), o.updated_at
+FROM exchange.orders o
+WHERE NOT EXISTS (SELECT 1 FROM exchange.order_events e WHERE e.order_id = o.order_id);
+
+-- Append-only: no UPDATE or DELETE for the adapter role
+GRANT SELECT, INSERT ON exchange.order_events TO exchange_adapter;
+GRANT USAGE, SELECT ON SEQUENCE exchange.order_events_event_id_seq TO exchange_adapter;
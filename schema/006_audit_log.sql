@@ -0,0 +1,83 @@
+-- Immutable audit trail of account mutations (AuditRepository)
+-- Recorded by trigger with the actor and reason the adapter sets per transaction from the
+-- request context (set_config('exchange.actor' / 'exchange.reason', ..., true)). Before and
+-- after hold only the changed columns for updates and the whole row for creates and deletes.
+CREATE TABLE IF NOT EXISTS exchange.audit_log (
+    audit_id BIGSERIAL PRIMARY KEY,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    reason TEXT,
+    before JSONB,
+    after JSONB,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON exchange.audit_log (entity_type, entity_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON exchange.audit_log (actor, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_occurred_at ON exchange.audit_log (occurred_at);
+
+-- TG_ARGV[0] is the entity type, TG_ARGV[1] the ID column. Without an actor in the context the
+-- database session user is recorded.
+CREATE OR REPLACE FUNCTION exchange.record_audit() RETURNS trigger AS $$
+DECLARE
+    old_row JSONB;
+    new_row JSONB;
+    before_values JSONB;
+    after_values JSONB;
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        old_row := to_jsonb(OLD);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        new_row := to_jsonb(NEW);
+    END IF;
+
+    IF TG_OP = 'UPDATE' THEN
+        SELECT COALESCE(jsonb_object_agg(o.key, o.value), '{}'::jsonb),
+               COALESCE(jsonb_object_agg(n.key, n.value), '{}'::jsonb)
+        INTO before_values, after_values
+        FROM jsonb_each(new_row) n
+        JOIN jsonb_each(old_row) o USING (key)
+        WHERE n.value IS DISTINCT FROM o.value;
+    ELSE
+        before_values := old_row;
+        after_values := new_row;
+    END IF;
+
+    INSERT INTO exchange.audit_log (entity_type, entity_id, action, actor, reason, before, after)
+    VALUES (
+        TG_ARGV[0],
+        COALESCE(new_row, old_row) ->> TG_ARGV[1],
+        CASE TG_OP WHEN 'INSERT' THEN 'CREATE' ELSE TG_OP END,
+        COALESCE(NULLIF(current_setting('exchange.actor', true), ''), session_user),
+        NULLIF(current_setting('exchange.reason', true), ''),
+        before_values,
+        after_values
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS accounts_audit ON exchange.accounts;
+CREATE TRIGGER accounts_audit AFTER INSERT OR UPDATE OR DELETE ON exchange.accounts
+    FOR EACH ROW EXECUTE FUNCTION exchange.record_audit('account', 'account_id');
+
+-- Entries can never be changed or removed, even by roles that own the table
+CREATE OR REPLACE FUNCTION exchange.reject_audit_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'exchange.audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_immutable ON exchange.audit_log;
+CREATE TRIGGER audit_log_immutable BEFORE UPDATE OR DELETE ON exchange.audit_log
+    FOR EACH ROW EXECUTE FUNCTION exchange.reject_audit_change();
+
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON exchange.audit_log;
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON exchange.audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION exchange.reject_audit_change();
+
+GRANT SELECT, INSERT ON exchange.audit_log TO exchange_adapter;
+GRANT USAGE, SELECT ON SEQUENCE exchange.audit_log_audit_id_seq TO exchange_adapter;
@@ -0,0 +1,31 @@
+-- Soft deletion of accounts (AccountRepository.Delete / Restore)
+-- Closed accounts keep their row so orders, trades and history still reference them
+ALTER TABLE exchange.accounts ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- Default reads only see live accounts
+CREATE INDEX IF NOT EXISTS idx_accounts_user_id_live ON exchange.accounts (user_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_accounts_deleted_at ON exchange.accounts (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Balance and order writes are refused for deleted accounts. The account row is locked FOR KEY
+-- SHARE, which waits behind a Delete in progress (it holds the row FOR UPDATE) and then sees its
+-- outcome, so no write can slip in behind Delete's balance and open-order checks. EX001 is
+-- mapped to ErrAccountDeleted by the repositories.
+CREATE OR REPLACE FUNCTION exchange.check_account_live() RETURNS trigger AS $$
+DECLARE
+    deleted TIMESTAMPTZ;
+BEGIN
+    SELECT deleted_at INTO deleted FROM exchange.accounts WHERE account_id = NEW.account_id FOR KEY SHARE;
+    IF deleted IS NOT NULL THEN
+        RAISE EXCEPTION 'account % is deleted', NEW.account_id USING ERRCODE = 'EX001';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS balances_account_live ON exchange.balances;
+CREATE TRIGGER balances_account_live BEFORE INSERT OR UPDATE ON exchange.balances
+    FOR EACH ROW EXECUTE FUNCTION exchange.check_account_live();
+
+DROP TRIGGER IF EXISTS orders_account_live ON exchange.orders;
+CREATE TRIGGER orders_account_live BEFORE INSERT OR UPDATE ON exchange.orders
+    FOR EACH ROW EXECUTE FUNCTION exchange.check_account_live();
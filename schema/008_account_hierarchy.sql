@@ -0,0 +1,65 @@
+-- Account hierarchy and internal transfers (AccountRepository.GetSubAccounts,
+-- BalanceRepository.Transfer / GetHierarchyBalances / GetLedger)
+ALTER TABLE exchange.accounts ADD COLUMN IF NOT EXISTS parent_account_id VARCHAR(255)
+    REFERENCES exchange.accounts (account_id);
+
+CREATE INDEX IF NOT EXISTS idx_accounts_parent_account_id ON exchange.accounts (parent_account_id)
+    WHERE parent_account_id IS NOT NULL;
+
+-- A hierarchy must stay a tree: reject parents that are the account itself or its descendants
+CREATE OR REPLACE FUNCTION exchange.check_account_parent() RETURNS trigger AS $$
+BEGIN
+    IF NEW.parent_account_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    IF EXISTS (
+        WITH RECURSIVE ancestors AS (
+            SELECT account_id, parent_account_id FROM exchange.accounts WHERE account_id = NEW.parent_account_id
+            UNION
+            SELECT a.account_id, a.parent_account_id
+            FROM exchange.accounts a
+            JOIN ancestors ON a.account_id = ancestors.parent_account_id
+        )
+        SELECT 1 FROM ancestors WHERE account_id = NEW.account_id
+    ) THEN
+        RAISE EXCEPTION 'account % cannot be a sub-account of its own descendant %', NEW.account_id, NEW.parent_account_id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS accounts_check_parent ON exchange.accounts;
+CREATE TRIGGER accounts_check_parent BEFORE INSERT OR UPDATE OF parent_account_id ON exchange.accounts
+    FOR EACH ROW EXECUTE FUNCTION exchange.check_account_parent();
+
+CREATE TABLE IF NOT EXISTS exchange.transfers (
+    transfer_id VARCHAR(255) PRIMARY KEY,
+    from_account_id VARCHAR(255) NOT NULL REFERENCES exchange.accounts (account_id),
+    to_account_id VARCHAR(255) NOT NULL REFERENCES exchange.accounts (account_id),
+    symbol VARCHAR(50) NOT NULL,
+    amount DECIMAL(20,8) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    metadata JSONB,
+    CHECK (from_account_id <> to_account_id)
+);
+
+-- One entry per balance movement; amounts are signed (debits negative) and entries of a
+-- transfer sum to zero
+CREATE TABLE IF NOT EXISTS exchange.ledger_entries (
+    entry_id BIGSERIAL PRIMARY KEY,
+    transfer_id VARCHAR(255) NOT NULL REFERENCES exchange.transfers (transfer_id),
+    account_id VARCHAR(255) NOT NULL REFERENCES exchange.accounts (account_id),
+    symbol VARCHAR(50) NOT NULL,
+    amount DECIMAL(20,8) NOT NULL,
+    balance_after DECIMAL(20,8) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account ON exchange.ledger_entries (account_id, symbol, entry_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_transfer ON exchange.ledger_entries (transfer_id);
+
+-- Transfers and ledger entries are append-only
+GRANT SELECT, INSERT ON exchange.transfers TO exchange_adapter;
+GRANT SELECT, INSERT ON exchange.ledger_entries TO exchange_adapter;
+GRANT USAGE, SELECT ON SEQUENCE exchange.ledger_entries_entry_id_seq TO exchange_adapter;